@@ -2,9 +2,13 @@ package ports
 
 import (
 	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
 )
 
-// TaskManagerV1HTTPClient interface for a Task Manager API v1 HTTP Client
-type TaskManagerV1HTTPClient interface {
+// TaskManagerV1GRPCClient interface for a Task Manager API v1 gRPC Client
+type TaskManagerV1GRPCClient interface {
+	Close() error
 	Health(ctx context.Context) error
+	GetByID(ctx context.Context, token, taskID string) (*pb.Task, error)
 }
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type grpcClient struct {
+	healthClient pb.HealthServiceClient
+	taskClient   pb.TaskServiceClient
+	conn         *grpc.ClientConn
+}
+
+// NewGRPCClient creates a new gRPC client for Task Manager API v1
+func NewGRPCClient(ctx context.Context, target string) (ports.TaskManagerV1GRPCClient, error) {
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	healthClient := pb.NewHealthServiceClient(conn)
+	taskClient := pb.NewTaskServiceClient(conn)
+
+	return &grpcClient{
+		healthClient: healthClient,
+		taskClient:   taskClient,
+		conn:         conn,
+	}, nil
+}
+
+// Close closes the gRPC connection
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
+
+// Health calls HealthCheck
+func (c *grpcClient) Health(ctx context.Context) error {
+	_, err := c.healthClient.HealthCheck(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf("HealthCheck call failed: %w", err)
+	}
+	return nil
+}
+
+// GetByID calls GetByID on behalf of the user of the received token
+func (c *grpcClient) GetByID(ctx context.Context, token, taskID string) (*pb.Task, error) {
+	md := metadata.Pairs("authorization", token)
+	mdCtx := metadata.NewOutgoingContext(ctx, md)
+
+	return c.taskClient.GetByID(mdCtx, &pb.GetTaskByIDRequest{Id: taskID})
+}
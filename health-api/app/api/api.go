@@ -30,7 +30,10 @@ type svs struct {
 func New(ctx context.Context, cfg config.Config) (a api) {
 	a.config = cfg
 
-	taskManagerClient := taskManagerClient.NewHTTPClient(cfg.TaskManagerURL)
+	taskManagerClient, err := taskManagerClient.NewGRPCClient(ctx, cfg.TaskManagerTarget)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
 
 	userManagementClient, err := userManagementClient.NewGRPCClient(ctx, cfg.UserManagementTarget)
 	if err != nil {
@@ -23,7 +23,7 @@ func main() {
 		Version              string `long:"ver" description:"Version" required:"true"`
 		Environment          string `long:"env" description:"Environment" choice:"local" choice:"prod" required:"true"`
 		HTTPPort             int    `long:"hport" description:"Running HTTP port" required:"true"`
-		TaskManagerURL       string `long:"tasksurl" description:"Base URL of the Task Manager API" required:"true"`
+		TaskManagerTarget    string `long:"tasksgrpc" description:"Base gRPC target of the Task Manager API" required:"true"`
 		UserManagementTarget string `long:"usersgrpc" description:"Base gRPC target of the User Management API" required:"true"`
 	}
 
@@ -32,7 +32,7 @@ func main() {
 		observability.Logger().Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
 	}
 
-	cfg, err := config.ReadConfig(opts.Version, opts.Environment, opts.HTTPPort, opts.TaskManagerURL, opts.UserManagementTarget, "config")
+	cfg, err := config.ReadConfig(opts.Version, opts.Environment, opts.HTTPPort, opts.TaskManagerTarget, opts.UserManagementTarget, "config")
 	if err != nil {
 		observability.Logger().Fatal(fmt.Errorf("cannot parse config file for env %s: %w", opts.Environment, err))
 	}
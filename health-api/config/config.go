@@ -24,7 +24,7 @@ type Config struct {
 }
 
 type externalURLs struct {
-	TaskManagerURL       string
+	TaskManagerTarget    string
 	UserManagementTarget string
 }
 
@@ -36,12 +36,12 @@ type config struct {
 // ReadConfig from the project´s JSON config files.
 // Default values are specified in the default configuration file, config/config.json
 // and can be overrided with values specified in the environment configuration files, config/config.{env}.json.
-func ReadConfig(version, env string, httpPort int, taskManagerURL, userManagementTarget, configPath string) (Config, error) {
+func ReadConfig(version, env string, httpPort int, taskManagerTarget, userManagementTarget, configPath string) (Config, error) {
 	var c Config
 	c.Version = version
 	c.Environment = env
 	c.HTTPPort = httpPort
-	c.TaskManagerURL = taskManagerURL
+	c.TaskManagerTarget = taskManagerTarget
 	c.UserManagementTarget = userManagementTarget
 
 	var cfg config
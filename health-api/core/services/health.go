@@ -15,12 +15,12 @@ import (
 // healthService adapter of an health service
 type healthService struct {
 	config               config.Config
-	taskManagerClient    commonPorts.TaskManagerV1HTTPClient
+	taskManagerClient    commonPorts.TaskManagerV1GRPCClient
 	userManagementClient commonPorts.UserManagementV1GRPCClient
 }
 
 // NewHealthService creates a new health service
-func NewHealthService(cfg config.Config, taskManagerClient commonPorts.TaskManagerV1HTTPClient, userManagementClient commonPorts.UserManagementV1GRPCClient) ports.HealthService {
+func NewHealthService(cfg config.Config, taskManagerClient commonPorts.TaskManagerV1GRPCClient, userManagementClient commonPorts.UserManagementV1GRPCClient) ports.HealthService {
 	return &healthService{
 		config:               cfg,
 		taskManagerClient:    taskManagerClient,
@@ -43,7 +43,7 @@ func TestHealthCheck(t *testing.T) {
 		{
 			name:               "Task Manager service is unhealthy",
 			userManagementErr:  nil,
-			taskManagerErr:     errors.New("HTTP 500"),
+			taskManagerErr:     errors.New("connection error"),
 			expectedError:      wrappers.NewServiceUnavailableErr(fmt.Errorf("service unavailable")),
 			expectedUserStatus: "OK",
 			expectedTaskStatus: "UNHEALTHY",
@@ -51,7 +51,7 @@ func TestHealthCheck(t *testing.T) {
 		{
 			name:               "Both services are unhealthy",
 			userManagementErr:  errors.New("connection error"),
-			taskManagerErr:     errors.New("HTTP 500"),
+			taskManagerErr:     errors.New("connection error"),
 			expectedError:      wrappers.NewServiceUnavailableErr(fmt.Errorf("service unavailable")),
 			expectedUserStatus: "UNHEALTHY",
 			expectedTaskStatus: "UNHEALTHY",
@@ -62,7 +62,7 @@ func TestHealthCheck(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
-			taskManagerClientMock := commonMocks.NewTaskManagerV1HTTPClient(t)
+			taskManagerClientMock := commonMocks.NewTaskManagerV1GRPCClient(t)
 
 			userManagementClientMock.On("Health", mock.Anything).Return(tc.userManagementErr).Once()
 			taskManagerClientMock.On("Health", mock.Anything).Return(tc.taskManagerErr).Once()
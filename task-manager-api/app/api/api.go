@@ -3,28 +3,47 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	userManagementClient "github.com/sergicanet9/go-microservices-demo/common/clients/usermanagementapi/v1"
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/async"
+	grpcHandlersV1 "github.com/sergicanet9/go-microservices-demo/task-manager-api/app/handlers/grpc/v1"
 	handlersV1 "github.com/sergicanet9/go-microservices-demo/task-manager-api/app/handlers/v1"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/realtime"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/relay"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/services"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/infrastructure/filesystem"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/infrastructure/logging"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/infrastructure/webhook"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
 	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/observability"
 	httpSwagger "github.com/swaggo/http-swagger"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
 )
 
 type api struct {
-	config   config.Config
-	services svs
+	config          config.Config
+	services        svs
+	idempotencyRepo ports.IdempotencyKeyRepository
 }
 
 type svs struct {
-	task ports.TaskService
+	task       ports.TaskService
+	webhook    ports.WebhookService
+	outbox     ports.OutboxService
+	label      ports.LabelService
+	project    ports.ProjectService
+	comment    ports.CommentService
+	attachment ports.AttachmentService
 }
 
 // New creates a new API
@@ -41,12 +60,85 @@ func New(ctx context.Context, cfg config.Config) (a api) {
 		observability.Logger().Fatal(err)
 	}
 
+	labelRepo, err := mongo.NewLabelRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	projectRepo, err := mongo.NewProjectRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	dependencyRepo, err := mongo.NewTaskDependencyRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	historyRepo, err := mongo.NewTaskHistoryRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	commentRepo, err := mongo.NewCommentRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	collaboratorRepo, err := mongo.NewTaskCollaboratorRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	attachmentRepo, err := mongo.NewAttachmentRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	a.idempotencyRepo, err = mongo.NewIdempotencyKeyRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	webhookRepo, err := mongo.NewWebhookRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	webhookDeliveryRepo, err := mongo.NewWebhookDeliveryRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	outboxRepo, err := mongo.NewOutboxRepository(ctx, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
+	blobStore, err := newBlobStore(a.config.Attachments, db)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+
 	userManagementClient, err := userManagementClient.NewGRPCClient(ctx, cfg.UserManagementTarget)
 	if err != nil {
 		observability.Logger().Fatal(err)
 	}
 
-	a.services.task = services.NewTaskService(a.config, taskRepo, userManagementClient)
+	transactions := mongo.NewTransactionManager(db)
+	a.services.webhook = services.NewWebhookService(a.config, webhookRepo, webhookDeliveryRepo, webhook.NewHTTPClient(a.config.Webhooks.Timeout.Duration, a.config.Webhooks.BlockedHosts))
+	taskWriter := services.NewTaskWriter(taskRepo, historyRepo, outboxRepo, transactions)
+	a.services.task = services.NewTaskService(a.config, taskRepo, labelRepo, projectRepo, dependencyRepo, historyRepo, commentRepo, attachmentRepo, blobStore, collaboratorRepo, taskWriter, transactions, userManagementClient)
+	a.services.comment = services.NewCommentService(a.config, commentRepo, taskRepo, collaboratorRepo, userManagementClient)
+	a.services.attachment = services.NewAttachmentService(a.config, attachmentRepo, taskRepo, collaboratorRepo, blobStore)
+	a.services.label = services.NewLabelService(a.config, labelRepo, taskRepo, taskWriter, transactions)
+	a.services.project = services.NewProjectService(a.config, projectRepo, taskRepo, taskWriter, transactions)
+
+	publisher, err := newEventPublisher(a.config.Outbox, a.services.webhook)
+	if err != nil {
+		observability.Logger().Fatal(err)
+	}
+	a.services.outbox = services.NewOutboxService(a.config, outboxRepo, publisher)
 
 	return a
 }
@@ -56,16 +148,46 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc) func() err
 		defer cancel()
 
 		router := mux.NewRouter()
-		router.Use(middlewares.Logger("/swagger", "/docs.swagger.json", "/grpcui"))
 		router.Use(middlewares.Recover)
 
+		// the routes streaming their bodies or upgrading the connection are mounted before and outside of the logged routes, as the logger
+		// reads the whole request body before calling the handler and buffers the whole response body until it returns, without hijacking
+		streamRouter := router.PathPrefix("/task-manager-api/v1").Subrouter()
+
 		v1Router := router.PathPrefix("/task-manager-api/v1").Subrouter()
+		v1Router.Use(middlewares.Logger("/swagger", "/docs.swagger.json", "/grpcui"))
 
 		healthHandler := handlersV1.NewHealthHandler(ctx, a.config)
 		handlersV1.SetHealthRoutes(v1Router, healthHandler)
 
-		taskHandler := handlersV1.NewTaskHandler(ctx, a.config, a.services.task)
+		taskHandler := handlersV1.NewTaskHandler(ctx, a.config, a.services.task, a.idempotencyRepo)
 		handlersV1.SetTaskRoutes(v1Router, taskHandler)
+		handlersV1.SetTaskStreamRoutes(streamRouter, taskHandler)
+
+		labelHandler := handlersV1.NewLabelHandler(ctx, a.config, a.services.label)
+		handlersV1.SetLabelRoutes(v1Router, labelHandler)
+
+		projectHandler := handlersV1.NewProjectHandler(ctx, a.config, a.services.project)
+		handlersV1.SetProjectRoutes(v1Router, projectHandler)
+
+		commentHandler := handlersV1.NewCommentHandler(ctx, a.config, a.services.comment)
+		handlersV1.SetCommentRoutes(v1Router, commentHandler)
+
+		attachmentHandler := handlersV1.NewAttachmentHandler(ctx, a.config, a.services.attachment)
+		handlersV1.SetAttachmentRoutes(streamRouter, attachmentHandler)
+
+		webhookHandler := handlersV1.NewWebhookHandler(ctx, a.config, a.services.webhook)
+		handlersV1.SetWebhookRoutes(v1Router, webhookHandler)
+
+		hub := realtime.NewHub(a.config, a.services.task, a.services.project)
+		hubDone := make(chan struct{})
+		go func() {
+			defer close(hubDone)
+			hub.Run(ctx)
+		}()
+
+		realtimeHandler := handlersV1.NewRealtimeHandler(ctx, a.config, hub)
+		handlersV1.SetRealtimeRoutes(streamRouter, realtimeHandler)
 
 		v1Router.PathPrefix("/swagger").HandlerFunc(httpSwagger.WrapHandler)
 
@@ -77,7 +199,77 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc) func() err
 		go shutdown(ctx, server)
 
 		observability.Logger().Printf("Server listening on HTTP port %d", a.config.HTTPPort)
-		return server.ListenAndServe()
+		err := server.ListenAndServe()
+
+		// the connections of the hub are hijacked from the server, so they are closed by the hub itself
+		cancel()
+		<-hubDone
+		return err
+	}
+}
+
+func (a *api) RunGRPC(ctx context.Context, cancel context.CancelFunc) func() error {
+	return func() error {
+		defer cancel()
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", a.config.GRPCPort))
+		if err != nil {
+			return err
+		}
+
+		policies := grpcHandlersV1.TaskMethodPolicies()
+		server := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				interceptors.UnaryLogger(),
+				interceptors.UnaryRecover(),
+				interceptors.UnaryJWT(a.config.JWTSecret, policies),
+			),
+			grpc.ChainStreamInterceptor(
+				interceptors.StreamLogger(),
+				interceptors.StreamRecover(),
+				interceptors.StreamJWT(a.config.JWTSecret, policies),
+			),
+		)
+
+		pb.RegisterHealthServiceServer(server, grpcHandlersV1.NewHealthHandler(ctx, a.config))
+		pb.RegisterTaskServiceServer(server, grpcHandlersV1.NewTaskHandler(ctx, a.config, a.services.task))
+
+		go shutdownGRPC(ctx, server)
+
+		observability.Logger().Printf("Server listening on gRPC port %d", a.config.GRPCPort)
+		return server.Serve(listener)
+	}
+}
+
+func (a *api) RunAsync(ctx context.Context, cancel context.CancelFunc) func() error {
+	return async.New(a.config, a.services.task, a.services.webhook).Run(ctx, cancel)
+}
+
+func (a *api) RunRelay(ctx context.Context, cancel context.CancelFunc) func() error {
+	return relay.New(a.config, a.services.outbox).Run(ctx, cancel)
+}
+
+// newEventPublisher creates the event publisher adapter selected in the outbox configuration
+func newEventPublisher(cfg config.Outbox, webhookService ports.WebhookService) (ports.EventPublisher, error) {
+	switch cfg.Publisher {
+	case "webhooks":
+		return webhookService, nil
+	case "log":
+		return logging.NewLogPublisher(observability.Logger()), nil
+	default:
+		return nil, fmt.Errorf("outbox publisher %q not supported", cfg.Publisher)
+	}
+}
+
+// newBlobStore creates the blob store adapter selected in the attachments configuration
+func newBlobStore(cfg config.Attachments, db *mongoDriver.Database) (ports.BlobStore, error) {
+	switch cfg.Storage {
+	case "gridfs":
+		return mongo.NewGridFSBlobStore(db)
+	case "local":
+		return filesystem.NewLocalBlobStore(cfg.LocalPath)
+	default:
+		return nil, fmt.Errorf("attachments storage %q not supported", cfg.Storage)
 	}
 }
 
@@ -86,3 +278,9 @@ func shutdown(ctx context.Context, server *http.Server) {
 	observability.Logger().Printf("Shutting down HTTP server gracefully...")
 	server.Shutdown(ctx)
 }
+
+func shutdownGRPC(ctx context.Context, server *grpc.Server) {
+	<-ctx.Done()
+	observability.Logger().Printf("Shutting down gRPC server gracefully...")
+	server.GracefulStop()
+}
@@ -0,0 +1,41 @@
+package async
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/async/rank"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/async/recurrence"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/async/trash"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/async/webhook"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+type async struct {
+	config         config.Config
+	taskService    ports.TaskService
+	webhookService ports.WebhookService
+}
+
+func New(cfg config.Config, taskService ports.TaskService, webhookService ports.WebhookService) async {
+	return async{
+		config:         cfg,
+		taskService:    taskService,
+		webhookService: webhookService,
+	}
+}
+
+func (a async) Run(ctx context.Context, cancel context.CancelFunc) func() error {
+	return func() error {
+		go recurrence.RunMaterializer(ctx, cancel, a.taskService, a.config.Async.Interval.Duration, a.config.Timeout.Duration)
+		go trash.RunPurger(ctx, cancel, a.taskService, a.config.Async.Interval.Duration, a.config.Timeout.Duration)
+		go rank.RunRebalancer(ctx, cancel, a.taskService, a.config.Async.Interval.Duration, a.config.Timeout.Duration)
+		go webhook.RunDispatcher(ctx, cancel, a.webhookService, a.config.Webhooks.Interval.Duration, time.Duration(a.config.Webhooks.BatchSize)*a.config.Webhooks.Timeout.Duration)
+
+		<-ctx.Done()
+		observability.Logger().Printf("Async process stopped")
+		return nil
+	}
+}
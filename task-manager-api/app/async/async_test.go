@@ -0,0 +1,48 @@
+package async
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNew_Ok checks that New creates a new async struct with the expected values
+func TestNew_Ok(t *testing.T) {
+	// Arrange
+	expectedConfig := config.Config{}
+	expectedTaskService := mocks.NewTaskService(t)
+	expectedWebhookService := mocks.NewWebhookService(t)
+
+	// Act
+	async := New(expectedConfig, expectedTaskService, expectedWebhookService)
+
+	// Assert
+	assert.Equal(t, expectedConfig, async.config)
+	assert.Equal(t, expectedTaskService, async.taskService)
+	assert.Equal(t, expectedWebhookService, async.webhookService)
+}
+
+// TestRun_ContextCancelled checks that Run finishes when the context gets cancelled
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Async.Interval.Duration = time.Second
+	cfg.Webhooks.Interval.Duration = time.Second
+	async := &async{
+		config:         cfg,
+		taskService:    mocks.NewTaskService(t),
+		webhookService: mocks.NewWebhookService(t),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+
+	// Act
+	errFunc := async.Run(ctx, cancel)
+
+	// Assert
+	assert.Nil(t, errFunc())
+}
@@ -0,0 +1,38 @@
+package rank
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// RunRebalancer periodically rewrites the ranks of the board columns that have grown too long until the context gets cancelled
+func RunRebalancer(ctx context.Context, cancel context.CancelFunc, service ports.TaskService, interval, timeout time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			observability.Logger().Printf("FATAL - recovered panic in rank rebalancer process: %v", rec)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		runCtx, runCancel := context.WithTimeout(ctx, timeout)
+		rebalanced, err := service.RebalanceRanks(runCtx)
+		runCancel()
+
+		if err != nil {
+			observability.Logger().Printf("rank rebalancer process - error: %s", err)
+			continue
+		}
+
+		observability.Logger().Printf("rank rebalancer process - columns rebalanced: %d", rebalanced)
+	}
+}
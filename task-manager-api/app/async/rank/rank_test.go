@@ -0,0 +1,31 @@
+package rank
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRunRebalancer_ContextCancelled checks that the rebalancer runs periodically until the context gets cancelled, even when a run fails
+func TestRunRebalancer_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	taskServiceMock := mocks.NewTaskService(t)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.RebalanceRanks), mock.Anything).Return(0, errors.New("rebalance-error")).Once()
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.RebalanceRanks), mock.Anything).Return(1, nil)
+
+	// Act
+	RunRebalancer(ctx, cancel, taskServiceMock, 10*time.Millisecond, time.Second)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
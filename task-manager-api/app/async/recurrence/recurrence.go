@@ -0,0 +1,38 @@
+package recurrence
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// RunMaterializer periodically creates the upcoming occurrences of the recurring tasks until the context gets cancelled
+func RunMaterializer(ctx context.Context, cancel context.CancelFunc, service ports.TaskService, interval, timeout time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			observability.Logger().Printf("FATAL - recovered panic in recurrence materializer process: %v", rec)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		runCtx, runCancel := context.WithTimeout(ctx, timeout)
+		created, err := service.MaterializeOccurrences(runCtx)
+		runCancel()
+
+		if err != nil {
+			observability.Logger().Printf("recurrence materializer process - error: %s", err)
+			continue
+		}
+
+		observability.Logger().Printf("recurrence materializer process - occurrences created: %d", created)
+	}
+}
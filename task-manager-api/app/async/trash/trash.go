@@ -0,0 +1,38 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// RunPurger periodically removes the tasks whose trash retention has expired until the context gets cancelled
+func RunPurger(ctx context.Context, cancel context.CancelFunc, service ports.TaskService, interval, timeout time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			observability.Logger().Printf("FATAL - recovered panic in trash purger process: %v", rec)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		runCtx, runCancel := context.WithTimeout(ctx, timeout)
+		purged, err := service.PurgeTrash(runCtx)
+		runCancel()
+
+		if err != nil {
+			observability.Logger().Printf("trash purger process - error: %s", err)
+			continue
+		}
+
+		observability.Logger().Printf("trash purger process - tasks purged: %d", purged)
+	}
+}
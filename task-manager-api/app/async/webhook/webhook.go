@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// RunDispatcher periodically attempts the due webhook deliveries until the context gets cancelled
+func RunDispatcher(ctx context.Context, cancel context.CancelFunc, service ports.WebhookService, interval, timeout time.Duration) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			observability.Logger().Printf("FATAL - recovered panic in webhook dispatcher process: %v", rec)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		runCtx, runCancel := context.WithTimeout(ctx, timeout)
+		attempted, err := service.DeliverDue(runCtx)
+		runCancel()
+
+		if err != nil {
+			observability.Logger().Printf("webhook dispatcher process - error: %s", err)
+			continue
+		}
+
+		if attempted > 0 {
+			observability.Logger().Printf("webhook dispatcher process - deliveries attempted: %d", attempted)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRunDispatcher_ContextCancelled checks that the dispatcher runs periodically until the context gets cancelled, even when a run fails
+func TestRunDispatcher_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	webhookServiceMock := mocks.NewWebhookService(t)
+	webhookServiceMock.On(testutils.FunctionName(t, ports.WebhookService.DeliverDue), mock.Anything).Return(0, errors.New("deliver-error")).Once()
+	webhookServiceMock.On(testutils.FunctionName(t, ports.WebhookService.DeliverDue), mock.Anything).Return(1, nil)
+
+	// Act
+	RunDispatcher(ctx, cancel, webhookServiceMock, 10*time.Millisecond, time.Second)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
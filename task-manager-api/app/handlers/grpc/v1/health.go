@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type healthHandler struct {
+	pb.UnimplementedHealthServiceServer
+	ctx context.Context
+	cfg config.Config
+}
+
+// NewHealthHandler creates a new gRPC health handler
+func NewHealthHandler(ctx context.Context, cfg config.Config) *healthHandler {
+	return &healthHandler{
+		ctx: ctx,
+		cfg: cfg,
+	}
+}
+
+// HealthCheck returns basic runtime information of the API when the service is up
+func (h *healthHandler) HealthCheck(ctx context.Context, _ *emptypb.Empty) (*pb.HealthCheckResponse, error) {
+	dsnValue := "***FILTERED***"
+	if h.cfg.Environment == "local" {
+		dsnValue = h.cfg.DSN
+	}
+
+	return &pb.HealthCheckResponse{
+		Version:     h.cfg.Version,
+		Environment: h.cfg.Environment,
+		HttpPort:    int32(h.cfg.HTTPPort),
+		GrpcPort:    int32(h.cfg.GRPCPort),
+		Dsn:         dsnValue,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TestHealthCheck_Ok checks that HealthCheck returns the runtime information of the API, filtering the DSN out of local environments
+func TestHealthCheck_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{Version: "v1", Environment: "prod", HTTPPort: 80, GRPCPort: 50051, DSN: "dsn"}
+	healthHandler := NewHealthHandler(context.Background(), cfg)
+
+	// Act
+	resp, err := healthHandler.HealthCheck(context.Background(), &emptypb.Empty{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", resp.Version)
+	assert.Equal(t, "prod", resp.Environment)
+	assert.Equal(t, int32(80), resp.HttpPort)
+	assert.Equal(t, int32(50051), resp.GrpcPort)
+	assert.Equal(t, "***FILTERED***", resp.Dsn)
+}
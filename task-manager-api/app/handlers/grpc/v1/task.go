@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type taskHandler struct {
+	pb.UnimplementedTaskServiceServer
+	ctx context.Context
+	cfg config.Config
+	svc ports.TaskService
+}
+
+// NewTaskHandler creates a new gRPC task handler
+func NewTaskHandler(ctx context.Context, cfg config.Config, svc ports.TaskService) *taskHandler {
+	return &taskHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// TaskMethodPolicies returns the JWT policies of the task service methods, which all require the user_id claim
+func TaskMethodPolicies() []interceptors.MethodPolicy {
+	var policies []interceptors.MethodPolicy
+	for _, method := range pb.TaskService_ServiceDesc.Methods {
+		policies = append(policies, interceptors.MethodPolicy{
+			MethodName:     fmt.Sprintf("/%s/%s", pb.TaskService_ServiceDesc.ServiceName, method.MethodName),
+			RequiredClaims: []string{"user_id"},
+		})
+	}
+	return policies
+}
+
+// Create creates a new task for the logged in user
+func (t *taskHandler) Create(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	createTaskReq := models.CreateTaskReq{
+		ProjectID:   req.ProjectId,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		Labels:      req.Labels,
+		DueAt:       toTime(req.DueAt),
+		Recurrence:  req.Recurrence,
+	}
+
+	createTaskResp, err := t.svc.Create(ctx, userID, createTaskReq, getToken(ctx))
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	return &pb.CreateTaskResponse{Id: createTaskResp.ID}, nil
+}
+
+// GetByID gets a task the logged in user can see
+func (t *taskHandler) GetByID(ctx context.Context, req *pb.GetTaskByIDRequest) (*pb.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	getTaskResp, err := t.svc.Get(ctx, userID, req.Id)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	return toTask(getTaskResp), nil
+}
+
+// List gets a page of the tasks of the logged in user
+func (t *taskHandler) List(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	getTasksReq := models.GetTasksReq{
+		Limit:     int(req.Limit),
+		Cursor:    req.Cursor,
+		Sort:      req.Sort,
+		ProjectID: req.ProjectId,
+	}
+
+	getTasksResp, err := t.svc.GetByUserID(ctx, userID, getTasksReq)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	resp := &pb.ListTasksResponse{NextCursor: getTasksResp.NextCursor}
+	for _, task := range getTasksResp.Tasks {
+		resp.Tasks = append(resp.Tasks, toTask(task))
+	}
+	return resp, nil
+}
+
+// Update fully replaces a task of the logged in user, which must still be at the received version
+func (t *taskHandler) Update(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	if req.Version == 0 {
+		return nil, toGRPC(taskWrappers.NewPreconditionRequiredErr(errors.New("version of the task is required")))
+	}
+
+	updateTaskReq := models.UpdateTaskReq{
+		ProjectID:   req.ProjectId,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		Labels:      req.Labels,
+		DueAt:       toTime(req.DueAt),
+		Recurrence:  req.Recurrence,
+	}
+
+	version, err := t.svc.Update(ctx, userID, req.Id, int(req.Version), updateTaskReq)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	return &pb.UpdateTaskResponse{Version: int32(version)}, nil
+}
+
+// Delete moves a task of the logged in user to the trash, which must still be at the received version
+func (t *taskHandler) Delete(ctx context.Context, req *pb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	if req.Version == 0 {
+		return nil, toGRPC(taskWrappers.NewPreconditionRequiredErr(errors.New("version of the task is required")))
+	}
+
+	err = t.svc.Delete(ctx, userID, req.Id, int(req.Version), req.Cascade)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// getUserID gets the user_id claim stored in the context by the JWT interceptor
+func getUserID(ctx context.Context) (string, error) {
+	claims, ok := ctx.Value(interceptors.ClaimsKey).(jwt.MapClaims)
+	if !ok {
+		return "", wrappers.NewUnauthorizedErr(errors.New("claims not found in context"))
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", wrappers.NewUnauthorizedErr(errors.New("user_id not found in claims"))
+	}
+	return userID, nil
+}
+
+// getToken gets the authorization metadata of the call, so that it can be forwarded to the User Management API
+func getToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if tokens := md.Get("authorization"); len(tokens) > 0 {
+		return tokens[0]
+	}
+	return ""
+}
+
+// toGRPC maps an error to a gRPC error the same way utils.ToGRPC does, mapping a task changed since the received version to failed precondition
+// and a missing version to invalid argument, as the call cannot succeed until it is fixed
+func toGRPC(err error) error {
+	switch {
+	case errors.Is(err, taskWrappers.PreconditionFailedErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, taskWrappers.PreconditionRequiredErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return utils.ToGRPC(err)
+	}
+}
+
+// toTime converts an optional timestamp
+func toTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toTimestamp converts an optional time
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// toTask converts a task response to its protobuf message
+func toTask(task models.GetTaskResp) *pb.Task {
+	return &pb.Task{
+		Id:          task.ID,
+		UserId:      task.UserID,
+		CreatorId:   task.CreatorID,
+		AssigneeId:  task.AssigneeID,
+		ProjectId:   task.ProjectID,
+		ParentId:    task.ParentID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		Labels:      task.Labels,
+		DueAt:       toTimestamp(task.DueAt),
+		Recurrence:  task.Recurrence,
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+		CompletedAt: toTimestamp(task.CompletedAt),
+		Version:     int32(task.Version),
+	}
+}
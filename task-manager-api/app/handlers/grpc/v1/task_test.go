@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-microservices-demo/common/proto/taskmanagerapi/v1/gen/go/pb"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newAuthenticatedContext creates the context of a call of the user-123 user, as the JWT interceptor leaves it
+func newAuthenticatedContext() context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	return context.WithValue(ctx, interceptors.ClaimsKey, jwt.MapClaims{"user_id": "user-123"})
+}
+
+// newTaskConfig creates a config with a timeout for the calls
+func newTaskConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Timeout.Duration = time.Second
+	return cfg
+}
+
+// TestTaskMethodPolicies_Ok checks that TaskMethodPolicies requires the user_id claim on every task service method
+func TestTaskMethodPolicies_Ok(t *testing.T) {
+	// Act
+	policies := TaskMethodPolicies()
+
+	// Assert
+	assert.Len(t, policies, len(pb.TaskService_ServiceDesc.Methods))
+	assert.Contains(t, policies, interceptors.MethodPolicy{MethodName: pb.TaskService_Create_FullMethodName, RequiredClaims: []string{"user_id"}})
+	assert.Contains(t, policies, interceptors.MethodPolicy{MethodName: pb.TaskService_Delete_FullMethodName, RequiredClaims: []string{"user_id"}})
+}
+
+// TestCreate_Ok checks that Create creates the task for the logged in user, forwarding the authorization metadata
+func TestCreate_Ok(t *testing.T) {
+	// Arrange
+	dueAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	taskService := mocks.NewTaskService(t)
+	taskService.On(testutils.FunctionName(t, ports.TaskService.Create), mock.Anything, "user-123", mock.MatchedBy(func(req models.CreateTaskReq) bool {
+		return req.Title == "title" && req.DueAt.Equal(dueAt)
+	}), "Bearer token").Return(models.CreateTaskResp{ID: "task-id"}, nil).Once()
+
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), taskService)
+
+	// Act
+	resp, err := taskHandler.Create(newAuthenticatedContext(), &pb.CreateTaskRequest{Title: "title", DueAt: timestamppb.New(dueAt)})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "task-id", resp.Id)
+}
+
+// TestGetByID_NotFound checks that GetByID maps the errors of the service to their gRPC codes
+func TestGetByID_NotFound(t *testing.T) {
+	// Arrange
+	taskService := mocks.NewTaskService(t)
+	taskService.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-123", "task-id").Return(models.GetTaskResp{}, wrappers.NewNonExistentErr(errors.New("task not found"))).Once()
+
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), taskService)
+
+	// Act
+	_, err := taskHandler.GetByID(newAuthenticatedContext(), &pb.GetTaskByIDRequest{Id: "task-id"})
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestList_Ok checks that List returns the page of tasks of the logged in user
+func TestList_Ok(t *testing.T) {
+	// Arrange
+	taskService := mocks.NewTaskService(t)
+	taskService.On(testutils.FunctionName(t, ports.TaskService.GetByUserID), mock.Anything, "user-123", models.GetTasksReq{Limit: 1, Sort: "title"}).Return(models.GetTasksResp{
+		Tasks:      []models.GetTaskResp{{ID: "task-id", Title: "title", Version: 2}},
+		NextCursor: "cursor",
+	}, nil).Once()
+
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), taskService)
+
+	// Act
+	resp, err := taskHandler.List(newAuthenticatedContext(), &pb.ListTasksRequest{Limit: 1, Sort: "title"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Tasks, 1)
+	assert.Equal(t, "task-id", resp.Tasks[0].Id)
+	assert.Equal(t, int32(2), resp.Tasks[0].Version)
+	assert.Equal(t, "cursor", resp.NextCursor)
+}
+
+// TestUpdate_VersionRequired checks that Update returns an invalid argument error when the version of the task is not received
+func TestUpdate_VersionRequired(t *testing.T) {
+	// Arrange
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), mocks.NewTaskService(t))
+
+	// Act
+	_, err := taskHandler.Update(newAuthenticatedContext(), &pb.UpdateTaskRequest{Id: "task-id", Title: "title"})
+
+	// Assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestDelete_PreconditionFailed checks that Delete returns a failed precondition error when the task has changed since the received version
+func TestDelete_PreconditionFailed(t *testing.T) {
+	// Arrange
+	taskService := mocks.NewTaskService(t)
+	taskService.On(testutils.FunctionName(t, ports.TaskService.Delete), mock.Anything, "user-123", "task-id", 1, true).Return(taskWrappers.NewPreconditionFailedErr(errors.New("task has changed"))).Once()
+
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), taskService)
+
+	// Act
+	_, err := taskHandler.Delete(newAuthenticatedContext(), &pb.DeleteTaskRequest{Id: "task-id", Version: 1, Cascade: true})
+
+	// Assert
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+// TestDelete_NoClaims checks that Delete returns an unauthenticated error when the call has not been authenticated
+func TestDelete_NoClaims(t *testing.T) {
+	// Arrange
+	taskHandler := NewTaskHandler(context.Background(), newTaskConfig(), mocks.NewTaskService(t))
+
+	// Act
+	_, err := taskHandler.Delete(context.Background(), &pb.DeleteTaskRequest{Id: "task-id", Version: 1})
+
+	// Assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
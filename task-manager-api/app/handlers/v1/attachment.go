@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// multipartOverhead is the room given to the multipart boundaries and headers on top of the maximum attachment size
+const multipartOverhead = 1 << 20
+
+type attachmentHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(ctx context.Context, cfg config.Config, svc ports.AttachmentService) attachmentHandler {
+	return attachmentHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// SetAttachmentRoutes creates attachment routes
+func SetAttachmentRoutes(router *mux.Router, a attachmentHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(a.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/tasks/{id}/attachments", a.uploadAttachment).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/attachments", a.getAttachments).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/attachments/{attachmentId}", a.downloadAttachment).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/attachments/{attachmentId}", a.deleteAttachment).Methods(http.MethodDelete)
+}
+
+// @Summary Upload attachment
+// @Description Attaches a file to a task of the logged in user. The file size and content type must be within the configured limits.
+// @Tags Attachments
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param id path string true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} models.CreateAttachmentResp
+// @Router /tasks/{id}/attachments [post]
+func (a *attachmentHandler) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.cfg.Attachments.TransferTimeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.cfg.Attachments.MaxSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		errorResponse(w, wrappers.NewValidationErr(fmt.Errorf("multipart request not valid: %w", err)))
+		return
+	}
+
+	// the file is streamed to the service straight from its part, without buffering the whole request
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			errorResponse(w, wrappers.NewValidationErr(errors.New("multipart request must include a file part")))
+			return
+		}
+		if err != nil {
+			errorResponse(w, wrappers.NewValidationErr(fmt.Errorf("multipart request not valid: %w", err)))
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		defer part.Close()
+
+		uploadAttachmentReq := models.UploadAttachmentReq{
+			FileName:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+		}
+
+		var params = mux.Vars(r)
+		createAttachmentResp, err := a.svc.Upload(ctx, userID, params["id"], uploadAttachmentReq, part)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				err = wrappers.NewValidationErr(fmt.Errorf("attachment exceeds the maximum size of %d bytes", a.cfg.Attachments.MaxSize))
+			}
+			errorResponse(w, err)
+			return
+		}
+		utils.SuccessResponse(w, http.StatusCreated, createAttachmentResp)
+		return
+	}
+}
+
+// @Summary Get attachments
+// @Description Gets the attachments of a task of the logged in user, the oldest first
+// @Tags Attachments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.GetAttachmentResp
+// @Router /tasks/{id}/attachments [get]
+func (a *attachmentHandler) getAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getAttachmentsResp, err := a.svc.GetByTaskID(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getAttachmentsResp)
+}
+
+// @Summary Download attachment
+// @Description Downloads the content of an attachment of a task of the logged in user
+// @Tags Attachments
+// @Security Bearer
+// @Produce octet-stream
+// @Param id path string true "Task ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {file} file
+// @Router /tasks/{id}/attachments/{attachmentId} [get]
+func (a *attachmentHandler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.cfg.Attachments.TransferTimeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	attachment, content, err := a.svc.Download(ctx, userID, params["id"], params["attachmentId"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+// @Summary Delete attachment
+// @Description Deletes an attachment of a task of the logged in user, along with its content
+// @Tags Attachments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200
+// @Router /tasks/{id}/attachments/{attachmentId} [delete]
+func (a *attachmentHandler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = a.svc.Delete(ctx, userID, params["id"], params["attachmentId"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+type commentHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(ctx context.Context, cfg config.Config, svc ports.CommentService) commentHandler {
+	return commentHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// SetCommentRoutes creates comment routes
+func SetCommentRoutes(router *mux.Router, c commentHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(c.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/tasks/{id}/comments", c.createComment).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/comments", c.getComments).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/comments/{commentId}", c.updateComment).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/tasks/{id}/comments/{commentId}", c.deleteComment).Methods(http.MethodDelete)
+}
+
+// @Summary Create comment
+// @Description Posts a comment on a task of the logged in user
+// @Tags Comments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param comment body models.CreateCommentReq true "Create Comment Request"
+// @Success 201 {object} models.CreateCommentResp
+// @Router /tasks/{id}/comments [post]
+func (c *commentHandler) createComment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var createCommentReq models.CreateCommentReq
+	err = json.Unmarshal(body, &createCommentReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	createCommentResp, err := c.svc.Create(ctx, userID, params["id"], createCommentReq, r.Header.Get("Authorization"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, createCommentResp)
+}
+
+// @Summary Get comments
+// @Description Gets a page of the comments of a task of the logged in user, the oldest first. Use the returned next_cursor to get the following page.
+// @Tags Comments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param limit query int false "Maximum number of comments in the page"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.GetCommentsResp
+// @Router /tasks/{id}/comments [get]
+func (c *commentHandler) getComments(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var getCommentsReq models.GetCommentsReq
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		getCommentsReq.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			errorResponse(w, wrappers.NewValidationErr(fmt.Errorf("invalid limit value: %w", err)))
+			return
+		}
+	}
+	getCommentsReq.Cursor = r.URL.Query().Get("cursor")
+
+	var params = mux.Vars(r)
+	getCommentsResp, err := c.svc.GetByTaskID(ctx, userID, params["id"], getCommentsReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getCommentsResp)
+}
+
+// @Summary Update comment
+// @Description Edits a comment of the logged in user
+// @Tags Comments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param commentId path string true "Comment ID"
+// @Param comment body models.UpdateCommentReq true "Update Comment Request"
+// @Success 200
+// @Router /tasks/{id}/comments/{commentId} [put]
+func (c *commentHandler) updateComment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var updateCommentReq models.UpdateCommentReq
+	err = json.Unmarshal(body, &updateCommentReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = c.svc.Update(ctx, userID, params["id"], params["commentId"], updateCommentReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Delete comment
+// @Description Deletes a comment of the logged in user
+// @Tags Comments
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200
+// @Router /tasks/{id}/comments/{commentId} [delete]
+func (c *commentHandler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = c.svc.Delete(ctx, userID, params["id"], params["commentId"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// errorResponse writes a JSON-encoded error message to the client with the status code errorStatus maps the error to,
+// used by every handler instead of utils.ErrorResponse, which does not know about the precondition errors
+func errorResponse(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errorStatus(err))
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// errorStatus maps an error to its HTTP status code
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, taskWrappers.PreconditionFailedErr):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, taskWrappers.PreconditionRequiredErr):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, wrappers.ValidationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, wrappers.NonExistentErr):
+		return http.StatusNotFound
+	case errors.Is(err, wrappers.UnauthorizedErr):
+		return http.StatusUnauthorized
+	case errors.Is(err, wrappers.UnauthenticatedErr):
+		return http.StatusForbidden
+	case errors.Is(err, wrappers.ServiceUnavailableErr):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorResponse_Ok checks that errorResponse writes the error message with the status code mapped from the error type
+func TestErrorResponse_Ok(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{"precondition failed", taskWrappers.NewPreconditionFailedErr(errors.New("error")), http.StatusPreconditionFailed},
+		{"precondition required", taskWrappers.NewPreconditionRequiredErr(errors.New("error")), http.StatusPreconditionRequired},
+		{"validation", wrappers.NewValidationErr(errors.New("error")), http.StatusBadRequest},
+		{"non existent", wrappers.NewNonExistentErr(errors.New("error")), http.StatusNotFound},
+		{"unauthorized", wrappers.NewUnauthorizedErr(errors.New("error")), http.StatusUnauthorized},
+		{"unauthenticated", wrappers.NewUnauthenticatedErr(errors.New("error")), http.StatusForbidden},
+		{"service unavailable", wrappers.NewServiceUnavailableErr(errors.New("error")), http.StatusServiceUnavailable},
+		{"unknown", errors.New("error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rr := httptest.NewRecorder()
+
+			// Act
+			errorResponse(rr, tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+			var response map[string]string
+			_ = json.NewDecoder(rr.Body).Decode(&response)
+			assert.Equal(t, map[string]string{"error": tt.err.Error()}, response)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+)
+
+type labelHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.LabelService
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(ctx context.Context, cfg config.Config, svc ports.LabelService) labelHandler {
+	return labelHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// SetLabelRoutes creates label routes
+func SetLabelRoutes(router *mux.Router, l labelHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(l.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/labels", l.createLabel).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/labels", l.getLabels).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/labels/{id}", l.renameLabel).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/labels/{id}", l.deleteLabel).Methods(http.MethodDelete)
+}
+
+// @Summary Create label
+// @Description Adds a new label to the catalogue of the logged in user
+// @Tags Labels
+// @Security Bearer
+// @Param label body models.CreateLabelReq true "Create Label Request"
+// @Success 201 {object} models.CreateLabelResp
+// @Router /labels [post]
+func (l *labelHandler) createLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(l.ctx, l.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var createLabelReq models.CreateLabelReq
+	err = json.Unmarshal(body, &createLabelReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	createLabelResp, err := l.svc.Create(ctx, userID, createLabelReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, createLabelResp)
+}
+
+// @Summary Get labels
+// @Description Gets the label catalogue of the logged in user
+// @Tags Labels
+// @Security Bearer
+// @Success 200 {array} models.GetLabelResp
+// @Router /labels [get]
+func (l *labelHandler) getLabels(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(l.ctx, l.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getLabelsResp, err := l.svc.GetByUserID(ctx, userID)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getLabelsResp)
+}
+
+// @Summary Rename label
+// @Description Renames a label of the logged in user, renaming it as well in all the tasks where it is assigned
+// @Tags Labels
+// @Security Bearer
+// @Param id path string true "Label ID"
+// @Param label body models.RenameLabelReq true "Rename Label Request"
+// @Success 200
+// @Router /labels/{id} [put]
+func (l *labelHandler) renameLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(l.ctx, l.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var renameLabelReq models.RenameLabelReq
+	err = json.Unmarshal(body, &renameLabelReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = l.svc.Rename(ctx, userID, params["id"], renameLabelReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Delete label
+// @Description Deletes a label of the logged in user, removing it as well from all the tasks where it is assigned
+// @Tags Labels
+// @Security Bearer
+// @Param id path string true "Label ID"
+// @Success 200
+// @Router /labels/{id} [delete]
+func (l *labelHandler) deleteLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(l.ctx, l.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = l.svc.Delete(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+)
+
+type projectHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.ProjectService
+}
+
+// NewProjectHandler creates a new project handler
+func NewProjectHandler(ctx context.Context, cfg config.Config, svc ports.ProjectService) projectHandler {
+	return projectHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// SetProjectRoutes creates project routes
+func SetProjectRoutes(router *mux.Router, p projectHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(p.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/projects", p.createProject).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/projects", p.getProjects).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/projects/{id}", p.getProject).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/projects/{id}", p.updateProject).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/projects/{id}", p.deleteProject).Methods(http.MethodDelete)
+}
+
+// @Summary Create project
+// @Description Creates a new project to group the tasks of the logged in user
+// @Tags Projects
+// @Security Bearer
+// @Param project body models.CreateProjectReq true "Create Project Request"
+// @Success 201 {object} models.CreateProjectResp
+// @Router /projects [post]
+func (p *projectHandler) createProject(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var createProjectReq models.CreateProjectReq
+	err = json.Unmarshal(body, &createProjectReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	createProjectResp, err := p.svc.Create(ctx, userID, createProjectReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, createProjectResp)
+}
+
+// @Summary Get projects
+// @Description Gets the projects of the logged in user, archived or not, with the number of tasks in each of them
+// @Tags Projects
+// @Security Bearer
+// @Success 200 {array} models.GetProjectResp
+// @Router /projects [get]
+func (p *projectHandler) getProjects(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getProjectsResp, err := p.svc.GetByUserID(ctx, userID)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getProjectsResp)
+}
+
+// @Summary Get project
+// @Description Gets a project of the logged in user with the number of tasks in it
+// @Tags Projects
+// @Security Bearer
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.GetProjectResp
+// @Router /projects/{id} [get]
+func (p *projectHandler) getProject(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getProjectResp, err := p.svc.GetByID(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getProjectResp)
+}
+
+// @Summary Update project
+// @Description Fully replaces a project of the logged in user. Archived projects keep their tasks but cannot receive new ones
+// @Tags Projects
+// @Security Bearer
+// @Param id path string true "Project ID"
+// @Param project body models.UpdateProjectReq true "Update Project Request"
+// @Success 200
+// @Router /projects/{id} [put]
+func (p *projectHandler) updateProject(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var updateProjectReq models.UpdateProjectReq
+	err = json.Unmarshal(body, &updateProjectReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = p.svc.Update(ctx, userID, params["id"], updateProjectReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Delete project
+// @Description Deletes a project of the logged in user, moving its tasks to the inbox or, when tasks is delete, to the trash
+// @Tags Projects
+// @Security Bearer
+// @Param id path string true "Project ID"
+// @Param tasks query string false "What to do with the tasks of the project" Enums(inbox, delete) default(inbox)
+// @Success 200
+// @Router /projects/{id} [delete]
+func (p *projectHandler) deleteProject(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = p.svc.Delete(ctx, userID, params["id"], r.URL.Query().Get("tasks"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
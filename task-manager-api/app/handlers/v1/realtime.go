@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	taskMiddlewares "github.com/sergicanet9/go-microservices-demo/task-manager-api/app/middlewares"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/app/realtime"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+)
+
+type realtimeHandler struct {
+	ctx context.Context
+	cfg config.Config
+	hub *realtime.Hub
+}
+
+// NewRealtimeHandler creates a new realtime handler
+func NewRealtimeHandler(ctx context.Context, cfg config.Config, hub *realtime.Hub) realtimeHandler {
+	return realtimeHandler{
+		ctx: ctx,
+		cfg: cfg,
+		hub: hub,
+	}
+}
+
+// SetRealtimeRoutes creates realtime routes
+func SetRealtimeRoutes(router *mux.Router, rh realtimeHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(taskMiddlewares.AccessTokenQuery, middlewares.JWT(rh.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/ws", rh.connect).Methods(http.MethodGet)
+}
+
+// @Summary Connect to realtime rooms
+// @Description Upgrades the call to a WebSocket of the logged in user, authenticated with the Authorization header or, from browsers, with the access_token query parameter.
+// @Description Send {"type":"subscribe","room":"task:{id}"} or {"type":"subscribe","room":"project:{id}"} to join the room of a task you can view or of a project of yours, and "unsubscribe" to leave it.
+// @Description Each room gets a presence message with the users viewing it whenever someone joins or leaves, and task.created, task.updated and task.deleted messages with the task whenever it changes
+// @Tags Realtime
+// @Security Bearer
+// @Param access_token query string false "JWT, when the Authorization header cannot be set"
+// @Success 101 {object} models.RealtimeMessage
+// @Router /ws [get]
+func (rh *realtimeHandler) connect(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	err = rh.hub.Serve(rh.ctx, w, r, userID)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+}
@@ -3,31 +3,42 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
+	taskMiddlewares "github.com/sergicanet9/go-microservices-demo/task-manager-api/app/middlewares"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
 	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
 	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 )
 
 type taskHandler struct {
-	ctx context.Context
-	cfg config.Config
-	svc ports.TaskService
+	ctx             context.Context
+	cfg             config.Config
+	svc             ports.TaskService
+	idempotencyRepo ports.IdempotencyKeyRepository
 }
 
 // NewTaskHandler creates a new task handler
-func NewTaskHandler(ctx context.Context, cfg config.Config, svc ports.TaskService) taskHandler {
+func NewTaskHandler(ctx context.Context, cfg config.Config, svc ports.TaskService, idempotencyRepo ports.IdempotencyKeyRepository) taskHandler {
 	return taskHandler{
-		ctx: ctx,
-		cfg: cfg,
-		svc: svc,
+		ctx:             ctx,
+		cfg:             cfg,
+		svc:             svc,
+		idempotencyRepo: idempotencyRepo,
 	}
 }
 
@@ -36,99 +47,1146 @@ func SetTaskRoutes(router *mux.Router, t taskHandler) {
 	secureRouter := router.PathPrefix("").Subrouter()
 	secureRouter.Use(middlewares.JWT(t.cfg.JWTSecret, "user_id"))
 
-	secureRouter.HandleFunc("/tasks", t.createTask).Methods(http.MethodPost)
+	idempotency := taskMiddlewares.Idempotency(t.idempotencyRepo, "user_id", t.cfg.Idempotency.TTL.Duration, t.cfg.Idempotency.LockTimeout.Duration)
+	secureRouter.Handle("/tasks", idempotency(http.HandlerFunc(t.createTask))).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks:batch", t.batchTasks).Methods(http.MethodPost)
 	secureRouter.HandleFunc("/tasks", t.getTasks).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/stats", t.getTaskStats).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/trash", t.getTrash).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/assigned", t.getAssignedTasks).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/projects/{id}/tasks", t.getProjectTasks).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}", t.getTask).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}", t.updateTask).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/tasks/{id}", t.patchTask).Methods(http.MethodPatch)
 	secureRouter.HandleFunc("/tasks/{id}", t.deleteTask).Methods(http.MethodDelete)
+	secureRouter.HandleFunc("/tasks/{id}/restore", t.restoreTask).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/transition", t.transitionTask).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/move", t.moveTask).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/assignee", t.assignTask).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/tasks/{id}/subtasks", t.createSubtask).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/subtasks", t.getSubtasks).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/subtasks/order", t.reorderSubtasks).Methods(http.MethodPut)
+	secureRouter.HandleFunc("/tasks/{id}/blockers", t.addBlocker).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/blockers/{blockerId}", t.removeBlocker).Methods(http.MethodDelete)
+	secureRouter.HandleFunc("/tasks/{id}/graph", t.getTaskGraph).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/history", t.getTaskHistory).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/collaborators", t.addCollaborator).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/tasks/{id}/collaborators", t.getCollaborators).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/tasks/{id}/collaborators/{userId}", t.removeCollaborator).Methods(http.MethodDelete)
+}
+
+// SetTaskStreamRoutes creates the task routes streaming their responses, which must not be wrapped by middlewares buffering the response
+func SetTaskStreamRoutes(router *mux.Router, t taskHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(t.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/tasks/stream", t.streamTasks).Methods(http.MethodGet)
 }
 
 // @Summary Create task
-// @Description Creates a new task for the logged in user
+// @Description Creates a new task for the logged in user.
+// @Description Retries sending the Idempotency-Key of a previous request get its stored response, marked with the Idempotent-Replayed header, and a 409 if their payload differs
+// @Tags Tasks
+// @Security Bearer
+// @Param user body models.CreateTaskReq true "Create Task Request"
+// @Param Idempotency-Key header string false "Key making retries replay the response of the first request instead of creating the task again"
+// @Success 201 {object} models.CreateTaskResp
+// @Router /tasks [post]
+func (t *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var createTaskReq models.CreateTaskReq
+	err = json.Unmarshal(body, &createTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	createTaskResp, err := t.svc.Create(ctx, userID, createTaskReq, r.Header.Get("Authorization"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, createTaskResp)
+}
+
+// @Summary Batch tasks
+// @Description Applies up to the configured maximum of create, update and delete operations over the tasks of the logged in user in a single request.
+// @Description Each operation is checked on its own, and its result carries the ID of its task or its error, along with the status code the single task endpoint would have returned.
+// @Description The operations passing their checks are applied in a single transaction, all at once or not at all: when the write of any of them is rejected,
+// @Description such as because its task has been modified meanwhile, none of them is applied and the request fails with the error of the rejected write
+// @Tags Tasks
+// @Security Bearer
+// @Param batch body models.BatchTasksReq true "Batch Tasks Request"
+// @Success 200 {object} models.BatchTasksResp
+// @Router /tasks:batch [post]
+func (t *taskHandler) batchTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var batchTasksReq models.BatchTasksReq
+	err = json.Unmarshal(body, &batchTasksReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	batchTasksResp, err := t.svc.Batch(ctx, userID, batchTasksReq, r.Header.Get("Authorization"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	for i := range batchTasksResp.Results {
+		result := &batchTasksResp.Results[i]
+		switch {
+		case result.Err != nil:
+			result.Status = errorStatus(result.Err)
+			result.Error = result.Err.Error()
+		case batchTasksReq.Operations[i].Op == models.BatchOpCreate:
+			result.Status = http.StatusCreated
+		default:
+			result.Status = http.StatusOK
+		}
+	}
+	utils.SuccessResponse(w, http.StatusOK, batchTasksResp)
+}
+
+// @Summary Get tasks
+// @Description Gets a page of tasks for the logged in user. Use the returned next_cursor to get the following page.
+// @Description When q is provided, the tasks matching the text are sorted by relevance, the matched fragments are highlighted in matches and sort and cursor are ignored
+// @Description When scope is shared, the tasks other users have shared with the logged in user are returned instead of their own ones. It cannot be combined with q
+// @Tags Tasks
+// @Security Bearer
+// @Param q query string false "Text to search in the title and description of the tasks"
+// @Param scope query string false "Whether to get the own tasks or the ones shared with the user" Enums(own, shared) default(own)
+// @Param overdue query bool false "Only open tasks whose due date has passed"
+// @Param due_before query string false "Only tasks due before the given RFC 3339 date"
+// @Param due_after query string false "Only tasks due after the given RFC 3339 date"
+// @Param label query string false "Comma-separated labels the tasks must have"
+// @Param label_match query string false "Whether the tasks must have any or all of the labels" Enums(any, all) default(any)
+// @Param sort query string false "Sort field, prefixed with - for descending order" Enums(due_at, -due_at, created_at, -created_at, title, -title, rank, -rank) default(due_at)
+// @Param limit query int false "Maximum number of tasks in the page"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.GetTasksResp
+// @Router /tasks [get]
+func (t *taskHandler) getTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksReq, err := getTasksQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var getTasksResp models.GetTasksResp
+	if getTasksReq.Query != "" {
+		getTasksResp, err = t.svc.Search(ctx, userID, getTasksReq)
+	} else {
+		getTasksResp, err = t.svc.GetByUserID(ctx, userID, getTasksReq)
+	}
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTasksResp)
+}
+
+// @Summary Get task stats
+// @Description Counts the tasks of the logged in user by status
+// @Tags Tasks
+// @Security Bearer
+// @Success 200 {object} models.GetTaskStatsResp
+// @Router /tasks/stats [get]
+func (t *taskHandler) getTaskStats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTaskStatsResp, err := t.svc.GetStats(ctx, userID)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTaskStatsResp)
+}
+
+// @Summary Stream task changes
+// @Description Streams the changes to the tasks of the logged in user as server-sent events named task.created, task.updated and task.deleted, with the task as data.
+// @Description Send the ID of the last event received as Last-Event-ID header to resume the stream right after it. While nothing changes, a comment is sent every heartbeat to keep the connection alive.
+// @Description Tasks removed permanently from the trash are not streamed
+// @Tags Tasks
+// @Security Bearer
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {object} models.GetTaskResp
+// @Router /tasks/stream [get]
+func (t *taskHandler) streamTasks(w http.ResponseWriter, r *http.Request) {
+	// the stream lasts until the client disconnects or the server shuts down
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	stream, err := t.svc.Stream(ctx, userID, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer stream.Close(context.Background())
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = controller.Flush()
+	for err == nil {
+		err = writeNextTaskChange(ctx, w, stream)
+		if err == nil {
+			err = controller.Flush()
+		}
+	}
+
+	if ctx.Err() == nil {
+		observability.Logger().Printf("task stream of UserID %s closed - error: %s", userID, err)
+	}
+}
+
+// @Summary Get trash
+// @Description Gets a page of the tasks of the logged in user that are in the trash, the most recently deleted first. Use the returned next_cursor to get the following page.
+// @Tags Tasks
+// @Security Bearer
+// @Param limit query int false "Maximum number of tasks in the page"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.GetTasksResp
+// @Router /tasks/trash [get]
+func (t *taskHandler) getTrash(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksReq, err := getTasksQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksResp, err := t.svc.GetTrash(ctx, userID, models.GetTasksReq{Limit: getTasksReq.Limit, Cursor: getTasksReq.Cursor})
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTasksResp)
+}
+
+// @Summary Get assigned tasks
+// @Description Gets a page of the tasks assigned to the logged in user, whoever owns them. Use the returned next_cursor to get the following page.
+// @Tags Tasks
+// @Security Bearer
+// @Param overdue query bool false "Only open tasks whose due date has passed"
+// @Param due_before query string false "Only tasks due before the given RFC 3339 date"
+// @Param due_after query string false "Only tasks due after the given RFC 3339 date"
+// @Param sort query string false "Sort field, prefixed with - for descending order" Enums(due_at, -due_at, created_at, -created_at, title, -title, rank, -rank) default(due_at)
+// @Param limit query int false "Maximum number of tasks in the page"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.GetTasksResp
+// @Router /tasks/assigned [get]
+func (t *taskHandler) getAssignedTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksReq, err := getTasksQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksResp, err := t.svc.GetAssigned(ctx, userID, models.GetTasksReq{
+		Overdue:   getTasksReq.Overdue,
+		DueBefore: getTasksReq.DueBefore,
+		DueAfter:  getTasksReq.DueAfter,
+		Sort:      getTasksReq.Sort,
+		Limit:     getTasksReq.Limit,
+		Cursor:    getTasksReq.Cursor,
+	})
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTasksResp)
+}
+
+// @Summary Get project tasks
+// @Description Gets a page of the tasks in a project of the logged in user, including its subtasks. Use the returned next_cursor to get the following page.
+// @Tags Projects
+// @Security Bearer
+// @Param id path string true "Project ID"
+// @Param overdue query bool false "Only open tasks whose due date has passed"
+// @Param due_before query string false "Only tasks due before the given RFC 3339 date"
+// @Param due_after query string false "Only tasks due after the given RFC 3339 date"
+// @Param label query string false "Comma-separated labels the tasks must have"
+// @Param label_match query string false "Whether the tasks must have any or all of the labels" Enums(any, all) default(any)
+// @Param sort query string false "Sort field, prefixed with - for descending order" Enums(due_at, -due_at, created_at, -created_at, title, -title, rank, -rank) default(due_at)
+// @Param limit query int false "Maximum number of tasks in the page"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.GetTasksResp
+// @Router /projects/{id}/tasks [get]
+func (t *taskHandler) getProjectTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getTasksReq, err := getTasksQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getTasksReq.ProjectID = params["id"]
+	getTasksReq.Query = ""
+	getTasksReq.Scope = ""
+
+	getTasksResp, err := t.svc.GetByUserID(ctx, userID, getTasksReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTasksResp)
+}
+
+// @Summary Get task
+// @Description Gets a task the logged in user owns or has been shared with. The ETag header carries its version, to be sent back in the If-Match header of the requests modifying it
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.GetTaskResp
+// @Header 200 {string} ETag "ETag of the current version of the task"
+// @Router /tasks/{id} [get]
+func (t *taskHandler) getTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getTaskResp, err := t.svc.Get(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(getTaskResp.Version))
+	utils.SuccessResponse(w, http.StatusOK, getTaskResp)
+}
+
+// @Summary Update task
+// @Description Fully replaces a task for the logged in user
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param task body models.UpdateTaskReq true "Update Task Request"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id} [put]
+func (t *taskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var updateTaskReq models.UpdateTaskReq
+	err = json.Unmarshal(body, &updateTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Update(ctx, userID, params["id"], version, updateTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Patch task
+// @Description Partially updates a task for the logged in user following JSON Merge Patch semantics
+// @Tags Tasks
+// @Security Bearer
+// @Accept application/merge-patch+json
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param task body models.UpdateTaskReq true "Merge Patch document with the fields to update"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id} [patch]
+func (t *taskHandler) patchTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var patchTaskReq models.PatchTaskReq
+	err = json.Unmarshal(body, &patchTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Patch(ctx, userID, params["id"], version, patchTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Delete task
+// @Description Moves a task of the logged in user to the trash, where it is kept until the retention period expires. A task with subtasks is only deleted, together with all its subtasks, when cascade is set
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param cascade query bool false "Delete the subtasks of the task as well"
+// @Success 200
+// @Router /tasks/{id} [delete]
+func (t *taskHandler) deleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var cascade bool
+	if value := r.URL.Query().Get("cascade"); value != "" {
+		cascade, err = strconv.ParseBool(value)
+		if err != nil {
+			errorResponse(w, wrappers.NewValidationErr(fmt.Errorf("invalid cascade value: %w", err)))
+			return
+		}
+	}
+
+	var params = mux.Vars(r)
+	err = t.svc.Delete(ctx, userID, params["id"], version, cascade)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Restore task
+// @Description Takes a task of the logged in user out of the trash, together with the subtasks deleted with it
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/restore [post]
+func (t *taskHandler) restoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Restore(ctx, userID, params["id"], version)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Transition task
+// @Description Moves a task of the logged in user to a new status. Allowed transitions: todo -> in_progress, blocked, done, archived; in_progress -> todo, blocked, done, archived; blocked -> todo, in_progress, archived; done -> in_progress, archived
+// @Description A task cannot be moved to done while any of its blockers is still open, and it is placed at the bottom of the board column of its new status
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param transition body models.TransitionTaskReq true "Transition Task Request"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/transition [post]
+func (t *taskHandler) transitionTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var transitionTaskReq models.TransitionTaskReq
+	err = json.Unmarshal(body, &transitionTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Transition(ctx, userID, params["id"], version, transitionTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Move task
+// @Description Places a task of the logged in user in a board column between two neighbours, given by before_id (the task above it) and after_id (the task below it), changing its status when the column differs following the transition rules
+// @Description Leave before_id empty to place the task at the top of the column, after_id empty to place it at the bottom, and both empty to append it to the column. Tasks are sorted in their column by rank
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param move body models.MoveTaskReq true "Move Task Request"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/move [post]
+func (t *taskHandler) moveTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var moveTaskReq models.MoveTaskReq
+	err = json.Unmarshal(body, &moveTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Move(ctx, userID, params["id"], version, moveTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Create subtask
+// @Description Creates a new task for the logged in user nested under the given task, placed after its current subtasks. Subtasks are completed as any other task, through the transition endpoint
 // @Tags Tasks
 // @Security Bearer
-// @Param user body models.CreateTaskReq true "Create Task Request"
+// @Param id path string true "Parent Task ID"
+// @Param task body models.CreateTaskReq true "Create Task Request"
 // @Success 201 {object} models.CreateTaskResp
-// @Router /tasks [post]
-func (t *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
+// @Router /tasks/{id}/subtasks [post]
+func (t *taskHandler) createSubtask(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
 	defer cancel()
 
 	userID, err := getUserID(r)
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 
 	var createTaskReq models.CreateTaskReq
 	err = json.Unmarshal(body, &createTaskReq)
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 
-	createTaskResp, err := t.svc.Create(ctx, userID, createTaskReq, r.Header.Get("Authorization"))
+	var params = mux.Vars(r)
+	createTaskResp, err := t.svc.CreateSubtask(ctx, userID, params["id"], createTaskReq, r.Header.Get("Authorization"))
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 	utils.SuccessResponse(w, http.StatusCreated, createTaskResp)
 }
 
-// @Summary Get tasks
-// @Description Gets all tasks for the logged in user
+// @Summary Get subtasks
+// @Description Gets the direct subtasks of a task of the logged in user, sorted by their position
 // @Tags Tasks
 // @Security Bearer
+// @Param id path string true "Parent Task ID"
 // @Success 200 {array} models.GetTaskResp
-// @Router /tasks [get]
-func (t *taskHandler) getTasks(w http.ResponseWriter, r *http.Request) {
+// @Router /tasks/{id}/subtasks [get]
+func (t *taskHandler) getSubtasks(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
 	defer cancel()
 
 	userID, err := getUserID(r)
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 
-	getTasksResp, err := t.svc.GetByUserID(ctx, userID)
+	var params = mux.Vars(r)
+	getSubtasksResp, err := t.svc.GetSubtasks(ctx, userID, params["id"])
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
-	utils.SuccessResponse(w, http.StatusOK, getTasksResp)
+	utils.SuccessResponse(w, http.StatusOK, getSubtasksResp)
 }
 
-// @Summary Delete task
-// @Description Deletes a tasks for the logged in user
+// @Summary Reorder subtasks
+// @Description Sets the order of the subtasks of a task of the logged in user. All the subtask IDs must be provided exactly once
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Parent Task ID"
+// @Param If-Match header string true "ETag of the version of the parent task the request is based on"
+// @Param order body models.ReorderSubtasksReq true "Reorder Subtasks Request"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the parent task"
+// @Router /tasks/{id}/subtasks/order [put]
+func (t *taskHandler) reorderSubtasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var reorderSubtasksReq models.ReorderSubtasksReq
+	err = json.Unmarshal(body, &reorderSubtasksReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.ReorderSubtasks(ctx, userID, params["id"], version, reorderSubtasksReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Add blocker
+// @Description Declares that a task of the logged in user is blocked by another of their tasks. Dependencies creating a cycle are rejected, and the blocker gets a new version too
 // @Tags Tasks
 // @Security Bearer
 // @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param blocker body models.AddBlockerReq true "Add Blocker Request"
 // @Success 200
-// @Router /tasks/{id} [delete]
-func (t *taskHandler) deleteTask(w http.ResponseWriter, r *http.Request) {
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/blockers [post]
+func (t *taskHandler) addBlocker(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var addBlockerReq models.AddBlockerReq
+	err = json.Unmarshal(body, &addBlockerReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.AddBlocker(ctx, userID, params["id"], version, addBlockerReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Remove blocker
+// @Description Removes the dependency between a task of the logged in user and one of its blockers
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param blockerId path string true "Blocker Task ID"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/blockers/{blockerId} [delete]
+func (t *taskHandler) removeBlocker(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.RemoveBlocker(ctx, userID, params["id"], version, params["blockerId"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Get task graph
+// @Description Gets the dependency graph of a task of the logged in user, with all the tasks that block it or are blocked by it, directly or transitively. Each edge goes from a blocker to the task it blocks
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.GetTaskGraphResp
+// @Router /tasks/{id}/graph [get]
+func (t *taskHandler) getTaskGraph(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getTaskGraphResp, err := t.svc.GetGraph(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTaskGraphResp)
+}
+
+// @Summary Get task history
+// @Description Gets the changes made to a task of the logged in user, the oldest first, with the actor, the timestamp and the changed fields of each change
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.GetTaskHistoryResp
+// @Router /tasks/{id}/history [get]
+func (t *taskHandler) getTaskHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getTaskHistoryResp, err := t.svc.GetHistory(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getTaskHistoryResp)
+}
+
+// @Summary Assign task
+// @Description Assigns a task of the logged in user to another user, who can then work on it as an editor. An empty assignee_id unassigns the task
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the version of the task the request is based on"
+// @Param assignment body models.AssignTaskReq true "Assign Task Request"
+// @Success 200
+// @Header 200 {string} ETag "ETag of the new version of the task"
+// @Router /tasks/{id}/assignee [put]
+func (t *taskHandler) assignTask(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	version, err := getIfMatchVersion(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var assignTaskReq models.AssignTaskReq
+	err = json.Unmarshal(body, &assignTaskReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	newVersion, err := t.svc.Assign(ctx, userID, params["id"], version, assignTaskReq, r.Header.Get("Authorization"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(newVersion))
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Add collaborator
+// @Description Shares a task of the logged in user, and its subtasks, with another user as a viewer or an editor. Adding an existing collaborator changes their role
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param collaborator body models.AddCollaboratorReq true "Add Collaborator Request"
+// @Success 200
+// @Router /tasks/{id}/collaborators [post]
+func (t *taskHandler) addCollaborator(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var addCollaboratorReq models.AddCollaboratorReq
+	err = json.Unmarshal(body, &addCollaboratorReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = t.svc.AddCollaborator(ctx, userID, params["id"], addCollaboratorReq, r.Header.Get("Authorization"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Get collaborators
+// @Description Gets the users a task has been shared with, along with their role
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.GetCollaboratorResp
+// @Router /tasks/{id}/collaborators [get]
+func (t *taskHandler) getCollaborators(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getCollaboratorsResp, err := t.svc.GetCollaborators(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getCollaboratorsResp)
+}
+
+// @Summary Remove collaborator
+// @Description Stops sharing a task of the logged in user with a collaborator. Collaborators can also remove themselves
+// @Tags Tasks
+// @Security Bearer
+// @Param id path string true "Task ID"
+// @Param userId path string true "Collaborator User ID"
+// @Success 200
+// @Router /tasks/{id}/collaborators/{userId} [delete]
+func (t *taskHandler) removeCollaborator(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(t.ctx, t.cfg.Timeout.Duration)
 	defer cancel()
 
 	userID, err := getUserID(r)
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 
 	var params = mux.Vars(r)
-	err = t.svc.Delete(ctx, userID, params["id"])
+	err = t.svc.RemoveCollaborator(ctx, userID, params["id"], params["userId"])
 	if err != nil {
-		utils.ErrorResponse(w, err)
+		errorResponse(w, err)
 		return
 	}
 	utils.SuccessResponse(w, http.StatusOK, nil)
 }
 
+func getTasksQuery(r *http.Request) (req models.GetTasksReq, err error) {
+	query := r.URL.Query()
+
+	if overdue := query.Get("overdue"); overdue != "" {
+		req.Overdue, err = strconv.ParseBool(overdue)
+		if err != nil {
+			return req, wrappers.NewValidationErr(fmt.Errorf("invalid overdue value: %w", err))
+		}
+	}
+
+	req.DueBefore, err = getTimeQueryParam(query, "due_before")
+	if err != nil {
+		return
+	}
+
+	req.DueAfter, err = getTimeQueryParam(query, "due_after")
+	if err != nil {
+		return
+	}
+
+	if labels := query.Get("label"); labels != "" {
+		req.Labels = strings.Split(labels, ",")
+	}
+	req.LabelMatch = query.Get("label_match")
+
+	if limit := query.Get("limit"); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return req, wrappers.NewValidationErr(fmt.Errorf("invalid limit value: %w", err))
+		}
+	}
+
+	req.Sort = query.Get("sort")
+	req.Cursor = query.Get("cursor")
+	req.Query = query.Get("q")
+	req.Scope = query.Get("scope")
+	return
+}
+
+// getIfMatchVersion gets the version of a task from the ETag received in the If-Match header, which the requests modifying a task require
+func getIfMatchVersion(r *http.Request) (int, error) {
+	value := r.Header.Get("If-Match")
+	if value == "" {
+		return 0, taskWrappers.NewPreconditionRequiredErr(errors.New("If-Match header with the ETag of the task is required"))
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		return 0, wrappers.NewValidationErr(fmt.Errorf("invalid If-Match value %s, expected the ETag of the task", value))
+	}
+	return version, nil
+}
+
+// versionETag builds the ETag of a version of a task
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func getTimeQueryParam(query url.Values, key string) (*time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("invalid %s value: %w", key, err))
+	}
+	return &t, nil
+}
+
 func getUserID(r *http.Request) (string, error) {
 	claimsValue := r.Context().Value(middlewares.ClaimsKey)
 	if claimsValue == nil {
@@ -152,3 +1210,29 @@ func getUserID(r *http.Request) (string, error) {
 
 	return userID, nil
 }
+
+// writeNextTaskChange waits for the next change of a task stream and writes it as a server-sent event,
+// or writes a heartbeat comment when no change arrives in time
+func writeNextTaskChange(ctx context.Context, w io.Writer, stream ports.TaskEventStream) error {
+	event, err := stream.Next(ctx)
+	if err != nil {
+		return err
+	}
+
+	if event == nil {
+		_, err = io.WriteString(w, ": heartbeat\n\n")
+		return err
+	}
+	return writeTaskChangeEvent(w, *event)
+}
+
+// writeTaskChangeEvent writes a task change as a server-sent event
+func writeTaskChangeEvent(w io.Writer, event models.TaskChangeEvent) error {
+	data, err := json.Marshal(event.Task)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+)
+
+type webhookHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(ctx context.Context, cfg config.Config, svc ports.WebhookService) webhookHandler {
+	return webhookHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// SetWebhookRoutes creates webhook routes
+func SetWebhookRoutes(router *mux.Router, wh webhookHandler) {
+	secureRouter := router.PathPrefix("").Subrouter()
+	secureRouter.Use(middlewares.JWT(wh.cfg.JWTSecret, "user_id"))
+
+	secureRouter.HandleFunc("/webhooks", wh.createWebhook).Methods(http.MethodPost)
+	secureRouter.HandleFunc("/webhooks", wh.getWebhooks).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/webhooks/{id}", wh.deleteWebhook).Methods(http.MethodDelete)
+	secureRouter.HandleFunc("/webhooks/{id}/dead-letters", wh.getDeadLetters).Methods(http.MethodGet)
+	secureRouter.HandleFunc("/webhooks/{id}/dead-letters/{deliveryId}/redeliver", wh.redeliver).Methods(http.MethodPost)
+}
+
+// @Summary Create webhook
+// @Description Registers a webhook of the logged in user, notified of the selected events on the tasks the user owns.
+// @Description Each delivery is a POST of a task event, signed in the X-Webhook-Signature header as t={unix time},v1={hex HMAC-SHA256 of "{unix time}.{body}" with the secret}.
+// @Description Failed deliveries are retried with exponential backoff and kept as dead letters once they run out of attempts. The secret is generated when not received, and only returned here
+// @Tags Webhooks
+// @Security Bearer
+// @Param webhook body models.CreateWebhookReq true "Create Webhook Request"
+// @Success 201 {object} models.CreateWebhookResp
+// @Router /webhooks [post]
+func (wh *webhookHandler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(wh.ctx, wh.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var createWebhookReq models.CreateWebhookReq
+	err = json.Unmarshal(body, &createWebhookReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	createWebhookResp, err := wh.svc.Create(ctx, userID, createWebhookReq)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, createWebhookResp)
+}
+
+// @Summary Get webhooks
+// @Description Gets the webhooks of the logged in user, without their secrets
+// @Tags Webhooks
+// @Security Bearer
+// @Success 200 {array} models.GetWebhookResp
+// @Router /webhooks [get]
+func (wh *webhookHandler) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(wh.ctx, wh.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	getWebhooksResp, err := wh.svc.GetByUserID(ctx, userID)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getWebhooksResp)
+}
+
+// @Summary Delete webhook
+// @Description Deletes a webhook of the logged in user, together with its pending deliveries and dead letters
+// @Tags Webhooks
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200
+// @Router /webhooks/{id} [delete]
+func (wh *webhookHandler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(wh.ctx, wh.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = wh.svc.Delete(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, nil)
+}
+
+// @Summary Get dead letters
+// @Description Gets the deliveries of a webhook of the logged in user that ran out of attempts
+// @Tags Webhooks
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Success 200 {array} models.GetWebhookDeliveryResp
+// @Router /webhooks/{id}/dead-letters [get]
+func (wh *webhookHandler) getDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(wh.ctx, wh.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	getDeadLettersResp, err := wh.svc.GetDeadLetters(ctx, userID, params["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, getDeadLettersResp)
+}
+
+// @Summary Redeliver dead letter
+// @Description Gives a dead letter of a webhook of the logged in user a new round of attempts, the first one as soon as possible
+// @Tags Webhooks
+// @Security Bearer
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202
+// @Router /webhooks/{id}/dead-letters/{deliveryId}/redeliver [post]
+func (wh *webhookHandler) redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(wh.ctx, wh.cfg.Timeout.Duration)
+	defer cancel()
+
+	userID, err := getUserID(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var params = mux.Vars(r)
+	err = wh.svc.Redeliver(ctx, userID, params["id"], params["deliveryId"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusAccepted, nil)
+}
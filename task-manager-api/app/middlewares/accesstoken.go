@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const AccessTokenQueryParam = "access_token"
+
+// AccessTokenQuery is an HTTP middleware that takes the bearer token from the access_token query parameter when the call has no Authorization header,
+// as browsers cannot set headers when opening a WebSocket. It has to be mounted before the JWT one, which validates the token as usual
+func AccessTokenQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get(AccessTokenQueryParam)
+		if token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
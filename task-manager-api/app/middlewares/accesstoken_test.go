@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAccessTokenQuery_Ok checks that AccessTokenQuery sets the Authorization header from the access_token query parameter
+func TestAccessTokenQuery_Ok(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "/ws?access_token=token", nil)
+
+	var authorization string
+	handler := AccessTokenQuery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Equal(t, "Bearer token", authorization)
+}
+
+// TestAccessTokenQuery_AuthorizationHeader checks that AccessTokenQuery keeps the Authorization header of the call when received
+func TestAccessTokenQuery_AuthorizationHeader(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodGet, "/ws?access_token=token", nil)
+	req.Header.Set("Authorization", "Bearer header-token")
+
+	var authorization string
+	handler := AccessTokenQuery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+
+	// Act
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	assert.Equal(t, "Bearer header-token", authorization)
+}
@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	scvMiddlewares "github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength  = 255
+)
+
+// Idempotency is a configurable HTTP middleware that honours the Idempotency-Key header of the incoming calls, which has to be mounted after the JWT one.
+// The first call with a key of a user is served and its response is stored for the received ttl, replaying it to the retries with the same key and payload.
+// A retry with a different payload, or arriving while the first call is still being served, gets a conflict. Responses with server errors are not stored,
+// and a call that never finishes releases its key after the received lockTimeout
+func Idempotency(repository ports.IdempotencyKeyRepository, userClaim string, ttl, lockTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > idempotencyKeyMaxLength {
+				utils.ErrorResponse(w, wrappers.NewValidationErr(fmt.Errorf("%s header cannot be longer than %d characters", IdempotencyKeyHeader, idempotencyKeyMaxLength)))
+				return
+			}
+
+			claims, _ := r.Context().Value(scvMiddlewares.ClaimsKey).(jwt.MapClaims)
+			userID, ok := claims[userClaim].(string)
+			if !ok {
+				utils.ErrorResponse(w, wrappers.NewUnauthorizedErr(fmt.Errorf("claim '%s' not found", userClaim)))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				utils.ErrorResponse(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now().UTC()
+			record := entities.IdempotencyKey{
+				UserID:      userID,
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(lockTimeout),
+			}
+
+			id, stored, err := repository.Reserve(r.Context(), record)
+			if err != nil {
+				utils.ErrorResponse(w, err)
+				return
+			}
+			if stored != nil {
+				replay(w, record, *stored)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			// the response is already written, so the key is saved even if the call has been cancelled meanwhile
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), lockTimeout)
+			defer cancel()
+
+			if recorder.status() >= http.StatusInternalServerError {
+				err = repository.Delete(ctx, id)
+			} else {
+				record.StatusCode = recorder.status()
+				record.Header = w.Header().Clone()
+				record.Body = recorder.body.Bytes()
+				record.ExpiresAt = time.Now().UTC().Add(ttl)
+				err = repository.Update(ctx, id, record)
+			}
+			if err != nil {
+				observability.Logger().Printf("idempotency key %s of UserID %s could not be saved: %s", key, userID, err)
+			}
+		})
+	}
+}
+
+// replay writes the stored response of a key, unless the stored call had a different payload or is still being served
+func replay(w http.ResponseWriter, record, stored entities.IdempotencyKey) {
+	if stored.RequestHash != record.RequestHash {
+		utils.SuccessResponse(w, http.StatusConflict, map[string]string{"error": fmt.Sprintf("%s %s has already been used with a different payload", IdempotencyKeyHeader, record.Key)})
+		return
+	}
+	if stored.StatusCode == 0 {
+		utils.SuccessResponse(w, http.StatusConflict, map[string]string{"error": fmt.Sprintf("a request with %s %s is still being processed", IdempotencyKeyHeader, record.Key)})
+		return
+	}
+
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+// requestHash gets a hash of the method, the path and the body of a call, identifying its payload
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + "\n" + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder is a response writer that writes through while keeping a copy of the status code and the body of the response
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// WriteHeader writes the status code of the response and keeps a copy of it
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.statusCode == 0 {
+		rr.statusCode = statusCode
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write writes a chunk of the body of the response and keeps a copy of it
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.statusCode == 0 {
+		rr.statusCode = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// status gets the status code of the response, which defaults to 200 when nothing has been written
+func (rr *responseRecorder) status() int {
+	if rr.statusCode == 0 {
+		return http.StatusOK
+	}
+	return rr.statusCode
+}
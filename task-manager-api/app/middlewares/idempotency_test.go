@@ -0,0 +1,221 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	scvMiddlewares "github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newIdempotentRequest creates a request of the user-123 user with the received idempotency key and body
+func newIdempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req.WithContext(context.WithValue(req.Context(), scvMiddlewares.ClaimsKey, jwt.MapClaims{"user_id": "user-123"}))
+}
+
+// newCreatedHandler creates a handler that reads the body of the request and responds with a 201, counting its calls
+func newCreatedHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"new-task-id","title":` + string(body) + `}`))
+	})
+}
+
+// TestIdempotency_NoKey checks that Idempotency lets the requests without key through without storing anything
+func TestIdempotency_NoKey(t *testing.T) {
+	// Arrange
+	calls := 0
+	repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+	handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(newCreatedHandler(&calls))
+
+	req := newIdempotentRequest("", `"title"`)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+}
+
+// TestIdempotency_FirstRequest checks that Idempotency serves the first request with a key and stores its response for the configured ttl
+func TestIdempotency_FirstRequest(t *testing.T) {
+	// Arrange
+	calls := 0
+	repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Reserve), mock.Anything, mock.MatchedBy(func(key entities.IdempotencyKey) bool {
+		return key.UserID == "user-123" && key.Key == "key-1" && key.RequestHash != "" && key.StatusCode == 0
+	})).Return("id-1", nil, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Update), mock.Anything, "id-1", mock.MatchedBy(func(key entities.IdempotencyKey) bool {
+		return key.StatusCode == http.StatusCreated &&
+			string(key.Body) == `{"id":"new-task-id","title":"title"}` &&
+			key.Header["Content-Type"][0] == "application/json" &&
+			key.ExpiresAt.After(time.Now().Add(59*time.Minute))
+	})).Return(nil).Once()
+	handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(newCreatedHandler(&calls))
+
+	req := newIdempotentRequest("key-1", `"title"`)
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, `{"id":"new-task-id","title":"title"}`, rr.Body.String())
+	assert.Equal(t, 1, calls)
+}
+
+// TestIdempotency_Replayed checks that Idempotency replays the stored response of a retry with the same key and payload without serving it again
+func TestIdempotency_Replayed(t *testing.T) {
+	// Arrange
+	calls := 0
+	req := newIdempotentRequest("key-1", `"title"`)
+	stored := entities.IdempotencyKey{
+		RequestHash: requestHash(req, []byte(`"title"`)),
+		StatusCode:  http.StatusCreated,
+		Header:      map[string][]string{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"id":"new-task-id"}`),
+	}
+
+	repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Reserve), mock.Anything, mock.Anything).Return("", &stored, nil).Once()
+	handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(newCreatedHandler(&calls))
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, `{"id":"new-task-id"}`, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 0, calls)
+}
+
+// TestIdempotency_Conflict checks that Idempotency returns a conflict for a retry with a different payload or arriving while the first request is being served
+func TestIdempotency_Conflict(t *testing.T) {
+	req := newIdempotentRequest("key-1", `"title"`)
+	tests := []struct {
+		name   string
+		stored entities.IdempotencyKey
+	}{
+		{
+			name:   "different payload",
+			stored: entities.IdempotencyKey{RequestHash: "other-hash", StatusCode: http.StatusCreated},
+		},
+		{
+			name:   "still being processed",
+			stored: entities.IdempotencyKey{RequestHash: requestHash(req, []byte(`"title"`))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+			repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Reserve), mock.Anything, mock.Anything).Return("", &tt.stored, nil).Once()
+			handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(newCreatedHandler(&calls))
+
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rr, newIdempotentRequest("key-1", `"title"`))
+
+			// Assert
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+// TestIdempotency_ServerError checks that Idempotency releases the key when the response is a server error, so that it can be retried
+func TestIdempotency_ServerError(t *testing.T) {
+	// Arrange
+	repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+	repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Reserve), mock.Anything, mock.Anything).Return("id-1", nil, nil).Once()
+	repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Delete), mock.Anything, "id-1").Return(nil).Once()
+	handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(rr, newIdempotentRequest("key-1", `"title"`))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+// TestIdempotency_InvalidRequest checks that Idempotency returns an error when the key is too long, the user claim is missing or the key cannot be reserved
+func TestIdempotency_InvalidRequest(t *testing.T) {
+	withoutClaims := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`"title"`))
+	withoutClaims.Header.Set(IdempotencyKeyHeader, "key-1")
+
+	tests := []struct {
+		name         string
+		req          *http.Request
+		reserveErr   error
+		expectedCode int
+	}{
+		{
+			name:         "key too long",
+			req:          newIdempotentRequest(strings.Repeat("k", 256), `"title"`),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "missing claim",
+			req:          withoutClaims,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "reserve error",
+			req:          newIdempotentRequest("key-1", `"title"`),
+			reserveErr:   errors.New("reserve-error"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			calls := 0
+			repositoryMock := mocks.NewIdempotencyKeyRepository(t)
+			if tt.reserveErr != nil {
+				repositoryMock.On(testutils.FunctionName(t, ports.IdempotencyKeyRepository.Reserve), mock.Anything, mock.Anything).Return("", nil, tt.reserveErr).Once()
+			}
+			handler := Idempotency(repositoryMock, "user_id", time.Hour, time.Minute)(newCreatedHandler(&calls))
+
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(rr, tt.req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// maxMessageSize is the maximum size in bytes of a message of a client
+const maxMessageSize = 1024
+
+// client of the hub, with the WebSocket connection of a user. Its rooms and closed flag are guarded by the hub
+type client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	userID string
+	send   chan models.RealtimeMessage
+	rooms  map[string]struct{}
+	closed bool
+}
+
+// newClient creates a new client of the hub
+func newClient(h *Hub, conn *websocket.Conn, userID string) *client {
+	return &client{
+		hub:    h,
+		conn:   conn,
+		userID: userID,
+		send:   make(chan models.RealtimeMessage, h.config.Realtime.SendBuffer),
+		rooms:  make(map[string]struct{}),
+	}
+}
+
+// readPump serves the messages of the client until the connection fails or misses the configured pong wait, removing the client afterwards
+func (c *client) readPump(ctx context.Context) {
+	defer c.hub.pumps.Done()
+	defer c.hub.remove(c)
+
+	pongWait := c.hub.config.Realtime.PongWait.Duration
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req models.RealtimeReq
+		err = json.Unmarshal(message, &req)
+		if err != nil {
+			c.hub.send(c, errorMessage("", wrappers.NewValidationErr(err)))
+			continue
+		}
+		c.hub.handle(ctx, c, req)
+	}
+}
+
+// writePump writes the messages sent to the client, pinging it every configured ping period. Once the client is closed
+// it writes a close message and closes the connection
+func (c *client) writePump() {
+	ticker := time.NewTicker(c.hub.config.Realtime.PingPeriod.Duration)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+		c.hub.pumps.Done()
+	}()
+
+	writeWait := c.hub.config.Realtime.WriteWait.Duration
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
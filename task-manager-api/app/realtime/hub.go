@@ -0,0 +1,372 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// Hub of the WebSocket clients, which subscribe to task and project rooms to know who else is viewing them and to be notified of the changes
+// to their tasks. Presence is tracked by each instance of the API on its own
+type Hub struct {
+	config         config.Config
+	taskService    ports.TaskService
+	projectService ports.ProjectService
+	upgrader       websocket.Upgrader
+
+	mu      sync.Mutex
+	closed  bool
+	clients map[*client]struct{}
+	rooms   map[string]map[*client]struct{}
+	pumps   sync.WaitGroup
+}
+
+// NewHub creates a new hub. Connections are only upgraded when their origin, if any, matches the host
+func NewHub(cfg config.Config, taskService ports.TaskService, projectService ports.ProjectService) *Hub {
+	return &Hub{
+		config:         cfg,
+		taskService:    taskService,
+		projectService: projectService,
+		clients:        make(map[*client]struct{}),
+		rooms:          make(map[string]map[*client]struct{}),
+	}
+}
+
+// Run relays the changes to the tasks to the rooms watching them until the context gets cancelled.
+// It then closes the connections of all the clients, returning once they are all closed
+func (h *Hub) Run(ctx context.Context) {
+	h.watch(ctx)
+
+	h.mu.Lock()
+	h.closed = true
+	for c := range h.clients {
+		c.closed = true
+		close(c.send)
+	}
+	h.clients = make(map[*client]struct{})
+	h.rooms = make(map[string]map[*client]struct{})
+	h.mu.Unlock()
+
+	h.pumps.Wait()
+	observability.Logger().Printf("Realtime hub stopped")
+}
+
+// Serve upgrades the request to a WebSocket connection of the received userID, serving it until either side closes it or the context gets cancelled.
+// It returns an error when the hub is already shut down, while failed upgrades are answered by the upgrader itself
+func (h *Hub) Serve(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) error {
+	h.mu.Lock()
+	closed := h.closed
+	h.mu.Unlock()
+	if closed {
+		return wrappers.NewServiceUnavailableErr(errors.New("realtime hub is shut down"))
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		observability.Logger().Printf("realtime connection of UserID %s not upgraded - error: %s", userID, err)
+		return nil
+	}
+
+	c := newClient(h, conn, userID)
+	if !h.register(c) {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(h.config.Realtime.WriteWait.Duration))
+		conn.Close()
+		return nil
+	}
+
+	go c.writePump()
+	go c.readPump(ctx)
+	return nil
+}
+
+// register adds a client to the hub, accounting for its pumps. It returns false when the hub is already shut down
+func (h *Hub) register(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+	h.clients[c] = struct{}{}
+	h.pumps.Add(2)
+	return true
+}
+
+// remove closes a client, removing it from its rooms
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.dropLocked(c)
+}
+
+// handle serves a message of a client
+func (h *Hub) handle(ctx context.Context, c *client, req models.RealtimeReq) {
+	switch req.Type {
+	case models.RealtimeReqSubscribe:
+		authCtx, cancel := context.WithTimeout(ctx, h.config.Timeout.Duration)
+		err := h.authorize(authCtx, c.userID, req.Room)
+		cancel()
+		if err != nil {
+			h.send(c, errorMessage(req.Room, err))
+			return
+		}
+		h.join(c, req.Room)
+	case models.RealtimeReqUnsubscribe:
+		h.leave(c, req.Room)
+	default:
+		h.send(c, errorMessage(req.Room, wrappers.NewValidationErr(fmt.Errorf("message type %q not valid", req.Type))))
+	}
+}
+
+// authorize checks that the user can view a room: the task of a task room, or the project of a project room
+func (h *Hub) authorize(ctx context.Context, userID, room string) error {
+	kind, id, ok := strings.Cut(room, ":")
+	if !ok || id == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("room %q not valid, should be task:{id} or project:{id}", room))
+	}
+
+	var err error
+	switch kind {
+	case models.RealtimeRoomTask:
+		_, err = h.taskService.Get(ctx, userID, id)
+	case models.RealtimeRoomProject:
+		_, err = h.projectService.GetByID(ctx, userID, id)
+	default:
+		err = wrappers.NewValidationErr(fmt.Errorf("room %q not valid, should be task:{id} or project:{id}", room))
+	}
+	return err
+}
+
+// join adds a client to a room, notifying the new presence to the whole room
+func (h *Hub) join(c *client, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	if _, ok := c.rooms[room]; ok {
+		h.sendLocked(c, models.RealtimeMessage{Type: models.RealtimeMessageSubscribed, Room: room})
+		return
+	}
+
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*client]struct{})
+	}
+	h.rooms[room][c] = struct{}{}
+	c.rooms[room] = struct{}{}
+
+	h.sendLocked(c, models.RealtimeMessage{Type: models.RealtimeMessageSubscribed, Room: room})
+	h.broadcastLocked(room, h.presenceLocked(room))
+}
+
+// leave removes a client from a room
+func (h *Hub) leave(c *client, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	if _, ok := c.rooms[room]; ok {
+		h.leaveLocked(c, room)
+	}
+	h.sendLocked(c, models.RealtimeMessage{Type: models.RealtimeMessageUnsubscribed, Room: room})
+}
+
+// send sends a message to a client
+func (h *Hub) send(c *client, msg models.RealtimeMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sendLocked(c, msg)
+}
+
+// watch relays the changes to the tasks to their task and project rooms until the context gets cancelled, opening the change stream again
+// after the configured retry delay when it fails
+func (h *Hub) watch(ctx context.Context) {
+	var lastEventID string
+	for {
+		err := h.relayChanges(ctx, &lastEventID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		observability.Logger().Printf("realtime hub task changes - error: %s", err)
+		if errors.Is(err, wrappers.ValidationErr) {
+			lastEventID = ""
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.config.Realtime.RetryDelay.Duration):
+		}
+	}
+}
+
+// relayChanges relays the changes of a stream of the changes to all the tasks, resuming right after the received last event ID,
+// which is kept up to date. It only returns when the stream fails
+func (h *Hub) relayChanges(ctx context.Context, lastEventID *string) error {
+	stream, err := h.taskService.Stream(ctx, "", *lastEventID)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for {
+		event, err := stream.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			continue
+		}
+
+		*lastEventID = event.ID
+		h.notify(ctx, *event)
+	}
+}
+
+// notify sends a task change to the rooms of the task and of its project. As access to a room may have been revoked since subscribing,
+// only the members that can still view the room get the change, while the ones that cannot are removed from it. Changes read once the task
+// was already purged carry no task, and are skipped
+func (h *Hub) notify(ctx context.Context, event models.TaskChangeEvent) {
+	if event.Task.ID == "" {
+		return
+	}
+
+	rooms := []string{fmt.Sprintf("%s:%s", models.RealtimeRoomTask, event.Task.ID)}
+	if event.Task.ProjectID != "" {
+		rooms = append(rooms, fmt.Sprintf("%s:%s", models.RealtimeRoomProject, event.Task.ProjectID))
+	}
+
+	for _, room := range rooms {
+		allowed, revoked := h.authorizeMembers(ctx, room)
+		task := event.Task
+		msg := models.RealtimeMessage{Type: event.Type, Room: room, Task: &task}
+
+		h.mu.Lock()
+		for c := range h.rooms[room] {
+			if err, ok := revoked[c.userID]; ok {
+				h.leaveLocked(c, room)
+				h.sendLocked(c, errorMessage(room, err))
+			}
+		}
+		for c := range h.rooms[room] {
+			if allowed[c.userID] {
+				h.sendLocked(c, msg)
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+// authorizeMembers checks which of the users in a room can still view it, returning the users whose access has been revoked with the error
+// explaining why. Users whose access cannot be checked are in neither of the results, and users of a task in the trash can still view it
+func (h *Hub) authorizeMembers(ctx context.Context, room string) (allowed map[string]bool, revoked map[string]error) {
+	h.mu.Lock()
+	userIDs := make(map[string]struct{})
+	for c := range h.rooms[room] {
+		userIDs[c.userID] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	allowed = make(map[string]bool)
+	revoked = make(map[string]error)
+	for userID := range userIDs {
+		authCtx, cancel := context.WithTimeout(ctx, h.config.Timeout.Duration)
+		err := h.authorize(authCtx, userID, room)
+		cancel()
+
+		switch {
+		case err == nil, errors.Is(err, wrappers.NonExistentErr):
+			allowed[userID] = true
+		case errors.Is(err, wrappers.UnauthenticatedErr):
+			revoked[userID] = err
+		default:
+			observability.Logger().Printf("realtime access of UserID %s to room %s not checked - error: %s", userID, room, err)
+		}
+	}
+	return
+}
+
+// leaveLocked removes a client from a room, notifying the new presence to the rest of the room
+func (h *Hub) leaveLocked(c *client, room string) {
+	delete(c.rooms, room)
+	delete(h.rooms[room], c)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+		return
+	}
+	h.broadcastLocked(room, h.presenceLocked(room))
+}
+
+// dropLocked closes a client, removing it from its rooms
+func (h *Hub) dropLocked(c *client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.send)
+	delete(h.clients, c)
+
+	for room := range c.rooms {
+		h.leaveLocked(c, room)
+	}
+}
+
+// broadcastLocked sends a message to all the clients in a room
+func (h *Hub) broadcastLocked(room string, msg models.RealtimeMessage) {
+	for c := range h.rooms[room] {
+		h.sendLocked(c, msg)
+	}
+}
+
+// sendLocked sends a message to a client, closing it when it is too slow to keep up with its messages
+func (h *Hub) sendLocked(c *client, msg models.RealtimeMessage) {
+	if c.closed {
+		return
+	}
+
+	select {
+	case c.send <- msg:
+	default:
+		observability.Logger().Printf("realtime connection of UserID %s too slow, closing it", c.userID)
+		h.dropLocked(c)
+	}
+}
+
+// presenceLocked builds the presence message of a room, with the users viewing it sorted
+func (h *Hub) presenceLocked(room string) models.RealtimeMessage {
+	seen := make(map[string]struct{})
+	userIDs := []string{}
+	for c := range h.rooms[room] {
+		if _, ok := seen[c.userID]; ok {
+			continue
+		}
+		seen[c.userID] = struct{}{}
+		userIDs = append(userIDs, c.userID)
+	}
+	sort.Strings(userIDs)
+
+	return models.RealtimeMessage{Type: models.RealtimeMessagePresence, Room: room, UserIDs: userIDs}
+}
+
+// errorMessage builds the message of an error
+func errorMessage(room string, err error) models.RealtimeMessage {
+	return models.RealtimeMessage{Type: models.RealtimeMessageError, Room: room, Error: err.Error()}
+}
@@ -0,0 +1,264 @@
+package realtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRealtimeConfig creates a config retrying the change stream right away
+func newRealtimeConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Timeout.Duration = time.Second
+	cfg.Realtime.PingPeriod.Duration = time.Minute
+	cfg.Realtime.PongWait.Duration = 2 * time.Minute
+	cfg.Realtime.WriteWait.Duration = time.Second
+	cfg.Realtime.SendBuffer = 8
+	cfg.Realtime.RetryDelay.Duration = 10 * time.Millisecond
+	return cfg
+}
+
+// newTaskServiceMock creates a task service mock streaming the events sent to the received channel
+func newTaskServiceMock(t *testing.T, events chan *models.TaskChangeEvent) *mocks.TaskService {
+	streamMock := mocks.NewTaskEventStream(t)
+	streamMock.On(testutils.FunctionName(t, ports.TaskEventStream.Next), mock.Anything).Return(func(ctx context.Context) (*models.TaskChangeEvent, error) {
+		select {
+		case event := <-events:
+			return event, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}).Maybe()
+	streamMock.On(testutils.FunctionName(t, ports.TaskEventStream.Close), mock.Anything).Return(nil).Maybe()
+
+	taskServiceMock := mocks.NewTaskService(t)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Stream), mock.Anything, "", "").Return(streamMock, nil).Once()
+	return taskServiceMock
+}
+
+// runHub runs the hub behind a test server serving the user of the user_id query parameter, stopping both once the test finishes
+func runHub(t *testing.T, hub *Hub) *httptest.Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.Serve(ctx, w, r, r.URL.Query().Get("user_id"))
+	}))
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		server.Close()
+	})
+	return server
+}
+
+// dial opens a WebSocket connection of the received user to the test server
+func dial(t *testing.T, server *httptest.Server, userID string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?user_id=" + userID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// read reads the next message of a connection
+func read(t *testing.T, conn *websocket.Conn) models.RealtimeMessage {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg models.RealtimeMessage
+	assert.Nil(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// TestServe_Presence checks that the clients subscribed to a room are notified of the users viewing it whenever someone joins or leaves
+func TestServe_Presence(t *testing.T) {
+	// Arrange
+	taskServiceMock := newTaskServiceMock(t, nil)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, mock.Anything, "task-id").Return(models.GetTaskResp{ID: "task-id"}, nil).Twice()
+	server := runHub(t, NewHub(newRealtimeConfig(), taskServiceMock, mocks.NewProjectService(t)))
+
+	first := dial(t, server, "user-1")
+	second := dial(t, server, "user-2")
+	subscribe := models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "task:task-id"}
+
+	// Act
+	assert.Nil(t, first.WriteJSON(subscribe))
+	firstSubscribed, firstPresence := read(t, first), read(t, first)
+	assert.Nil(t, second.WriteJSON(subscribe))
+	secondSubscribed, secondPresence, bothPresence := read(t, second), read(t, second), read(t, first)
+	assert.Nil(t, second.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqUnsubscribe, Room: "task:task-id"}))
+	unsubscribed, leftPresence := read(t, second), read(t, first)
+
+	// Assert
+	assert.Equal(t, models.RealtimeMessage{Type: models.RealtimeMessageSubscribed, Room: "task:task-id"}, firstSubscribed)
+	assert.Equal(t, []string{"user-1"}, firstPresence.UserIDs)
+	assert.Equal(t, models.RealtimeMessage{Type: models.RealtimeMessageSubscribed, Room: "task:task-id"}, secondSubscribed)
+	assert.Equal(t, []string{"user-1", "user-2"}, secondPresence.UserIDs)
+	assert.Equal(t, []string{"user-1", "user-2"}, bothPresence.UserIDs)
+	assert.Equal(t, models.RealtimeMessageUnsubscribed, unsubscribed.Type)
+	assert.Equal(t, models.RealtimeMessage{Type: models.RealtimeMessagePresence, Room: "task:task-id", UserIDs: []string{"user-1"}}, leftPresence)
+}
+
+// TestServe_Unauthorized checks that a client gets an error message when subscribing to a room it cannot view, or to an invalid one
+func TestServe_Unauthorized(t *testing.T) {
+	// Arrange
+	projectServiceMock := mocks.NewProjectService(t)
+	projectServiceMock.On(testutils.FunctionName(t, ports.ProjectService.GetByID), mock.Anything, "user-1", "project-id").Return(models.GetProjectResp{}, wrappers.NewUnauthenticatedErr(assert.AnError)).Once()
+	server := runHub(t, NewHub(newRealtimeConfig(), newTaskServiceMock(t, nil), projectServiceMock))
+
+	conn := dial(t, server, "user-1")
+
+	// Act
+	assert.Nil(t, conn.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "project:project-id"}))
+	unauthorized := read(t, conn)
+	assert.Nil(t, conn.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "label:label-id"}))
+	invalid := read(t, conn)
+
+	// Assert
+	assert.Equal(t, models.RealtimeMessageError, unauthorized.Type)
+	assert.Equal(t, "project:project-id", unauthorized.Room)
+	assert.Equal(t, assert.AnError.Error(), unauthorized.Error)
+	assert.Equal(t, models.RealtimeMessageError, invalid.Type)
+	assert.Contains(t, invalid.Error, "should be task:{id} or project:{id}")
+}
+
+// TestRun_NotifiesChanges checks that Run notifies the changes to a task to the rooms of the task and of its project, checking the access of their members again
+func TestRun_NotifiesChanges(t *testing.T) {
+	// Arrange
+	events := make(chan *models.TaskChangeEvent, 1)
+	taskServiceMock := newTaskServiceMock(t, events)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-1", "task-id").Return(models.GetTaskResp{ID: "task-id"}, nil).Twice()
+	projectServiceMock := mocks.NewProjectService(t)
+	projectServiceMock.On(testutils.FunctionName(t, ports.ProjectService.GetByID), mock.Anything, "user-2", "project-id").Return(models.GetProjectResp{ID: "project-id"}, nil).Twice()
+	server := runHub(t, NewHub(newRealtimeConfig(), taskServiceMock, projectServiceMock))
+
+	taskConn := dial(t, server, "user-1")
+	assert.Nil(t, taskConn.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "task:task-id"}))
+	read(t, taskConn)
+	read(t, taskConn)
+	projectConn := dial(t, server, "user-2")
+	assert.Nil(t, projectConn.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "project:project-id"}))
+	read(t, projectConn)
+	read(t, projectConn)
+
+	// Act
+	events <- &models.TaskChangeEvent{ID: "token", Type: entities.WebhookEventTaskUpdated, Task: models.GetTaskResp{ID: "task-id", ProjectID: "project-id"}}
+	taskMsg, projectMsg := read(t, taskConn), read(t, projectConn)
+
+	// Assert
+	assert.Equal(t, entities.WebhookEventTaskUpdated, taskMsg.Type)
+	assert.Equal(t, "task:task-id", taskMsg.Room)
+	assert.Equal(t, "task-id", taskMsg.Task.ID)
+	assert.Equal(t, entities.WebhookEventTaskUpdated, projectMsg.Type)
+	assert.Equal(t, "project:project-id", projectMsg.Room)
+	assert.Equal(t, "task-id", projectMsg.Task.ID)
+}
+
+// TestRun_RevokedAccess checks that Run removes from a room the members that can no longer view it instead of notifying them of the changes
+func TestRun_RevokedAccess(t *testing.T) {
+	// Arrange
+	events := make(chan *models.TaskChangeEvent, 1)
+	taskServiceMock := newTaskServiceMock(t, events)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-1", "task-id").Return(models.GetTaskResp{ID: "task-id"}, nil).Twice()
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-2", "task-id").Return(models.GetTaskResp{ID: "task-id"}, nil).Once()
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-2", "task-id").Return(models.GetTaskResp{}, wrappers.NewUnauthenticatedErr(assert.AnError)).Once()
+	server := runHub(t, NewHub(newRealtimeConfig(), taskServiceMock, mocks.NewProjectService(t)))
+
+	subscribe := models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "task:task-id"}
+	owner := dial(t, server, "user-1")
+	assert.Nil(t, owner.WriteJSON(subscribe))
+	read(t, owner)
+	read(t, owner)
+	collaborator := dial(t, server, "user-2")
+	assert.Nil(t, collaborator.WriteJSON(subscribe))
+	read(t, collaborator)
+	read(t, collaborator)
+	read(t, owner)
+
+	// Act
+	events <- &models.TaskChangeEvent{ID: "token", Type: entities.WebhookEventTaskUpdated, Task: models.GetTaskResp{ID: "task-id"}}
+	ownerPresence, ownerMsg := read(t, owner), read(t, owner)
+	collaboratorMsg := read(t, collaborator)
+
+	// Assert
+	assert.Equal(t, models.RealtimeMessage{Type: models.RealtimeMessagePresence, Room: "task:task-id", UserIDs: []string{"user-1"}}, ownerPresence)
+	assert.Equal(t, entities.WebhookEventTaskUpdated, ownerMsg.Type)
+	assert.Equal(t, "task-id", ownerMsg.Task.ID)
+	assert.Equal(t, models.RealtimeMessageError, collaboratorMsg.Type)
+	assert.Equal(t, "task:task-id", collaboratorMsg.Room)
+	assert.Equal(t, assert.AnError.Error(), collaboratorMsg.Error)
+	assert.Nil(t, collaboratorMsg.Task)
+}
+
+// TestRun_PurgedTask checks that Run skips the changes read once the task was already purged, which carry no task
+func TestRun_PurgedTask(t *testing.T) {
+	// Arrange
+	events := make(chan *models.TaskChangeEvent, 2)
+	taskServiceMock := newTaskServiceMock(t, events)
+	taskServiceMock.On(testutils.FunctionName(t, ports.TaskService.Get), mock.Anything, "user-1", "task-id").Return(models.GetTaskResp{ID: "task-id"}, nil).Twice()
+	server := runHub(t, NewHub(newRealtimeConfig(), taskServiceMock, mocks.NewProjectService(t)))
+
+	conn := dial(t, server, "user-1")
+	assert.Nil(t, conn.WriteJSON(models.RealtimeReq{Type: models.RealtimeReqSubscribe, Room: "task:task-id"}))
+	read(t, conn)
+	read(t, conn)
+
+	// Act
+	events <- &models.TaskChangeEvent{ID: "token-1", Type: entities.WebhookEventTaskUpdated}
+	events <- &models.TaskChangeEvent{ID: "token-2", Type: entities.WebhookEventTaskUpdated, Task: models.GetTaskResp{ID: "task-id"}}
+	msg := read(t, conn)
+
+	// Assert
+	assert.Equal(t, entities.WebhookEventTaskUpdated, msg.Type)
+	assert.Equal(t, "task:task-id", msg.Room)
+	assert.Equal(t, "task-id", msg.Task.ID)
+}
+
+// TestRun_ContextCancelled checks that Run closes the connections of the clients once the context gets cancelled, and rejects new ones
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	hub := NewHub(newRealtimeConfig(), newTaskServiceMock(t, nil), mocks.NewProjectService(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := hub.Serve(ctx, w, r, "user-1"); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	conn := dial(t, server, "user-1")
+
+	// Act
+	cancel()
+	<-done
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	_, resp, dialErr := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+
+	// Assert
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+	assert.NotNil(t, dialErr)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
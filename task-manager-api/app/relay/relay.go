@@ -0,0 +1,56 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+type relay struct {
+	config  config.Config
+	service ports.OutboxService
+}
+
+func New(cfg config.Config, service ports.OutboxService) relay {
+	return relay{
+		config:  cfg,
+		service: service,
+	}
+}
+
+// Run periodically publishes the events written to the outbox until the context gets cancelled
+func (r relay) Run(ctx context.Context, cancel context.CancelFunc) func() error {
+	return func() error {
+		defer cancel()
+		defer func() {
+			if rec := recover(); rec != nil {
+				observability.Logger().Printf("FATAL - recovered panic in outbox relay process: %v", rec)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				observability.Logger().Printf("Outbox relay stopped")
+				return nil
+			case <-time.After(r.config.Outbox.Interval.Duration):
+			}
+
+			runCtx, runCancel := context.WithTimeout(ctx, r.config.Outbox.Lease.Duration)
+			published, err := r.service.Relay(runCtx)
+			runCancel()
+
+			if err != nil {
+				observability.Logger().Printf("outbox relay process - error: %s", err)
+				continue
+			}
+
+			if published > 0 {
+				observability.Logger().Printf("outbox relay process - events published: %d", published)
+			}
+		}
+	}
+}
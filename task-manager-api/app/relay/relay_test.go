@@ -0,0 +1,55 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNew_Ok checks that New creates a new relay struct with the expected values
+func TestNew_Ok(t *testing.T) {
+	// Arrange
+	expectedConfig := config.Config{}
+	expectedService := mocks.NewOutboxService(t)
+
+	// Act
+	relay := New(expectedConfig, expectedService)
+
+	// Assert
+	assert.Equal(t, expectedConfig, relay.config)
+	assert.Equal(t, expectedService, relay.service)
+}
+
+// TestRun_ContextCancelled checks that Run relays the outbox periodically until the context gets cancelled, even when a run fails
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Outbox.Interval.Duration = 10 * time.Millisecond
+	cfg.Outbox.Lease.Duration = time.Second
+
+	outboxServiceMock := mocks.NewOutboxService(t)
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Relay), mock.Anything).Return(0, errors.New("relay-error")).Once()
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Relay), mock.Anything).Return(1, nil)
+
+	relay := &relay{
+		config:  cfg,
+		service: outboxServiceMock,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+	// Act
+	errFunc := relay.Run(ctx, cancel)
+
+	// Assert
+	assert.Nil(t, errFunc())
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
@@ -25,6 +25,7 @@ func main() {
 		Version              string `long:"ver" description:"Version" required:"true"`
 		Environment          string `long:"env" description:"Environment" choice:"local" choice:"prod" required:"true"`
 		HTTPPort             int    `long:"hport" description:"Running HTTP port" required:"true"`
+		GRPCPort             int    `long:"gport" description:"Running gRPC port" required:"true"`
 		DSN                  string `long:"dsn" description:"Database DSN" required:"true"`
 		JWTSecret            string `long:"jsecret" description:"Secret used to sign and validate JWT tokens" required:"true"`
 		UserManagementTarget string `long:"usersgrpc" description:"Base gRPC target of the User Management API" required:"true"`
@@ -35,7 +36,7 @@ func main() {
 		observability.Logger().Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
 	}
 
-	cfg, err := config.ReadConfig(opts.Version, opts.Environment, opts.HTTPPort, opts.DSN, opts.JWTSecret, opts.UserManagementTarget, "config")
+	cfg, err := config.ReadConfig(opts.Version, opts.Environment, opts.HTTPPort, opts.GRPCPort, opts.DSN, opts.JWTSecret, opts.UserManagementTarget, "config")
 	if err != nil {
 		observability.Logger().Fatal(fmt.Errorf("cannot parse config file for env %s: %w", opts.Environment, err))
 	}
@@ -48,6 +49,11 @@ func main() {
 
 	a := api.New(ctx, cfg)
 	g.Go(a.RunHTTP(ctx, cancel))
+	g.Go(a.RunGRPC(ctx, cancel))
+	g.Go(a.RunRelay(ctx, cancel))
+	if cfg.Async.Run {
+		g.Go(a.RunAsync(ctx, cancel))
+	}
 
 	<-ctx.Done()
 	observability.Logger().Printf("context canceled, the application will terminate...")
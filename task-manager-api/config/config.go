@@ -7,11 +7,90 @@ import (
 	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
 )
 
+type Pagination struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+type Subtasks struct {
+	MaxDepth int
+}
+
+type Comments struct {
+	MaxLength int
+}
+
+type Attachments struct {
+	MaxSize             int64
+	TransferTimeout     utils.Duration
+	AllowedContentTypes []string
+	Storage             string
+	LocalPath           string
+}
+
+type Recurrence struct {
+	Horizon   utils.Duration
+	BatchSize int
+}
+
+type Trash struct {
+	Retention utils.Duration
+}
+
+type Batch struct {
+	MaxOperations int
+}
+
+type Idempotency struct {
+	TTL         utils.Duration
+	LockTimeout utils.Duration
+}
+
+type Webhooks struct {
+	Interval     utils.Duration
+	Timeout      utils.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseDelay    utils.Duration
+	MaxDelay     utils.Duration
+	BlockedHosts []string
+}
+
+type Outbox struct {
+	Interval  utils.Duration
+	Lease     utils.Duration
+	BatchSize int
+	Publisher string
+}
+
+type Stream struct {
+	Heartbeat utils.Duration
+}
+
+type Realtime struct {
+	PingPeriod utils.Duration
+	PongWait   utils.Duration
+	WriteWait  utils.Duration
+	SendBuffer int
+	RetryDelay utils.Duration
+}
+
+type Ranks struct {
+	MaxLength int
+	BatchSize int
+}
+
+type Async struct {
+	Run      bool
+	Interval utils.Duration
+}
+
 type Config struct {
 	// set in flags
 	Version     string
 	Environment string
 	HTTPPort    int
+	GRPCPort    int
 	DSN         string
 	JWTSecret   string
 	externalURLs
@@ -25,17 +104,32 @@ type externalURLs struct {
 }
 
 type config struct {
-	Timeout utils.Duration
+	Timeout     utils.Duration
+	Pagination  Pagination
+	Subtasks    Subtasks
+	Comments    Comments
+	Attachments Attachments
+	Recurrence  Recurrence
+	Trash       Trash
+	Ranks       Ranks
+	Batch       Batch
+	Idempotency Idempotency
+	Webhooks    Webhooks
+	Outbox      Outbox
+	Stream      Stream
+	Realtime    Realtime
+	Async       Async
 }
 
 // ReadConfig from the project´s JSON config files.
 // Default values are specified in the default configuration file, config/config.json
 // and can be overrided with values specified in the environment configuration files, config/config.{env}.json.
-func ReadConfig(version, env string, httpPort int, dsn, jwtSecret, userManagementTarget, configPath string) (Config, error) {
+func ReadConfig(version, env string, httpPort, grpcPort int, dsn, jwtSecret, userManagementTarget, configPath string) (Config, error) {
 	var c Config
 	c.Version = version
 	c.Environment = env
 	c.HTTPPort = httpPort
+	c.GRPCPort = grpcPort
 	c.DSN = dsn
 	c.JWTSecret = jwtSecret
 	c.UserManagementTarget = userManagementTarget
@@ -19,7 +19,7 @@ func TestReadConfig_Ok(t *testing.T) {
 	}
 
 	// Act
-	cfg, err := ReadConfig("", expectedConfig.Environment, 0, "", "", "", path.Join(path.Dir(filePath)))
+	cfg, err := ReadConfig("", expectedConfig.Environment, 0, 0, "", "", "", path.Join(path.Dir(filePath)))
 
 	// Assert
 	assert.NotEmpty(t, cfg)
@@ -34,7 +34,7 @@ func TestReadConfig_NonExistentConfigPath(t *testing.T) {
 	expectedError := fmt.Sprintf("error parsing configuration, ignoring config file %s/config.json: stat %s/config.json: no such file or directory", invalidPath, invalidPath)
 
 	// Act
-	_, err := ReadConfig("", "", 0, "", "", "", invalidPath)
+	_, err := ReadConfig("", "", 0, 0, "", "", "", invalidPath)
 
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
@@ -52,7 +52,7 @@ func TestReadConfig_NonExistentEnvironmentFile(t *testing.T) {
 	expectedError := fmt.Sprintf("error parsing environment configuration, ignoring config file %s/config.invalid-environment.json: stat %s/config.invalid-environment.json: no such file or directory", path.Join(path.Dir(filePath)), path.Join(path.Dir(filePath)))
 
 	// Act
-	_, err := ReadConfig("", expectedConfig.Environment, 0, "", "", "", path.Join(path.Dir(filePath)))
+	_, err := ReadConfig("", expectedConfig.Environment, 0, 0, "", "", "", path.Join(path.Dir(filePath)))
 
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
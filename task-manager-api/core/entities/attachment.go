@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+const EntityNameAttachment = "attachments"
+
+// Attachment is the metadata of a file attached to a task, whose content is kept in the blob store under BlobKey
+type Attachment struct {
+	ID          string    `bson:"_id,omitempty"`
+	TaskID      string    `bson:"task_id"`
+	UserID      string    `bson:"user_id"`
+	FileName    string    `bson:"file_name"`
+	ContentType string    `bson:"content_type"`
+	Size        int64     `bson:"size"`
+	BlobKey     string    `bson:"blob_key"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
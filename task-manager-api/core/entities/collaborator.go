@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+const EntityNameTaskCollaborator = "task_collaborators"
+
+const (
+	TaskRoleViewer = "viewer"
+	TaskRoleEditor = "editor"
+	TaskRoleOwner  = "owner"
+)
+
+// TaskCollaborator grants a user, other than the owner, a role on a task and on all its subtasks
+type TaskCollaborator struct {
+	ID        string    `bson:"_id,omitempty"`
+	OwnerID   string    `bson:"owner_id"`
+	TaskID    string    `bson:"task_id"`
+	UserID    string    `bson:"user_id"`
+	Role      string    `bson:"role"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
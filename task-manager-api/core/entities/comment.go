@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+const EntityNameComment = "comments"
+
+// Comment is a message posted by a user in the discussion of a task
+type Comment struct {
+	ID        string    `bson:"_id,omitempty"`
+	TaskID    string    `bson:"task_id"`
+	AuthorID  string    `bson:"author_id"`
+	Body      string    `bson:"body"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
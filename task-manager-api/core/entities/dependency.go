@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+const EntityNameTaskDependency = "task_dependencies"
+
+// TaskDependency relates a task with another task of the same user that blocks it
+type TaskDependency struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	TaskID    string    `bson:"task_id"`
+	BlockerID string    `bson:"blocker_id"`
+	CreatedAt time.Time `bson:"created_at"`
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+const EntityNameTaskHistory = "task_history"
+
+const (
+	TaskHistoryActionCreated  = "created"
+	TaskHistoryActionUpdated  = "updated"
+	TaskHistoryActionDeleted  = "deleted"
+	TaskHistoryActionRestored = "restored"
+)
+
+// TaskHistoryActorSystem is the actor of the changes made by the background processes
+const TaskHistoryActorSystem = "system"
+
+// TaskHistory is an immutable record of a change made to a task
+type TaskHistory struct {
+	ID        string        `bson:"_id,omitempty"`
+	TaskID    string        `bson:"task_id"`
+	ActorID   string        `bson:"actor_id"`
+	Action    string        `bson:"action"`
+	Changes   []FieldChange `bson:"changes"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// FieldChange is the value of a task field before and after a change
+type FieldChange struct {
+	Field string      `bson:"field"`
+	From  interface{} `bson:"from"`
+	To    interface{} `bson:"to"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+const EntityNameIdempotencyKey = "idempotency_keys"
+
+// IdempotencyKey is a request identified by the Idempotency-Key header of a user, with its response once it has been served.
+// A key without response is reserved by a request still being served
+type IdempotencyKey struct {
+	ID          string              `bson:"_id,omitempty"`
+	UserID      string              `bson:"user_id"`
+	Key         string              `bson:"key"`
+	RequestHash string              `bson:"request_hash"`
+	StatusCode  int                 `bson:"status_code,omitempty"`
+	Header      map[string][]string `bson:"header,omitempty"`
+	Body        []byte              `bson:"body,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at"`
+	ExpiresAt   time.Time           `bson:"expires_at"`
+}
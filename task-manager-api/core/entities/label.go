@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+const EntityNameLabel = "labels"
+
+type Label struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	Name      string    `bson:"name"`
+	CreatedAt time.Time `bson:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+const EntityNameOutboxEvent = "outbox_events"
+
+// OutboxEvent is a task event written in the same transaction as the change it describes, waiting to be published by the relay.
+// Events are removed once published
+type OutboxEvent struct {
+	ID          string    `bson:"_id,omitempty"`
+	Type        string    `bson:"type"`
+	Payload     []byte    `bson:"payload"`
+	AvailableAt time.Time `bson:"available_at"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+const EntityNameProject = "projects"
+
+type Project struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	Name      string    `bson:"name"`
+	Color     string    `bson:"color"`
+	Archived  bool      `bson:"archived"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
@@ -4,10 +4,60 @@ import "time"
 
 const EntityNameTask = "tasks"
 
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusBlocked    = "blocked"
+	TaskStatusDone       = "done"
+	TaskStatusArchived   = "archived"
+)
+
+const (
+	TaskPriorityLow    = "low"
+	TaskPriorityMedium = "medium"
+	TaskPriorityHigh   = "high"
+)
+
+const (
+	TaskChangeOperationInsert  = "insert"
+	TaskChangeOperationUpdate  = "update"
+	TaskChangeOperationReplace = "replace"
+)
+
+// Task is owned by UserID, which can differ from the CreatorID when a collaborator creates it, and worked on by its AssigneeID when it is assigned
 type Task struct {
-	ID          string    `bson:"_id,omitempty"`
-	UserID      string    `bson:"user_id"`
-	Title       string    `bson:"title"`
-	Description string    `bson:"description"`
-	CreatedAt   time.Time `bson:"created_at"`
+	ID               string     `bson:"_id,omitempty"`
+	UserID           string     `bson:"user_id"`
+	CreatorID        string     `bson:"creator_id,omitempty"`
+	AssigneeID       string     `bson:"assignee_id,omitempty"`
+	ProjectID        string     `bson:"project_id,omitempty"`
+	ParentID         string     `bson:"parent_id,omitempty"`
+	Position         int        `bson:"position"`
+	Rank             string     `bson:"rank"`
+	Title            string     `bson:"title"`
+	Description      string     `bson:"description"`
+	Status           string     `bson:"status"`
+	Priority         string     `bson:"priority"`
+	Labels           []string   `bson:"labels"`
+	DueAt            *time.Time `bson:"due_at"`
+	Recurrence       string     `bson:"recurrence"`
+	NextOccurrenceID string     `bson:"next_occurrence_id,omitempty"`
+	CreatedAt        time.Time  `bson:"created_at"`
+	UpdatedAt        time.Time  `bson:"updated_at"`
+	CompletedAt      *time.Time `bson:"completed_at"`
+	DeletedAt        *time.Time `bson:"deleted_at"`
+	Version          int        `bson:"version"`
+}
+
+// TaskSearchResult is a task matching a text search, with its relevance score
+type TaskSearchResult struct {
+	Task  `bson:",inline"`
+	Score float64 `bson:"score"`
+}
+
+// TaskChange is an insert, update or replace of a task as stored right after it, identified by the token that resumes the changes after it
+type TaskChange struct {
+	Token     string
+	Operation string
+	Task      Task
 }
@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+const (
+	EntityNameWebhook         = "webhooks"
+	EntityNameWebhookDelivery = "webhook_deliveries"
+)
+
+const (
+	WebhookEventTaskCreated = "task.created"
+	WebhookEventTaskUpdated = "task.updated"
+	WebhookEventTaskDeleted = "task.deleted"
+)
+
+const (
+	WebhookDeliveryStatusPending = "pending"
+	WebhookDeliveryStatusDead    = "dead"
+)
+
+// Webhook is an endpoint of a user notified of the events of the received types on the tasks the user owns
+type Webhook struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	URL       string    `bson:"url"`
+	Secret    string    `bson:"secret"`
+	Events    []string  `bson:"events"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// WebhookDelivery is an event pending to be delivered to a webhook, or dead after running out of attempts.
+// Deliveries are removed once delivered
+type WebhookDelivery struct {
+	ID             string    `bson:"_id,omitempty"`
+	EventID        string    `bson:"event_id"`
+	WebhookID      string    `bson:"webhook_id"`
+	UserID         string    `bson:"user_id"`
+	Event          string    `bson:"event"`
+	Payload        []byte    `bson:"payload"`
+	Status         string    `bson:"status"`
+	Attempts       int       `bson:"attempts"`
+	NextAttemptAt  time.Time `bson:"next_attempt_at"`
+	LastStatusCode int       `bson:"last_status_code"`
+	LastError      string    `bson:"last_error"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
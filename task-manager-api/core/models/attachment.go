@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UploadAttachmentReq struct
+type UploadAttachmentReq struct {
+	FileName    string
+	ContentType string
+}
+
+// CreateAttachmentResp struct
+type CreateAttachmentResp struct {
+	ID string `json:"id"`
+}
+
+// GetAttachmentResp struct
+type GetAttachmentResp struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	UserID      string    `json:"user_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	BlobKey     string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// BatchTasksReq struct with the operations to apply over the tasks, each one independently of the others
+type BatchTasksReq struct {
+	Operations []BatchTaskOperation `json:"operations"`
+}
+
+// BatchTaskOperation struct. A create operation takes a CreateTaskReq as task, an update operation takes the id and the version of the task
+// and an UpdateTaskReq as task, and a delete operation takes the id and the version of the task and whether to delete its subtasks as well
+type BatchTaskOperation struct {
+	Op      string          `json:"op" enums:"create,update,delete"`
+	ID      string          `json:"id,omitempty"`
+	Version int             `json:"version,omitempty"`
+	Task    json.RawMessage `json:"task,omitempty" swaggertype:"object"`
+	Cascade bool            `json:"cascade,omitempty"`
+}
+
+// BatchTasksResp struct with the result of each operation, in the order they were received
+type BatchTasksResp struct {
+	Results []BatchTaskResult `json:"results"`
+}
+
+// BatchTaskResult struct with the ID of the task an operation was applied to, or with its error and the status code of the error
+type BatchTaskResult struct {
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Err    error  `json:"-"`
+}
+
+// TaskWrite struct with one of the writes of a bulk write over the tasks: the creation of Task, the replacement
+// of the task with the first of the IDs by Task, or the move of the tasks with the IDs to the trash at DeletedAt.
+// Each of the IDs is only written when its task is still at the version in the same position of Versions
+type TaskWrite struct {
+	Op        string
+	IDs       []string
+	Versions  []int
+	Task      interface{}
+	DeletedAt time.Time
+}
+
+// TaskWriteResult struct with the ID of the task created, updated or deleted by a write, or with the error of a failed write
+type TaskWriteResult struct {
+	ID  string
+	Err error
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AddCollaboratorReq struct
+type AddCollaboratorReq struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role" enums:"viewer,editor"`
+}
+
+// GetCollaboratorResp struct
+type GetCollaboratorResp struct {
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// CreateCommentReq struct
+type CreateCommentReq struct {
+	ID        string    `json:"-"`
+	TaskID    string    `json:"-"`
+	AuthorID  string    `json:"-"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// CreateCommentResp struct
+type CreateCommentResp struct {
+	ID string `json:"id"`
+}
+
+// GetCommentResp struct
+type GetCommentResp struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetCommentsReq struct
+type GetCommentsReq struct {
+	Limit  int
+	Cursor string
+}
+
+// GetCommentsResp struct
+type GetCommentsResp struct {
+	Comments   []GetCommentResp `json:"comments"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// CommentFilter struct with the criteria to find the comments of a task
+type CommentFilter struct {
+	TaskID string
+	Limit  int
+	After  *CommentCursor
+}
+
+// CommentCursor struct with the sort keys of the last comment of a page, encoded as an opaque cursor
+type CommentCursor struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UpdateCommentReq struct
+type UpdateCommentReq struct {
+	Body string `json:"body"`
+}
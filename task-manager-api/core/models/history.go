@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// GetTaskHistoryResp struct
+type GetTaskHistoryResp struct {
+	ID        string        `json:"id"`
+	TaskID    string        `json:"task_id"`
+	ActorID   string        `json:"actor_id"`
+	Action    string        `json:"action" enums:"created,updated,deleted,restored"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange struct with the value of a task field before and after a change
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
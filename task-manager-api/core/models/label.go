@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// CreateLabelReq struct
+type CreateLabelReq struct {
+	ID        string    `json:"-"`
+	UserID    string    `json:"-"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// CreateLabelResp struct
+type CreateLabelResp struct {
+	ID string `json:"id"`
+}
+
+// GetLabelResp struct
+type GetLabelResp struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RenameLabelReq struct
+type RenameLabelReq struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CreateProjectReq struct
+type CreateProjectReq struct {
+	ID        string    `json:"-"`
+	UserID    string    `json:"-"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color" example:"#4f46e5"`
+	Archived  bool      `json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// CreateProjectResp struct
+type CreateProjectResp struct {
+	ID string `json:"id"`
+}
+
+// GetProjectResp struct
+type GetProjectResp struct {
+	ID         string            `json:"id"`
+	UserID     string            `json:"user_id"`
+	Name       string            `json:"name"`
+	Color      string            `json:"color"`
+	Archived   bool              `json:"archived"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	TaskCounts ProjectTaskCounts `json:"task_counts"`
+}
+
+// ProjectTaskCounts struct with the number of tasks of a project, and how many of them are open and done
+type ProjectTaskCounts struct {
+	Total int `json:"total"`
+	Open  int `json:"open"`
+	Done  int `json:"done"`
+}
+
+// UpdateProjectReq struct
+type UpdateProjectReq struct {
+	Name     string `json:"name"`
+	Color    string `json:"color" example:"#4f46e5"`
+	Archived bool   `json:"archived"`
+}
@@ -0,0 +1,30 @@
+package models
+
+const (
+	RealtimeRoomTask    = "task"
+	RealtimeRoomProject = "project"
+
+	RealtimeReqSubscribe   = "subscribe"
+	RealtimeReqUnsubscribe = "unsubscribe"
+
+	RealtimeMessageSubscribed   = "subscribed"
+	RealtimeMessageUnsubscribed = "unsubscribed"
+	RealtimeMessagePresence     = "presence"
+	RealtimeMessageError        = "error"
+)
+
+// RealtimeReq struct with a message of a WebSocket client, subscribing to or unsubscribing from a room named task:{id} or project:{id}
+type RealtimeReq struct {
+	Type string `json:"type" enums:"subscribe,unsubscribe"`
+	Room string `json:"room"`
+}
+
+// RealtimeMessage struct with a message sent to a WebSocket client. Presence messages carry the users viewing the room,
+// and task.created, task.updated and task.deleted ones the task changed
+type RealtimeMessage struct {
+	Type    string       `json:"type" enums:"subscribed,unsubscribed,presence,task.created,task.updated,task.deleted,error"`
+	Room    string       `json:"room,omitempty"`
+	UserIDs []string     `json:"user_ids,omitempty"`
+	Task    *GetTaskResp `json:"task,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
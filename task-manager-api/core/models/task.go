@@ -4,11 +4,27 @@ import "time"
 
 // CreateTaskReq struct
 type CreateTaskReq struct {
-	ID          string    `json:"-"`
-	UserID      string    `json:"-"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"-"`
+	ID               string     `json:"-"`
+	UserID           string     `json:"-"`
+	CreatorID        string     `json:"-"`
+	AssigneeID       string     `json:"-"`
+	ProjectID        string     `json:"project_id,omitempty"`
+	ParentID         string     `json:"-"`
+	Position         int        `json:"-"`
+	Rank             string     `json:"-"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"-"`
+	Priority         string     `json:"priority" enums:"low,medium,high"`
+	Labels           []string   `json:"labels"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	Recurrence       string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	NextOccurrenceID string     `json:"-"`
+	CreatedAt        time.Time  `json:"-"`
+	UpdatedAt        time.Time  `json:"-"`
+	CompletedAt      *time.Time `json:"-"`
+	DeletedAt        *time.Time `json:"-"`
+	Version          int        `json:"-"`
 }
 
 // CreateTaskResp struct
@@ -18,9 +34,173 @@ type CreateTaskResp struct {
 
 // GetTaskResp struct
 type GetTaskResp struct {
-	ID          string    `json:"id"`
-	UserID      string    `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID               string     `json:"id"`
+	UserID           string     `json:"user_id"`
+	CreatorID        string     `json:"creator_id,omitempty"`
+	AssigneeID       string     `json:"assignee_id,omitempty"`
+	ProjectID        string     `json:"project_id,omitempty"`
+	ParentID         string     `json:"parent_id,omitempty"`
+	Position         int        `json:"position"`
+	Rank             string     `json:"rank,omitempty"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Priority         string     `json:"priority"`
+	Labels           []string   `json:"labels"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	Recurrence       string     `json:"recurrence,omitempty"`
+	NextOccurrenceID string     `json:"next_occurrence_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	Progress         *int       `json:"progress,omitempty"`
+	Version          int        `json:"version"`
+}
+
+// TaskChangeEvent struct with a change to a task of the user, streamed as a server-sent event of the received Type
+// with the task as data. Its ID resumes the stream right after it
+type TaskChangeEvent struct {
+	ID   string
+	Type string
+	Task GetTaskResp
+}
+
+// GetTasksReq struct
+type GetTasksReq struct {
+	Overdue    bool
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Labels     []string
+	LabelMatch string
+	Sort       string
+	Limit      int
+	Cursor     string
+	Query      string
+	Scope      string
+	ProjectID  string
+}
+
+// GetTasksResp struct
+type GetTasksResp struct {
+	Tasks      []GetTaskResp `json:"tasks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Matches    []TaskMatch   `json:"matches,omitempty"`
+}
+
+// TaskMatch struct with the relevance and the highlighted fragments of a task matching a text search
+type TaskMatch struct {
+	ID         string              `json:"id"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+// TaskFilter struct with the criteria to find the tasks of a user, or among the tasks with the received IDs or assigned to the received AssigneeID when they are set
+type TaskFilter struct {
+	UserID     string
+	IDs        []string
+	AssigneeID string
+	ProjectID  string
+	Statuses   []string
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Labels     []string
+	AllLabels  bool
+	Deleted    bool
+	SortBy     string
+	SortDesc   bool
+	Limit      int
+	After      *TaskCursor
+}
+
+// TaskCursor struct with the sort keys of the last task of a page, encoded as an opaque cursor
+type TaskCursor struct {
+	Sort      string     `json:"sort"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Rank      string     `json:"rank,omitempty"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UpdateTaskReq struct
+type UpdateTaskReq struct {
+	ProjectID   string     `json:"project_id,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    string     `json:"priority" enums:"low,medium,high"`
+	Labels      []string   `json:"labels"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+}
+
+// PatchTaskReq is a JSON Merge Patch document (RFC 7396) to be applied over an UpdateTaskReq
+type PatchTaskReq map[string]interface{}
+
+// TransitionTaskReq struct
+type TransitionTaskReq struct {
+	Status string `json:"status" enums:"todo,in_progress,blocked,done,archived"`
+}
+
+// MoveTaskReq struct with the board column of a task and its new neighbours in it. An empty status keeps the task in its column,
+// an empty before_id places it at the top of the column and an empty after_id at the bottom
+type MoveTaskReq struct {
+	Status   string `json:"status,omitempty" enums:"todo,in_progress,blocked,done,archived"`
+	BeforeID string `json:"before_id,omitempty"`
+	AfterID  string `json:"after_id,omitempty"`
+}
+
+// TaskColumn struct identifying the board column with the tasks of a user in a status
+type TaskColumn struct {
+	UserID string `bson:"user_id"`
+	Status string `bson:"status"`
+}
+
+// AssignTaskReq struct, where an empty assignee_id unassigns the task
+type AssignTaskReq struct {
+	AssigneeID string `json:"assignee_id"`
+}
+
+// ReorderSubtasksReq struct with all the subtask IDs of a task in the new order
+type ReorderSubtasksReq struct {
+	IDs []string `json:"ids"`
+}
+
+// SubtaskCount struct with the number of subtasks of a task and how many of them are done
+type SubtaskCount struct {
+	Total int
+	Done  int
+}
+
+// AddBlockerReq struct
+type AddBlockerReq struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+// GetTaskGraphResp struct with the dependency graph of a task, where each edge goes from a blocker to the task it blocks
+type GetTaskGraphResp struct {
+	Nodes []TaskGraphNode `json:"nodes"`
+	Edges []TaskGraphEdge `json:"edges"`
+}
+
+// TaskGraphNode struct
+type TaskGraphNode struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// TaskGraphEdge struct
+type TaskGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GetTaskStatsResp struct
+type GetTaskStatsResp struct {
+	Total    int            `json:"total"`
+	Open     int            `json:"open"`
+	Done     int            `json:"done"`
+	ByStatus map[string]int `json:"by_status"`
 }
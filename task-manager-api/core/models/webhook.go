@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookReq struct. The secret the deliveries are signed with is generated when not received
+type CreateWebhookReq struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events" enums:"task.created,task.updated,task.deleted"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// CreateWebhookResp struct with the secret the deliveries are signed with, which is not returned again afterwards
+type CreateWebhookResp struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// GetWebhookResp struct
+type GetWebhookResp struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetWebhookDeliveryResp struct
+type GetWebhookDeliveryResp struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// TaskEvent struct with a change made to a task, as delivered to the webhooks of its owner. Its ID is the ID of the history entry of the change
+type TaskEvent struct {
+	ID        string        `json:"id"`
+	Type      string        `json:"type" enums:"task.created,task.updated,task.deleted"`
+	TaskID    string        `json:"task_id"`
+	UserID    string        `json:"user_id"`
+	ActorID   string        `json:"actor_id"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// AttachmentRepository interface
+type AttachmentRepository interface {
+	repository.Repository
+	FindByTaskIDs(ctx context.Context, taskIDs []string) ([]interface{}, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+}
+
+// BlobStore interface
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentService interface
+type AttachmentService interface {
+	Upload(ctx context.Context, userID, taskID string, attachment models.UploadAttachmentReq, content io.Reader) (models.CreateAttachmentResp, error)
+	GetByTaskID(ctx context.Context, userID, taskID string) ([]models.GetAttachmentResp, error)
+	Download(ctx context.Context, userID, taskID, attachmentID string) (models.GetAttachmentResp, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, taskID, attachmentID string) error
+}
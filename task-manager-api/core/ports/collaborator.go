@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// TaskCollaboratorRepository interface
+type TaskCollaboratorRepository interface {
+	repository.Repository
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// CommentRepository interface
+type CommentRepository interface {
+	repository.Repository
+	Find(ctx context.Context, filter models.CommentFilter) ([]interface{}, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+}
+
+// CommentService interface
+type CommentService interface {
+	Create(ctx context.Context, userID, taskID string, comment models.CreateCommentReq, token string) (models.CreateCommentResp, error)
+	GetByTaskID(ctx context.Context, userID, taskID string, req models.GetCommentsReq) (models.GetCommentsResp, error)
+	Update(ctx context.Context, userID, taskID, commentID string, comment models.UpdateCommentReq) error
+	Delete(ctx context.Context, userID, taskID, commentID string) error
+}
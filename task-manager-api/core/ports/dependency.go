@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// TaskDependencyRepository interface
+type TaskDependencyRepository interface {
+	repository.Repository
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// TaskHistoryRepository interface
+type TaskHistoryRepository interface {
+	repository.Repository
+	FindByTaskID(ctx context.Context, taskID string) ([]interface{}, error)
+}
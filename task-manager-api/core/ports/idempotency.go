@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// IdempotencyKeyRepository interface
+type IdempotencyKeyRepository interface {
+	repository.Repository
+	Reserve(ctx context.Context, key entities.IdempotencyKey) (string, *entities.IdempotencyKey, error)
+}
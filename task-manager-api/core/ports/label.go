@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// LabelRepository interface
+type LabelRepository interface {
+	repository.Repository
+}
+
+// LabelService interface
+type LabelService interface {
+	Create(ctx context.Context, userID string, label models.CreateLabelReq) (models.CreateLabelResp, error)
+	GetByUserID(ctx context.Context, userID string) ([]models.GetLabelResp, error)
+	Rename(ctx context.Context, userID, labelID string, rename models.RenameLabelReq) error
+	Delete(ctx context.Context, userID, labelID string) error
+}
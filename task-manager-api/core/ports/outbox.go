@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// OutboxRepository interface
+type OutboxRepository interface {
+	repository.Repository
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (interface{}, error)
+}
+
+// EventPublisher interface
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.TaskEvent) error
+}
+
+// OutboxService interface
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+}
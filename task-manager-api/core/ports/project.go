@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// ProjectRepository interface
+type ProjectRepository interface {
+	repository.Repository
+}
+
+// ProjectService interface
+type ProjectService interface {
+	Create(ctx context.Context, userID string, project models.CreateProjectReq) (models.CreateProjectResp, error)
+	GetByUserID(ctx context.Context, userID string) ([]models.GetProjectResp, error)
+	GetByID(ctx context.Context, userID, projectID string) (models.GetProjectResp, error)
+	Update(ctx context.Context, userID, projectID string, project models.UpdateProjectReq) error
+	Delete(ctx context.Context, userID, projectID, tasks string) error
+}
@@ -2,7 +2,9 @@ package ports
 
 import (
 	"context"
+	"time"
 
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
 	"github.com/sergicanet9/scv-go-tools/v4/repository"
 )
@@ -10,11 +12,65 @@ import (
 // TaskRepository interface
 type TaskRepository interface {
 	repository.Repository
+	Find(ctx context.Context, filter models.TaskFilter) ([]interface{}, error)
+	FindByIDs(ctx context.Context, ids []string) ([]interface{}, error)
+	Search(ctx context.Context, filter models.TaskFilter, text string) ([]interface{}, error)
+	CountByStatus(ctx context.Context, userID string) (map[string]int, error)
+	CountSubtasks(ctx context.Context, parentIDs []string) (map[string]models.SubtaskCount, error)
+	CountByProject(ctx context.Context, projectIDs []string) (map[string]models.ProjectTaskCounts, error)
+	FirstRank(ctx context.Context, userID, status string) (string, error)
+	LastRank(ctx context.Context, userID, status string) (string, error)
+	CountRank(ctx context.Context, userID, status, rank string) (int, error)
+	FindRankColumns(ctx context.Context, maxLength, limit int) ([]models.TaskColumn, error)
+	FindColumn(ctx context.Context, userID, status string) ([]interface{}, error)
+	BulkWrite(ctx context.Context, writes []models.TaskWrite) ([]models.TaskWriteResult, error)
+	FindPendingOccurrences(ctx context.Context, dueBefore time.Time, excludedIDs []string, limit int) ([]interface{}, error)
+	UpdateIfVersion(ctx context.Context, id string, entity interface{}, version int) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error)
+	Watch(ctx context.Context, userID, resumeToken string, maxAwaitTime time.Duration) (TaskChangeStream, error)
+}
+
+// TaskChangeStream interface
+type TaskChangeStream interface {
+	Next(ctx context.Context) (*entities.TaskChange, error)
+	Close(ctx context.Context) error
+}
+
+// TaskEventStream interface
+type TaskEventStream interface {
+	Next(ctx context.Context) (*models.TaskChangeEvent, error)
+	Close(ctx context.Context) error
 }
 
 // TaskService interface
 type TaskService interface {
 	Create(ctx context.Context, userID string, task models.CreateTaskReq, token string) (models.CreateTaskResp, error)
-	GetByUserID(ctx context.Context, userID string) ([]models.GetTaskResp, error)
-	Delete(ctx context.Context, userID, taskID string) error
+	Batch(ctx context.Context, userID string, batch models.BatchTasksReq, token string) (models.BatchTasksResp, error)
+	CreateSubtask(ctx context.Context, userID, parentID string, task models.CreateTaskReq, token string) (models.CreateTaskResp, error)
+	Get(ctx context.Context, userID, taskID string) (models.GetTaskResp, error)
+	GetByUserID(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error)
+	Search(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error)
+	GetSubtasks(ctx context.Context, userID, taskID string) ([]models.GetTaskResp, error)
+	GetStats(ctx context.Context, userID string) (models.GetTaskStatsResp, error)
+	GetTrash(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error)
+	GetAssigned(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error)
+	Update(ctx context.Context, userID, taskID string, version int, task models.UpdateTaskReq) (int, error)
+	Patch(ctx context.Context, userID, taskID string, version int, patch models.PatchTaskReq) (int, error)
+	Transition(ctx context.Context, userID, taskID string, version int, transition models.TransitionTaskReq) (int, error)
+	Move(ctx context.Context, userID, taskID string, version int, move models.MoveTaskReq) (int, error)
+	Assign(ctx context.Context, userID, taskID string, version int, assignment models.AssignTaskReq, token string) (int, error)
+	ReorderSubtasks(ctx context.Context, userID, taskID string, version int, reorder models.ReorderSubtasksReq) (int, error)
+	AddBlocker(ctx context.Context, userID, taskID string, version int, blocker models.AddBlockerReq) (int, error)
+	RemoveBlocker(ctx context.Context, userID, taskID string, version int, blockerID string) (int, error)
+	GetGraph(ctx context.Context, userID, taskID string) (models.GetTaskGraphResp, error)
+	GetHistory(ctx context.Context, userID, taskID string) ([]models.GetTaskHistoryResp, error)
+	AddCollaborator(ctx context.Context, userID, taskID string, collaborator models.AddCollaboratorReq, token string) error
+	RemoveCollaborator(ctx context.Context, userID, taskID, collaboratorID string) error
+	GetCollaborators(ctx context.Context, userID, taskID string) ([]models.GetCollaboratorResp, error)
+	Delete(ctx context.Context, userID, taskID string, version int, cascade bool) error
+	Restore(ctx context.Context, userID, taskID string, version int) (int, error)
+	MaterializeOccurrences(ctx context.Context) (int, error)
+	PurgeTrash(ctx context.Context) (int, error)
+	RebalanceRanks(ctx context.Context) (int, error)
+	Stream(ctx context.Context, userID, lastEventID string) (TaskEventStream, error)
 }
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// TransactionManager interface
+type TransactionManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
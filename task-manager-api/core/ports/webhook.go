@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/repository"
+)
+
+// WebhookRepository interface
+type WebhookRepository interface {
+	repository.Repository
+}
+
+// WebhookDeliveryRepository interface
+type WebhookDeliveryRepository interface {
+	repository.Repository
+	Enqueue(ctx context.Context, delivery entities.WebhookDelivery) error
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (interface{}, error)
+	DeleteByWebhookID(ctx context.Context, webhookID string) error
+}
+
+// WebhookClient interface
+type WebhookClient interface {
+	Send(ctx context.Context, url string, header map[string]string, body []byte) (int, error)
+	CheckURL(ctx context.Context, url string) error
+}
+
+// WebhookService interface
+type WebhookService interface {
+	Create(ctx context.Context, userID string, webhook models.CreateWebhookReq) (models.CreateWebhookResp, error)
+	GetByUserID(ctx context.Context, userID string) ([]models.GetWebhookResp, error)
+	Delete(ctx context.Context, userID, webhookID string) error
+	GetDeadLetters(ctx context.Context, userID, webhookID string) ([]models.GetWebhookDeliveryResp, error)
+	Redeliver(ctx context.Context, userID, webhookID, deliveryID string) error
+	Publish(ctx context.Context, event models.TaskEvent) error
+	DeliverDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// Assign assigns a task to another user, or unassigns it when no assignee is received. The assignee becomes an editor of the task and its subtasks.
+// It returns the new version of the task
+func (t *taskService) Assign(ctx context.Context, userID, taskID string, version int, assignment models.AssignTaskReq, token string) (newVersion int, err error) {
+	entity, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleOwner, "assign")
+	if err != nil {
+		return
+	}
+	if entity.AssigneeID == assignment.AssigneeID {
+		return entity.Version, nil
+	}
+
+	if assignment.AssigneeID != "" {
+		exists, err := t.userManagementClient.Exists(ctx, token, assignment.AssigneeID)
+		if err != nil {
+			return newVersion, err
+		}
+		if !exists {
+			return newVersion, wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", assignment.AssigneeID))
+		}
+	}
+
+	before := *entity
+	entity.AssigneeID = assignment.AssigneeID
+	entity.UpdatedAt = time.Now().UTC()
+	err = t.writer.saveChanges(ctx, userID, before, entity)
+	return entity.Version, err
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestAssign_Ok checks that Assign sets the assignee of the task when the assignee exists
+func TestAssign_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title"}
+	req := models.AssignTaskReq{AssigneeID: "user-456"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.AssigneeID == req.AssigneeID && e.UserID == task.UserID && !e.UpdatedAt.IsZero()
+	}), mock.Anything).Return(nil).Once()
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskHistory) bool {
+		return len(e.Changes) == 1 && e.Changes[0].Field == "assignee_id" && e.Changes[0].To == req.AssigneeID
+	})).Return("history-id", nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, "token", req.AssigneeID).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		historyRepository:    historyRepositoryMock,
+		writer:               NewTaskWriter(taskRepositoryMock, historyRepositoryMock, newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Assign(context.Background(), task.UserID, task.ID, 0, req, "token")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAssign_Unassign checks that Assign removes the assignee of the task when no assignee is received
+func TestAssign_Unassign(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", AssigneeID: "user-456"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.AssigneeID == ""
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Assign(context.Background(), task.UserID, task.ID, 0, models.AssignTaskReq{}, "token")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAssign_AssigneeNotFound checks that Assign returns a non existent error when the assignee does not exist
+func TestAssign_AssigneeNotFound(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Assign(context.Background(), task.UserID, task.ID, 0, models.AssignTaskReq{AssigneeID: "user-456"}, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestGetAssigned_Ok checks that GetAssigned finds the tasks assigned to the user instead of the ones it owns
+func TestGetAssigned_Ok(t *testing.T) {
+	// Arrange
+	userID := "user-456"
+	task := entities.Task{ID: "task-id", UserID: "user-123", AssigneeID: userID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.UserID == "" && f.AssigneeID == userID && f.SortBy == "due_at"
+	})).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetAssigned(context.Background(), userID, models.GetTasksReq{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Tasks, 1)
+	assert.Equal(t, userID, resp.Tasks[0].AssigneeID)
+}
+
+// TestUpdate_Assignee checks that Update succeeds when the user is the assignee of the task
+func TestUpdate_Assignee(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", AssigneeID: "user-456", Title: "title"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.Anything, mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.AssigneeID, task.ID, 0, models.UpdateTaskReq{Title: "new-title"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDelete_Assignee checks that Delete returns an unauthenticated error when the user is the assignee but not the owner of the task
+func TestDelete_Assignee(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", AssigneeID: "user-456"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), task.AssigneeID, task.ID, 0, false)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthenticatedErr))
+	assert.Contains(t, err.Error(), "only its owner can delete it")
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const maxAttachmentFileNameLength = 255
+
+// attachmentService adapter of an attachment service
+type attachmentService struct {
+	config                 config.Config
+	repository             ports.AttachmentRepository
+	taskRepository         ports.TaskRepository
+	collaboratorRepository ports.TaskCollaboratorRepository
+	blobStore              ports.BlobStore
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(cfg config.Config, repo ports.AttachmentRepository, taskRepo ports.TaskRepository, collaboratorRepo ports.TaskCollaboratorRepository, blobStore ports.BlobStore) ports.AttachmentService {
+	return &attachmentService{
+		config:                 cfg,
+		repository:             repo,
+		taskRepository:         taskRepo,
+		collaboratorRepository: collaboratorRepo,
+		blobStore:              blobStore,
+	}
+}
+
+// Upload stores the received content in the blob store and attaches it to a task the received userID can edit
+func (a *attachmentService) Upload(ctx context.Context, userID, taskID string, attachment models.UploadAttachmentReq, content io.Reader) (resp models.CreateAttachmentResp, err error) {
+	_, err = getTaskWithRole(ctx, a.taskRepository, a.collaboratorRepository, userID, taskID, entities.TaskRoleEditor, "attach files to")
+	if err != nil {
+		return
+	}
+
+	fileName, err := validateAttachmentFileName(attachment.FileName)
+	if err != nil {
+		return
+	}
+
+	contentType, err := a.validateAttachmentContentType(attachment.ContentType)
+	if err != nil {
+		return
+	}
+
+	key, err := newBlobKey()
+	if err != nil {
+		return
+	}
+
+	// one byte over the limit is enough to know that the content is too large
+	maxSize := a.config.Attachments.MaxSize
+	size, err := a.blobStore.Put(ctx, key, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return
+	}
+	if size == 0 || size > maxSize {
+		_ = a.blobStore.Delete(ctx, key)
+		if size == 0 {
+			return resp, wrappers.NewValidationErr(errors.New("attachment cannot be empty"))
+		}
+		return resp, wrappers.NewValidationErr(fmt.Errorf("attachment exceeds the maximum size of %d bytes", maxSize))
+	}
+
+	entity := entities.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		BlobKey:     key,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	id, err := a.repository.Create(ctx, entity)
+	if err != nil {
+		_ = a.blobStore.Delete(ctx, key)
+		return
+	}
+
+	resp = models.CreateAttachmentResp{
+		ID: id,
+	}
+
+	return
+}
+
+// GetByTaskID gets the attachments of a task, the oldest first
+func (a *attachmentService) GetByTaskID(ctx context.Context, userID, taskID string) (resp []models.GetAttachmentResp, err error) {
+	resp = []models.GetAttachmentResp{}
+	_, err = getTaskWithRole(ctx, a.taskRepository, a.collaboratorRepository, userID, taskID, entities.TaskRoleViewer, "get the attachments of")
+	if err != nil {
+		return
+	}
+
+	result, err := a.repository.FindByTaskIDs(ctx, []string{taskID})
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	resp = make([]models.GetAttachmentResp, len(result))
+	for i, v := range result {
+		resp[i] = models.GetAttachmentResp(*(v.(*entities.Attachment)))
+	}
+
+	return
+}
+
+// Download gets the metadata of an attachment along with a reader of its content, which must be closed by the caller
+func (a *attachmentService) Download(ctx context.Context, userID, taskID, attachmentID string) (models.GetAttachmentResp, io.ReadCloser, error) {
+	attachment, err := a.getTaskAttachment(ctx, userID, taskID, attachmentID, entities.TaskRoleViewer, "download attachments of")
+	if err != nil {
+		return models.GetAttachmentResp{}, nil, err
+	}
+
+	content, err := a.blobStore.Get(ctx, attachment.BlobKey)
+	if err != nil {
+		return models.GetAttachmentResp{}, nil, err
+	}
+
+	return models.GetAttachmentResp(*attachment), content, nil
+}
+
+// Delete removes an attachment and its content
+func (a *attachmentService) Delete(ctx context.Context, userID, taskID, attachmentID string) (err error) {
+	attachment, err := a.getTaskAttachment(ctx, userID, taskID, attachmentID, entities.TaskRoleEditor, "delete attachments of")
+	if err != nil {
+		return
+	}
+
+	err = a.blobStore.Delete(ctx, attachment.BlobKey)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+
+	err = a.repository.Delete(ctx, attachmentID)
+	return
+}
+
+// getTaskAttachment gets an attachment of a task, checking that the received userID has at least the received role on the task
+func (a *attachmentService) getTaskAttachment(ctx context.Context, userID, taskID, attachmentID, role, action string) (*entities.Attachment, error) {
+	_, err := getTaskWithRole(ctx, a.taskRepository, a.collaboratorRepository, userID, taskID, role, action)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.repository.GetByID(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("AttachmentID %s not found", attachmentID))
+		}
+		return nil, err
+	}
+
+	attachment := result.(*entities.Attachment)
+	if attachment.TaskID != taskID {
+		return nil, wrappers.NewNonExistentErr(fmt.Errorf("AttachmentID %s not found in TaskID %s", attachmentID, taskID))
+	}
+
+	return attachment, nil
+}
+
+// validateAttachmentContentType checks that the media type of the received content type is in the configured allow-list
+func (a *attachmentService) validateAttachmentContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", wrappers.NewValidationErr(fmt.Errorf("content type %q not valid", contentType))
+	}
+	if !slices.Contains(a.config.Attachments.AllowedContentTypes, mediaType) {
+		return "", wrappers.NewValidationErr(fmt.Errorf("content type %s not allowed, it must be one of %s", mediaType, strings.Join(a.config.Attachments.AllowedContentTypes, ", ")))
+	}
+	return mediaType, nil
+}
+
+// validateAttachmentFileName strips any directory from the received file name and checks that it is not empty nor too long
+func validateAttachmentFileName(fileName string) (string, error) {
+	fileName = strings.TrimSpace(path.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "", wrappers.NewValidationErr(errors.New("attachment file name cannot be empty"))
+	}
+	if utf8.RuneCountInString(fileName) > maxAttachmentFileNameLength {
+		return "", wrappers.NewValidationErr(fmt.Errorf("attachment file name cannot be longer than %d characters", maxAttachmentFileNameLength))
+	}
+	return fileName, nil
+}
+
+// newBlobKey generates a random key under which an attachment content is stored
+func newBlobKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestUploadAttachment_Ok checks that Upload stores the content and returns the ID of the new attachment when a valid request is received
+func TestUploadAttachment_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	content := "plain text content"
+	expectedResponse := models.CreateAttachmentResp{ID: "attachment-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	var blobKey string
+	blobStoreMock := mocks.NewBlobStore(t)
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Put), mock.Anything, mock.Anything, mock.Anything).Return(int64(len(content)), nil).Run(func(args mock.Arguments) {
+		blobKey = args.String(1)
+	}).Once()
+
+	attachmentRepositoryMock := mocks.NewAttachmentRepository(t)
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.Create), mock.Anything, mock.MatchedBy(func(attachment entities.Attachment) bool {
+		return attachment.TaskID == task.ID && attachment.UserID == task.UserID && attachment.FileName == "notes.txt" &&
+			attachment.ContentType == "text/plain" && attachment.Size == int64(len(content)) && attachment.BlobKey == blobKey
+	})).Return(expectedResponse.ID, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Attachments = config.Attachments{MaxSize: 1024, AllowedContentTypes: []string{"text/plain"}}
+	service := &attachmentService{
+		config:         cfg,
+		repository:     attachmentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		blobStore:      blobStoreMock,
+	}
+	req := models.UploadAttachmentReq{FileName: "../docs/notes.txt", ContentType: "text/plain; charset=utf-8"}
+
+	// Act
+	resp, err := service.Upload(context.Background(), task.UserID, task.ID, req, strings.NewReader(content))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestUploadAttachment_TooLarge checks that Upload discards the stored content and returns a validation error when it exceeds the maximum size
+func TestUploadAttachment_TooLarge(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	blobStoreMock := mocks.NewBlobStore(t)
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Put), mock.Anything, mock.Anything, mock.Anything).Return(func(_ context.Context, _ string, content io.Reader) (int64, error) {
+		return io.Copy(io.Discard, content)
+	}).Once()
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Delete), mock.Anything, mock.Anything).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Attachments = config.Attachments{MaxSize: 4, AllowedContentTypes: []string{"text/plain"}}
+	service := &attachmentService{
+		config:         cfg,
+		taskRepository: taskRepositoryMock,
+		blobStore:      blobStoreMock,
+	}
+	req := models.UploadAttachmentReq{FileName: "notes.txt", ContentType: "text/plain"}
+
+	// Act
+	_, err := service.Upload(context.Background(), task.UserID, task.ID, req, strings.NewReader("more than four bytes"))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestUploadAttachment_ContentTypeNotAllowed checks that Upload returns a validation error when the content type is not in the allow-list
+func TestUploadAttachment_ContentTypeNotAllowed(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Attachments = config.Attachments{MaxSize: 1024, AllowedContentTypes: []string{"text/plain"}}
+	service := &attachmentService{
+		config:         cfg,
+		taskRepository: taskRepositoryMock,
+	}
+	req := models.UploadAttachmentReq{FileName: "script.sh", ContentType: "application/x-sh"}
+
+	// Act
+	_, err := service.Upload(context.Background(), task.UserID, task.ID, req, strings.NewReader("echo"))
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestUploadAttachment_RepositoryError checks that Upload discards the stored content when the attachment cannot be saved
+func TestUploadAttachment_RepositoryError(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	expectedError := "repository-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	blobStoreMock := mocks.NewBlobStore(t)
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Put), mock.Anything, mock.Anything, mock.Anything).Return(int64(4), nil).Once()
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Delete), mock.Anything, mock.Anything).Return(nil).Once()
+
+	attachmentRepositoryMock := mocks.NewAttachmentRepository(t)
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.Create), mock.Anything, mock.Anything).Return("", errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	cfg.Attachments = config.Attachments{MaxSize: 1024, AllowedContentTypes: []string{"text/plain"}}
+	service := &attachmentService{
+		config:         cfg,
+		repository:     attachmentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		blobStore:      blobStoreMock,
+	}
+	req := models.UploadAttachmentReq{FileName: "notes.txt", ContentType: "text/plain"}
+
+	// Act
+	_, err := service.Upload(context.Background(), task.UserID, task.ID, req, strings.NewReader("text"))
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDownloadAttachment_OtherTask checks that Download returns a non existent error when the attachment belongs to another task
+func TestDownloadAttachment_OtherTask(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	attachment := entities.Attachment{ID: "attachment-id", TaskID: "another-task-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	attachmentRepositoryMock := mocks.NewAttachmentRepository(t)
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.GetByID), mock.Anything, attachment.ID).Return(&attachment, nil).Once()
+
+	service := &attachmentService{
+		config:         config.Config{},
+		repository:     attachmentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	_, _, err := service.Download(context.Background(), task.UserID, task.ID, attachment.ID)
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestDeleteAttachment_Ok checks that Delete removes both the content and the attachment
+func TestDeleteAttachment_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	attachment := entities.Attachment{ID: "attachment-id", TaskID: task.ID, BlobKey: "blob-key"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	attachmentRepositoryMock := mocks.NewAttachmentRepository(t)
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.GetByID), mock.Anything, attachment.ID).Return(&attachment, nil).Once()
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.Delete), mock.Anything, attachment.ID).Return(nil).Once()
+
+	blobStoreMock := mocks.NewBlobStore(t)
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Delete), mock.Anything, attachment.BlobKey).Return(nil).Once()
+
+	service := &attachmentService{
+		config:         config.Config{},
+		repository:     attachmentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		blobStore:      blobStoreMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), task.UserID, task.ID, attachment.ID)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// batchWrite is a write of a batch of operations along with what is needed to finish it once it has been applied
+type batchWrite struct {
+	index          int
+	write          models.TaskWrite
+	before         entities.Task
+	deleted        []entities.Task
+	projectChanged bool
+}
+
+// Batch applies up to the configured maximum of create, update and delete operations in a single bulk write, checking the
+// permissions on each task the same way as the single task operations do. Each operation passes or fails these checks on its own,
+// while the operations passing them are written all at once or not at all: when any of their writes is rejected, such as because
+// its task has been modified since it was checked, none of them is applied and the error of the rejected write is returned
+func (t *taskService) Batch(ctx context.Context, userID string, batch models.BatchTasksReq, token string) (resp models.BatchTasksResp, err error) {
+	if len(batch.Operations) == 0 || len(batch.Operations) > t.config.Batch.MaxOperations {
+		return resp, wrappers.NewValidationErr(fmt.Errorf("a batch must have between 1 and %d operations", t.config.Batch.MaxOperations))
+	}
+
+	// the user and the bottom of the todo column are only looked up once for all the tasks created
+	lastRank := ""
+	if hasBatchOp(batch, models.BatchOpCreate) {
+		exists, err := t.userManagementClient.Exists(ctx, token, userID)
+		if err != nil {
+			return resp, err
+		}
+		if !exists {
+			return resp, wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", userID))
+		}
+
+		lastRank, err = t.repository.LastRank(ctx, userID, entities.TaskStatusTodo)
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	resp.Results = make([]models.BatchTaskResult, len(batch.Operations))
+
+	writes := []batchWrite{}
+	seen := map[string]int{}
+	now := time.Now().UTC()
+	for i, operation := range batch.Operations {
+		if previous, ok := seen[operation.ID]; ok && operation.ID != "" {
+			resp.Results[i].Err = wrappers.NewValidationErr(fmt.Errorf("TaskID %s is already in operation %d", operation.ID, previous))
+			continue
+		}
+		seen[operation.ID] = i
+
+		write, err := t.prepareBatchWrite(ctx, userID, operation, now, &lastRank)
+		if err != nil {
+			resp.Results[i].Err = err
+			continue
+		}
+		write.index = i
+		writes = append(writes, write)
+	}
+	if len(writes) == 0 {
+		return
+	}
+
+	taskWrites := make([]models.TaskWrite, len(writes))
+	for i, w := range writes {
+		taskWrites[i] = w.write
+	}
+	// the transaction may be retried, so the results are only taken from its last run once it has been committed
+	var results []models.TaskWriteResult
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = t.repository.BulkWrite(ctx, taskWrites)
+		if err != nil {
+			return err
+		}
+
+		for i, w := range writes {
+			if results[i].Err != nil {
+				return results[i].Err
+			}
+			err = t.finishBatchWrite(ctx, userID, w, results[i].ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	for i, w := range writes {
+		resp.Results[w.index].ID = results[i].ID
+	}
+	return
+}
+
+// hasBatchOp checks whether any of the operations of a batch is of the received kind
+func hasBatchOp(batch models.BatchTasksReq, op string) bool {
+	for _, operation := range batch.Operations {
+		if operation.Op == op {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareBatchWrite validates an operation of a batch and builds its write. The tasks created are placed one after the other
+// at the bottom of the todo column, after the received last rank
+func (t *taskService) prepareBatchWrite(ctx context.Context, userID string, operation models.BatchTaskOperation, now time.Time, lastRank *string) (w batchWrite, err error) {
+	w.write.Op = operation.Op
+	switch operation.Op {
+	case models.BatchOpCreate:
+		var task models.CreateTaskReq
+		err = unmarshalBatchTask(operation, &task)
+		if err != nil {
+			return
+		}
+
+		var entity entities.Task
+		entity, err = t.newTask(ctx, userID, userID, task)
+		if err != nil {
+			return
+		}
+		entity.CreatedAt = now
+		entity.UpdatedAt = now
+
+		entity.Rank, err = rankBetween(*lastRank, "")
+		if err != nil {
+			return
+		}
+		*lastRank = entity.Rank
+		w.write.Task = entity
+
+	case models.BatchOpUpdate:
+		var task models.UpdateTaskReq
+		err = unmarshalBatchTask(operation, &task)
+		if err != nil {
+			return
+		}
+
+		var entity *entities.Task
+		entity, err = t.getVersionedTask(ctx, userID, operation.ID, operation.Version, entities.TaskRoleEditor, "update")
+		if err != nil {
+			return
+		}
+
+		w.before = *entity
+		w.projectChanged, err = t.applyUpdate(ctx, entity, task)
+		if err != nil {
+			return
+		}
+		w.write.IDs = []string{operation.ID}
+		w.write.Versions = []int{w.before.Version}
+		w.write.Task = *entity
+
+	case models.BatchOpDelete:
+		w.deleted, err = t.getDeletableTasks(ctx, userID, operation.ID, operation.Version, operation.Cascade)
+		for _, task := range w.deleted {
+			w.write.IDs = append(w.write.IDs, task.ID)
+			w.write.Versions = append(w.write.Versions, task.Version)
+		}
+		w.write.DeletedAt = now
+
+	default:
+		err = wrappers.NewValidationErr(fmt.Errorf("op %s not valid, allowed values: %s, %s, %s", operation.Op, models.BatchOpCreate, models.BatchOpUpdate, models.BatchOpDelete))
+	}
+	return
+}
+
+// finishBatchWrite records the history of an applied write of a batch and moves the subtasks of the updated tasks to their new project
+func (t *taskService) finishBatchWrite(ctx context.Context, userID string, w batchWrite, taskID string) error {
+	switch w.write.Op {
+	case models.BatchOpCreate:
+		return t.writer.recordHistory(ctx, userID, userID, entities.TaskHistoryActionCreated, taskID, diffTasks(entities.Task{}, w.write.Task.(entities.Task)))
+
+	case models.BatchOpUpdate:
+		after := w.write.Task.(entities.Task)
+		err := t.writer.recordHistory(ctx, userID, after.UserID, entities.TaskHistoryActionUpdated, after.ID, diffTasks(w.before, after))
+		if err != nil || !w.projectChanged {
+			return err
+		}
+		return t.cascadeProject(ctx, userID, &after)
+
+	default:
+		for _, before := range w.deleted {
+			after := before
+			after.DeletedAt = &w.write.DeletedAt
+			err := t.writer.recordHistory(ctx, userID, after.UserID, entities.TaskHistoryActionDeleted, after.ID, diffTasks(before, after))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// unmarshalBatchTask decodes the task of an operation of a batch
+func unmarshalBatchTask(operation models.BatchTaskOperation, task interface{}) error {
+	if len(operation.Task) == 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("task is required for op %s", operation.Op))
+	}
+	if err := json.Unmarshal(operation.Task, task); err != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("task not valid: %w", err))
+	}
+	return nil
+}
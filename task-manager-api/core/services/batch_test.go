@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newBatchConfig creates a config allowing batches of up to 10 operations
+func newBatchConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Batch = config.Batch{MaxOperations: 10}
+	return cfg
+}
+
+// TestBatch_Ok checks that Batch applies the create, update and delete operations in a single bulk write, checking the user only once
+func TestBatch_Ok(t *testing.T) {
+	// Arrange
+	userID := "user-123"
+	updated := entities.Task{ID: "task-1", UserID: userID, Title: "title", Status: entities.TaskStatusTodo}
+	deleted := entities.Task{ID: "task-2", UserID: userID, Title: "title", Status: entities.TaskStatusTodo}
+	create, _ := json.Marshal(models.CreateTaskReq{Title: "new"})
+	update, _ := json.Marshal(models.UpdateTaskReq{Title: "new-title"})
+	batch := models.BatchTasksReq{Operations: []models.BatchTaskOperation{
+		{Op: models.BatchOpCreate, Task: create},
+		{Op: models.BatchOpCreate, Task: create},
+		{Op: models.BatchOpUpdate, ID: updated.ID, Task: update},
+		{Op: models.BatchOpDelete, ID: deleted.ID},
+	}}
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, "token", userID).Return(true, nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, userID, entities.TaskStatusTodo).Return("V", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, updated.ID).Return(&updated, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, deleted.ID).Return(&deleted, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.BulkWrite), mock.Anything, mock.MatchedBy(func(writes []models.TaskWrite) bool {
+		return len(writes) == 4 &&
+			writes[0].Task.(entities.Task).Rank == "W" && writes[1].Task.(entities.Task).Rank == "X" &&
+			writes[2].Task.(entities.Task).Title == "new-title" &&
+			writes[3].IDs[0] == deleted.ID
+	})).Return([]models.TaskWriteResult{{ID: "new-1"}, {ID: "new-2"}, {ID: updated.ID}, {ID: deleted.ID}}, nil).Once()
+
+	service := &taskService{
+		config:               newBatchConfig(),
+		repository:           taskRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	resp, err := service.Batch(context.Background(), userID, batch, "token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Results, 4)
+	for i, id := range []string{"new-1", "new-2", updated.ID, deleted.ID} {
+		assert.Nil(t, resp.Results[i].Err)
+		assert.Equal(t, id, resp.Results[i].ID)
+	}
+}
+
+// TestBatch_TooManyOperations checks that Batch returns a validation error when the batch exceeds the configured maximum
+func TestBatch_TooManyOperations(t *testing.T) {
+	// Arrange
+	batch := models.BatchTasksReq{Operations: make([]models.BatchTaskOperation, 11)}
+
+	service := &taskService{
+		config: newBatchConfig(),
+	}
+
+	// Act
+	_, err := service.Batch(context.Background(), "user-123", batch, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestBatch_ItemErrors checks that Batch reports the error of each operation failing its checks while applying the rest of them
+func TestBatch_ItemErrors(t *testing.T) {
+	// Arrange
+	userID := "user-123"
+	owned := entities.Task{ID: "task-1", UserID: userID, Status: entities.TaskStatusTodo}
+	foreign := entities.Task{ID: "task-2", UserID: "user-456", Status: entities.TaskStatusTodo}
+	batch := models.BatchTasksReq{Operations: []models.BatchTaskOperation{
+		{Op: "invalid-op"},
+		{Op: models.BatchOpUpdate, ID: "task-3"},
+		{Op: models.BatchOpDelete, ID: foreign.ID},
+		{Op: models.BatchOpDelete, ID: owned.ID},
+		{Op: models.BatchOpDelete, ID: owned.ID},
+	}}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, foreign.ID).Return(&foreign, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, owned.ID).Return(&owned, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.BulkWrite), mock.Anything, mock.MatchedBy(func(writes []models.TaskWrite) bool {
+		return len(writes) == 1 && writes[0].IDs[0] == owned.ID
+	})).Return([]models.TaskWriteResult{{ID: owned.ID}}, nil).Once()
+
+	service := &taskService{
+		config:                 newBatchConfig(),
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+	}
+
+	// Act
+	resp, err := service.Batch(context.Background(), userID, batch, "token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(resp.Results[0].Err, wrappers.ValidationErr))
+	assert.True(t, errors.Is(resp.Results[1].Err, wrappers.ValidationErr))
+	assert.True(t, errors.Is(resp.Results[2].Err, wrappers.UnauthenticatedErr))
+	assert.Nil(t, resp.Results[3].Err)
+	assert.Equal(t, owned.ID, resp.Results[3].ID)
+	assert.True(t, errors.Is(resp.Results[4].Err, wrappers.ValidationErr))
+}
+
+// TestBatch_WriteRejected checks that Batch applies none of the operations and returns the error of the write rejected when any write is rejected
+func TestBatch_WriteRejected(t *testing.T) {
+	// Arrange
+	userID := "user-123"
+	first := entities.Task{ID: "task-1", UserID: userID, Version: 2}
+	second := entities.Task{ID: "task-2", UserID: userID, Version: 5}
+	batch := models.BatchTasksReq{Operations: []models.BatchTaskOperation{
+		{Op: models.BatchOpDelete, ID: first.ID, Version: first.Version},
+		{Op: models.BatchOpDelete, ID: second.ID, Version: second.Version},
+	}}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, first.ID).Return(&first, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, second.ID).Return(&second, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Twice()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.BulkWrite), mock.Anything, mock.MatchedBy(func(writes []models.TaskWrite) bool {
+		return len(writes) == 2 && writes[0].Versions[0] == first.Version && writes[1].Versions[0] == second.Version
+	})).Return([]models.TaskWriteResult{{ID: first.ID}, {ID: second.ID, Err: taskWrappers.NewPreconditionFailedErr(errors.New("modified"))}}, nil).Once()
+
+	service := &taskService{
+		config:            newBatchConfig(),
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	resp, err := service.Batch(context.Background(), userID, batch, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, taskWrappers.PreconditionFailedErr))
+	for _, result := range resp.Results {
+		assert.Empty(t, result.ID)
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// taskRoleRanks orders the roles a user can have on a task, from the least to the most privileged
+var taskRoleRanks = map[string]int{
+	entities.TaskRoleViewer: 1,
+	entities.TaskRoleEditor: 2,
+	entities.TaskRoleOwner:  3,
+}
+
+// AddCollaborator grants a user a role on a task and its subtasks, or changes the role already granted
+func (t *taskService) AddCollaborator(ctx context.Context, userID, taskID string, collaborator models.AddCollaboratorReq, token string) (err error) {
+	task, err := t.getOwnedTask(ctx, userID, taskID, "share")
+	if err != nil {
+		return
+	}
+
+	if collaborator.Role != entities.TaskRoleViewer && collaborator.Role != entities.TaskRoleEditor {
+		return wrappers.NewValidationErr(fmt.Errorf("role %s not valid, allowed values: %s, %s", collaborator.Role, entities.TaskRoleViewer, entities.TaskRoleEditor))
+	}
+	if collaborator.UserID == "" {
+		return wrappers.NewValidationErr(errors.New("user_id cannot be empty"))
+	}
+	if collaborator.UserID == task.UserID {
+		return wrappers.NewValidationErr(fmt.Errorf("UserID %s already owns TaskID %s", collaborator.UserID, taskID))
+	}
+
+	exists, err := t.userManagementClient.Exists(ctx, token, collaborator.UserID)
+	if err != nil {
+		return
+	}
+	if !exists {
+		return wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", collaborator.UserID))
+	}
+
+	collaborators, err := getCollaborators(ctx, t.collaboratorRepository, map[string]interface{}{"task_id": taskID, "user_id": collaborator.UserID})
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	if len(collaborators) > 0 {
+		entity := collaborators[0]
+		if entity.Role == collaborator.Role {
+			return
+		}
+
+		id := entity.ID
+		entity.ID = ""
+		entity.Role = collaborator.Role
+		entity.UpdatedAt = now
+		return t.collaboratorRepository.Update(ctx, id, entity)
+	}
+
+	_, err = t.collaboratorRepository.Create(ctx, entities.TaskCollaborator{
+		OwnerID:   task.UserID,
+		TaskID:    taskID,
+		UserID:    collaborator.UserID,
+		Role:      collaborator.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	return
+}
+
+// RemoveCollaborator revokes the role granted to a user on a task. Besides the owner, collaborators can remove themselves
+func (t *taskService) RemoveCollaborator(ctx context.Context, userID, taskID, collaboratorID string) (err error) {
+	role := entities.TaskRoleOwner
+	if collaboratorID == userID {
+		role = entities.TaskRoleViewer
+	}
+	_, err = t.getSharedTask(ctx, userID, taskID, role, "remove collaborators of")
+	if err != nil {
+		return
+	}
+
+	collaborators, err := getCollaborators(ctx, t.collaboratorRepository, map[string]interface{}{"task_id": taskID, "user_id": collaboratorID})
+	if err != nil {
+		return
+	}
+	if len(collaborators) == 0 {
+		return wrappers.NewNonExistentErr(fmt.Errorf("UserID %s is not a collaborator of TaskID %s", collaboratorID, taskID))
+	}
+
+	err = t.collaboratorRepository.Delete(ctx, collaborators[0].ID)
+	return
+}
+
+// GetCollaborators gets the users a task has been shared with, without the ones inherited from its parent tasks
+func (t *taskService) GetCollaborators(ctx context.Context, userID, taskID string) (resp []models.GetCollaboratorResp, err error) {
+	resp = []models.GetCollaboratorResp{}
+	_, err = t.getSharedTask(ctx, userID, taskID, entities.TaskRoleViewer, "get the collaborators of")
+	if err != nil {
+		return
+	}
+
+	collaborators, err := getCollaborators(ctx, t.collaboratorRepository, map[string]interface{}{"task_id": taskID})
+	if err != nil {
+		return
+	}
+
+	for _, collaborator := range collaborators {
+		resp = append(resp, models.GetCollaboratorResp{
+			UserID:    collaborator.UserID,
+			Role:      collaborator.Role,
+			CreatedAt: collaborator.CreatedAt,
+			UpdatedAt: collaborator.UpdatedAt,
+		})
+	}
+	return
+}
+
+// copyCollaborators grants on a task the same roles granted on another task
+func (t *taskService) copyCollaborators(ctx context.Context, fromTaskID, toTaskID string) error {
+	collaborators, err := getCollaborators(ctx, t.collaboratorRepository, map[string]interface{}{"task_id": fromTaskID})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, collaborator := range collaborators {
+		collaborator.ID = ""
+		collaborator.TaskID = toTaskID
+		collaborator.CreatedAt = now
+		collaborator.UpdatedAt = now
+		_, err = t.collaboratorRepository.Create(ctx, collaborator)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSharedTask gets a task that is not in the trash and checks that the received userID has at least the received role on it
+func (t *taskService) getSharedTask(ctx context.Context, userID, taskID, role, action string) (*entities.Task, error) {
+	return getTaskWithRole(ctx, t.repository, t.collaboratorRepository, userID, taskID, role, action)
+}
+
+// getTaskWithRole gets a task that is not in the trash and checks that the received userID has at least the received role on it
+func getTaskWithRole(ctx context.Context, taskRepository ports.TaskRepository, collaboratorRepository ports.TaskCollaboratorRepository, userID, taskID, role, action string) (*entities.Task, error) {
+	task, err := getTaskInTrashWithRole(ctx, taskRepository, collaboratorRepository, userID, taskID, role, action)
+	if err != nil {
+		return nil, err
+	}
+	if task.DeletedAt != nil {
+		return nil, wrappers.NewNonExistentErr(fmt.Errorf("TaskID %s not found", taskID))
+	}
+
+	return task, nil
+}
+
+// getTaskInTrashWithRole gets a task, even when it is in the trash, and checks that the received userID has at least the received role on it
+func getTaskInTrashWithRole(ctx context.Context, taskRepository ports.TaskRepository, collaboratorRepository ports.TaskCollaboratorRepository, userID, taskID, role, action string) (*entities.Task, error) {
+	result, err := taskRepository.GetByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("TaskID %s not found", taskID))
+		}
+		return nil, err
+	}
+
+	task := result.(*entities.Task)
+	userRole, err := taskRole(ctx, taskRepository, collaboratorRepository, task, userID)
+	if err != nil {
+		return nil, err
+	}
+	if taskRoleRanks[userRole] < taskRoleRanks[role] {
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to %s TaskID %s", userID, action, taskID))
+	}
+
+	return task, nil
+}
+
+// taskRole resolves the role of the received userID on a task, which is inherited from its parent tasks:
+// the owner of any of them is an owner of the task, otherwise the most privileged role granted on any of them applies,
+// with the assignee of any of them being at least an editor
+func taskRole(ctx context.Context, taskRepository ports.TaskRepository, collaboratorRepository ports.TaskCollaboratorRepository, task *entities.Task, userID string) (string, error) {
+	taskIDs := []string{task.ID}
+	role := ""
+	for {
+		if task.UserID == userID {
+			return entities.TaskRoleOwner, nil
+		}
+		if task.AssigneeID == userID {
+			role = entities.TaskRoleEditor
+		}
+		if task.ParentID == "" {
+			break
+		}
+
+		result, err := taskRepository.GetByID(ctx, task.ParentID)
+		if err != nil {
+			return "", err
+		}
+		task = result.(*entities.Task)
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	collaborators, err := getCollaborators(ctx, collaboratorRepository, map[string]interface{}{"task_id": map[string]interface{}{"$in": taskIDs}, "user_id": userID})
+	if err != nil {
+		return "", err
+	}
+
+	for _, collaborator := range collaborators {
+		if taskRoleRanks[collaborator.Role] > taskRoleRanks[role] {
+			role = collaborator.Role
+		}
+	}
+	return role, nil
+}
+
+// getSharedTaskIDs gets the IDs of the tasks shared with the received userID
+func (t *taskService) getSharedTaskIDs(ctx context.Context, userID string) ([]string, error) {
+	collaborators, err := getCollaborators(ctx, t.collaboratorRepository, map[string]interface{}{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(collaborators))
+	for i, collaborator := range collaborators {
+		ids[i] = collaborator.TaskID
+	}
+	return ids, nil
+}
+
+// getCollaborators gets the collaborators matching the received filter
+func getCollaborators(ctx context.Context, collaboratorRepository ports.TaskCollaboratorRepository, filter map[string]interface{}) ([]entities.TaskCollaborator, error) {
+	result, err := collaboratorRepository.Get(ctx, filter, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	collaborators := make([]entities.TaskCollaborator, len(result))
+	for i, v := range result {
+		collaborators[i] = *(v.(*entities.TaskCollaborator))
+	}
+	return collaborators, nil
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTaskCollaboratorRepositoryMock creates a task collaborator repository mock for the tests where the user is not granted any role on the tasks
+func newTaskCollaboratorRepositoryMock(t *testing.T) *mocks.TaskCollaboratorRepository {
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Maybe()
+	return collaboratorRepositoryMock
+}
+
+// newCollaboratorsResp creates the repository response with the received collaborators
+func newCollaboratorsResp(collaborators ...entities.TaskCollaborator) []interface{} {
+	var resp []interface{}
+	for i := range collaborators {
+		resp = append(resp, &collaborators[i])
+	}
+	return resp
+}
+
+// TestAddCollaborator_Ok checks that AddCollaborator grants the requested role when a valid request is received
+func TestAddCollaborator_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	req := models.AddCollaboratorReq{UserID: "user-456", Role: entities.TaskRoleEditor}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskCollaborator) bool {
+		return e.OwnerID == task.UserID && e.TaskID == task.ID && e.UserID == req.UserID && e.Role == req.Role && !e.CreatedAt.IsZero()
+	})).Return("collaborator-id", nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, "token", req.UserID).Return(true, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+		userManagementClient:   userManagementClientMock,
+	}
+
+	// Act
+	err := service.AddCollaborator(context.Background(), task.UserID, task.ID, req, "token")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAddCollaborator_ChangesRole checks that AddCollaborator updates the role of a user that is already a collaborator
+func TestAddCollaborator_ChangesRole(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	req := models.AddCollaboratorReq{UserID: "user-456", Role: entities.TaskRoleEditor}
+	collaborator := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: req.UserID, Role: entities.TaskRoleViewer}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newCollaboratorsResp(collaborator), nil).Once()
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Update), mock.Anything, collaborator.ID, mock.MatchedBy(func(e entities.TaskCollaborator) bool {
+		return e.Role == req.Role && !e.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+		userManagementClient:   userManagementClientMock,
+	}
+
+	// Act
+	err := service.AddCollaborator(context.Background(), task.UserID, task.ID, req, "token")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestAddCollaborator_InvalidRole checks that AddCollaborator returns a validation error when the requested role cannot be granted
+func TestAddCollaborator_InvalidRole(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	req := models.AddCollaboratorReq{UserID: "user-456", Role: entities.TaskRoleOwner}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	err := service.AddCollaborator(context.Background(), task.UserID, task.ID, req, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestAddCollaborator_Editor checks that AddCollaborator returns an unauthenticated error when the user is an editor but not the owner of the task
+func TestAddCollaborator_Editor(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	editor := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: "user-456", Role: entities.TaskRoleEditor}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newCollaboratorsResp(editor), nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	err := service.AddCollaborator(context.Background(), editor.UserID, task.ID, models.AddCollaboratorReq{UserID: "user-789", Role: entities.TaskRoleViewer}, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthenticatedErr))
+}
+
+// TestRemoveCollaborator_Self checks that RemoveCollaborator lets a collaborator remove themselves from a task
+func TestRemoveCollaborator_Self(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	viewer := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: "user-456", Role: entities.TaskRoleViewer}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newCollaboratorsResp(viewer), nil).Twice()
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Delete), mock.Anything, viewer.ID).Return(nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	err := service.RemoveCollaborator(context.Background(), viewer.UserID, task.ID, viewer.UserID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRemoveCollaborator_NotFound checks that RemoveCollaborator returns a non existent error when the user is not a collaborator of the task
+func TestRemoveCollaborator_NotFound(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	err := service.RemoveCollaborator(context.Background(), task.UserID, task.ID, "user-456")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestGetCollaborators_Ok checks that GetCollaborators returns the expected response when a valid request is received
+func TestGetCollaborators_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	collaborator := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: "user-456", Role: entities.TaskRoleViewer, CreatedAt: now, UpdatedAt: now}
+	expectedResponse := []models.GetCollaboratorResp{
+		{UserID: collaborator.UserID, Role: collaborator.Role, CreatedAt: now, UpdatedAt: now},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newCollaboratorsResp(collaborator), nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetCollaborators(context.Background(), task.UserID, task.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestUpdate_InheritedEditor checks that Update succeeds when the user is an editor of a parent of the task
+func TestUpdate_InheritedEditor(t *testing.T) {
+	// Arrange
+	parent := entities.Task{ID: "parent-id", UserID: "user-123"}
+	task := entities.Task{ID: "task-id", UserID: parent.UserID, ParentID: parent.ID, Title: "title"}
+	editor := entities.TaskCollaborator{ID: "collaborator-id", TaskID: parent.ID, UserID: "user-456", Role: entities.TaskRoleEditor}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Title == "new-title" && e.UserID == parent.UserID
+	}), mock.Anything).Return(nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, map[string]interface{}{
+		"task_id": map[string]interface{}{"$in": []string{task.ID, parent.ID}},
+		"user_id": editor.UserID,
+	}, mock.Anything, mock.Anything).Return(newCollaboratorsResp(editor), nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), editor.UserID, task.ID, 0, models.UpdateTaskReq{Title: "new-title"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdate_Viewer checks that Update returns an unauthenticated error when the user is only a viewer of the task
+func TestUpdate_Viewer(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	viewer := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: "user-456", Role: entities.TaskRoleViewer}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newCollaboratorsResp(viewer), nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), viewer.UserID, task.ID, 0, models.UpdateTaskReq{Title: "new-title"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthenticatedErr))
+}
+
+// TestGetByUserID_SharedScope checks that GetByUserID finds the tasks shared with the user when the shared scope is requested
+func TestGetByUserID_SharedScope(t *testing.T) {
+	// Arrange
+	userID := "user-456"
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	collaborator := entities.TaskCollaborator{ID: "collaborator-id", TaskID: task.ID, UserID: userID, Role: entities.TaskRoleViewer}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.UserID == "" && len(f.IDs) == 1 && f.IDs[0] == task.ID
+	})).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.Get), mock.Anything, map[string]interface{}{"user_id": userID}, mock.Anything, mock.Anything).Return(newCollaboratorsResp(collaborator), nil).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:                 cfg,
+		repository:             taskRepositoryMock,
+		collaboratorRepository: collaboratorRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), userID, models.GetTasksReq{Scope: taskScopeShared})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Tasks, 1)
+	assert.Equal(t, task.ID, resp.Tasks[0].ID)
+}
+
+// TestGetByUserID_NothingShared checks that GetByUserID returns no tasks when the shared scope is requested and nothing is shared with the user
+func TestGetByUserID_NothingShared(t *testing.T) {
+	// Arrange
+	service := &taskService{
+		config:                 config.Config{},
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), "user-456", models.GetTasksReq{Scope: taskScopeShared})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Tasks)
+}
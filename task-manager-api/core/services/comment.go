@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// commentService adapter of a comment service
+type commentService struct {
+	config                 config.Config
+	repository             ports.CommentRepository
+	taskRepository         ports.TaskRepository
+	collaboratorRepository ports.TaskCollaboratorRepository
+	userManagementClient   commonPorts.UserManagementV1GRPCClient
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(cfg config.Config, repo ports.CommentRepository, taskRepo ports.TaskRepository, collaboratorRepo ports.TaskCollaboratorRepository, userManagementClient commonPorts.UserManagementV1GRPCClient) ports.CommentService {
+	return &commentService{
+		config:                 cfg,
+		repository:             repo,
+		taskRepository:         taskRepo,
+		collaboratorRepository: collaboratorRepo,
+		userManagementClient:   userManagementClient,
+	}
+}
+
+// Create posts a comment on a task, authored by the received userID, who must be its owner or an editor
+func (c *commentService) Create(ctx context.Context, userID, taskID string, comment models.CreateCommentReq, token string) (resp models.CreateCommentResp, err error) {
+	_, err = getTaskWithRole(ctx, c.taskRepository, c.collaboratorRepository, userID, taskID, entities.TaskRoleEditor, "comment on")
+	if err != nil {
+		return
+	}
+
+	exists, err := c.userManagementClient.Exists(ctx, token, userID)
+	if err != nil {
+		return
+	}
+	if !exists {
+		return resp, wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", userID))
+	}
+
+	comment.Body, err = c.validateCommentBody(comment.Body)
+	if err != nil {
+		return
+	}
+
+	entity := entities.Comment(comment)
+	entity.TaskID = taskID
+	entity.AuthorID = userID
+	entity.CreatedAt = time.Now().UTC()
+	entity.UpdatedAt = entity.CreatedAt
+
+	id, err := c.repository.Create(ctx, entity)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreateCommentResp{
+		ID: id,
+	}
+
+	return
+}
+
+// GetByTaskID gets a page of the comments of a task, the oldest first
+func (c *commentService) GetByTaskID(ctx context.Context, userID, taskID string, req models.GetCommentsReq) (resp models.GetCommentsResp, err error) {
+	resp.Comments = []models.GetCommentResp{}
+	_, err = getTaskWithRole(ctx, c.taskRepository, c.collaboratorRepository, userID, taskID, entities.TaskRoleViewer, "get the comments of")
+	if err != nil {
+		return
+	}
+
+	limit, err := pageLimit(c.config.Pagination, req.Limit)
+	if err != nil {
+		return
+	}
+
+	filter := models.CommentFilter{
+		TaskID: taskID,
+		Limit:  limit + 1,
+	}
+	if req.Cursor != "" {
+		filter.After, err = decodeCommentCursor(req.Cursor)
+		if err != nil {
+			return
+		}
+	}
+
+	result, err := c.repository.Find(ctx, filter)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	if len(result) > limit {
+		result = result[:limit]
+		resp.NextCursor, err = encodeCommentCursor(result[limit-1].(*entities.Comment))
+		if err != nil {
+			return
+		}
+	}
+
+	resp.Comments = make([]models.GetCommentResp, len(result))
+	for i, v := range result {
+		resp.Comments[i] = models.GetCommentResp(*(v.(*entities.Comment)))
+	}
+
+	return
+}
+
+// Update edits the body of a comment, as long as the received userID is its author
+func (c *commentService) Update(ctx context.Context, userID, taskID, commentID string, comment models.UpdateCommentReq) (err error) {
+	entity, err := c.getOwnComment(ctx, userID, taskID, commentID, "edit")
+	if err != nil {
+		return
+	}
+
+	entity.Body, err = c.validateCommentBody(comment.Body)
+	if err != nil {
+		return
+	}
+
+	entity.ID = ""
+	entity.UpdatedAt = time.Now().UTC()
+	err = c.repository.Update(ctx, commentID, *entity)
+	return
+}
+
+// Delete removes a comment, as long as the received userID is its author
+func (c *commentService) Delete(ctx context.Context, userID, taskID, commentID string) (err error) {
+	_, err = c.getOwnComment(ctx, userID, taskID, commentID, "delete")
+	if err != nil {
+		return
+	}
+
+	err = c.repository.Delete(ctx, commentID)
+	return
+}
+
+// getOwnComment gets a comment of a task and checks that the received userID is its author
+func (c *commentService) getOwnComment(ctx context.Context, userID, taskID, commentID, action string) (*entities.Comment, error) {
+	_, err := getTaskWithRole(ctx, c.taskRepository, c.collaboratorRepository, userID, taskID, entities.TaskRoleEditor, action+" comments of")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.repository.GetByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("CommentID %s not found", commentID))
+		}
+		return nil, err
+	}
+
+	comment := result.(*entities.Comment)
+	if comment.TaskID != taskID {
+		return nil, wrappers.NewNonExistentErr(fmt.Errorf("CommentID %s not found in TaskID %s", commentID, taskID))
+	}
+	if comment.AuthorID != userID {
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to %s CommentID %s", userID, action, commentID))
+	}
+
+	return comment, nil
+}
+
+// validateCommentBody trims the received comment body and checks that it is not empty nor longer than the configured maximum
+func (c *commentService) validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", wrappers.NewValidationErr(errors.New("comment body cannot be empty"))
+	}
+	if length := utf8.RuneCountInString(body); length > c.config.Comments.MaxLength {
+		return "", wrappers.NewValidationErr(fmt.Errorf("comment body has %d characters, the maximum is %d", length, c.config.Comments.MaxLength))
+	}
+	return body, nil
+}
+
+// encodeCommentCursor builds the opaque cursor pointing after the received comment
+func encodeCommentCursor(comment *entities.Comment) (string, error) {
+	cursor, err := json.Marshal(models.CommentCursor{
+		ID:        comment.ID,
+		CreatedAt: comment.CreatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+// decodeCommentCursor parses an opaque comment cursor
+func decodeCommentCursor(encoded string) (*models.CommentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, wrappers.NewValidationErr(errors.New("cursor not valid"))
+	}
+
+	var cursor models.CommentCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, wrappers.NewValidationErr(errors.New("cursor not valid"))
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateComment_Ok checks that Create returns the expected response when a valid request is received
+func TestCreateComment_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	expectedResponse := models.CreateCommentResp{ID: "comment-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, "token", task.UserID).Return(true, nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.Create), mock.Anything, mock.MatchedBy(func(comment entities.Comment) bool {
+		return comment.Body == "looks good" && comment.TaskID == task.ID && comment.AuthorID == task.UserID && !comment.CreatedAt.IsZero()
+	})).Return(expectedResponse.ID, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Comments = config.Comments{MaxLength: 100}
+	service := &commentService{
+		config:               cfg,
+		repository:           commentRepositoryMock,
+		taskRepository:       taskRepositoryMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	resp, err := service.Create(context.Background(), task.UserID, task.ID, models.CreateCommentReq{Body: " looks good "}, "token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestCreateComment_UserNotFound checks that Create returns a non existent error when the user does not exist
+func TestCreateComment_UserNotFound(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	service := &commentService{
+		config:               config.Config{},
+		taskRepository:       taskRepositoryMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), task.UserID, task.ID, models.CreateCommentReq{Body: "looks good"}, "token")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestCreateComment_InvalidBody checks that Create returns a validation error when the comment body is not valid
+func TestCreateComment_InvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty body", "   "},
+		{"too long body", "more than ten characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+			taskRepositoryMock := mocks.NewTaskRepository(t)
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+			userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+			userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+			cfg := config.Config{}
+			cfg.Comments = config.Comments{MaxLength: 10}
+			service := &commentService{
+				config:               cfg,
+				taskRepository:       taskRepositoryMock,
+				userManagementClient: userManagementClientMock,
+			}
+
+			// Act
+			_, err := service.Create(context.Background(), task.UserID, task.ID, models.CreateCommentReq{Body: tt.body}, "token")
+
+			// Assert
+			assert.IsType(t, wrappers.ValidationErr, err)
+		})
+	}
+}
+
+// TestCreateComment_TaskInTrash checks that Create returns a non existent error when the task is in the trash
+func TestCreateComment_TaskInTrash(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", DeletedAt: &deletedAt}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &commentService{
+		config:         config.Config{},
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), task.UserID, task.ID, models.CreateCommentReq{Body: "looks good"}, "token")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestGetCommentsByTaskID_NextCursor checks that GetByTaskID returns a page of comments and a cursor that resumes after its last comment
+func TestGetCommentsByTaskID_NextCursor(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	comments := []interface{}{
+		&entities.Comment{ID: "comment-1", TaskID: task.ID, Body: "first", CreatedAt: createdAt},
+		&entities.Comment{ID: "comment-2", TaskID: task.ID, Body: "second", CreatedAt: createdAt},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.Find), mock.Anything, models.CommentFilter{TaskID: task.ID, Limit: 2}).Return(comments, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 20, MaxLimit: 100}
+	service := &commentService{
+		config:         cfg,
+		repository:     commentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByTaskID(context.Background(), task.UserID, task.ID, models.GetCommentsReq{Limit: 1})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.GetCommentResp{{ID: "comment-1", TaskID: task.ID, Body: "first", CreatedAt: createdAt}}, resp.Comments)
+	cursor, err := decodeCommentCursor(resp.NextCursor)
+	assert.Nil(t, err)
+	assert.Equal(t, &models.CommentCursor{ID: "comment-1", CreatedAt: createdAt}, cursor)
+}
+
+// TestUpdateComment_NotAuthor checks that Update returns an unauthenticated error when the user is not the author of the comment
+func TestUpdateComment_NotAuthor(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	comment := entities.Comment{ID: "comment-id", TaskID: task.ID, AuthorID: "another-user"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.GetByID), mock.Anything, comment.ID).Return(&comment, nil).Once()
+
+	service := &commentService{
+		config:         config.Config{},
+		repository:     commentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), task.UserID, task.ID, comment.ID, models.UpdateCommentReq{Body: "edited"})
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+}
+
+// TestDeleteComment_Ok checks that Delete removes the comment when the user is its author
+func TestDeleteComment_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	comment := entities.Comment{ID: "comment-id", TaskID: task.ID, AuthorID: task.UserID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.GetByID), mock.Anything, comment.ID).Return(&comment, nil).Once()
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.Delete), mock.Anything, comment.ID).Return(nil).Once()
+
+	service := &commentService{
+		config:         config.Config{},
+		repository:     commentRepositoryMock,
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), task.UserID, task.ID, comment.ID)
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// AddBlocker declares that a task is blocked by another task of the same owner, as long as it does not create a dependency cycle.
+// The version of the task is incremented, so that concurrent changes to its dependencies based on the same version are rejected
+func (t *taskService) AddBlocker(ctx context.Context, userID, taskID string, version int, blocker models.AddBlockerReq) (newVersion int, err error) {
+	task, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
+		return
+	}
+
+	if blocker.BlockerID == taskID {
+		return newVersion, wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be blocked by itself", taskID))
+	}
+	blockerTask, err := t.getSharedTask(ctx, userID, blocker.BlockerID, entities.TaskRoleViewer, "depend on")
+	if err != nil {
+		return
+	}
+	if blockerTask.UserID != task.UserID {
+		return newVersion, wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be blocked by TaskID %s, they belong to different users", taskID, blocker.BlockerID))
+	}
+
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		// the dependencies are checked in the transaction and the blocker is saved too, so that declaring the reverse dependency
+		// at the same time makes one of the transactions fail and find the cycle when run again
+		err := t.checkNewBlocker(ctx, task.UserID, taskID, blocker.BlockerID)
+		if err != nil {
+			return err
+		}
+
+		_, err = t.dependencyRepository.Create(ctx, entities.TaskDependency{
+			UserID:    task.UserID,
+			TaskID:    taskID,
+			BlockerID: blocker.BlockerID,
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err := t.repository.GetByID(ctx, blocker.BlockerID)
+		if err != nil {
+			return err
+		}
+		_, err = t.writer.touch(ctx, userID, *(result.(*entities.Task)))
+		if err != nil {
+			return err
+		}
+
+		newVersion, err = t.writer.touch(ctx, userID, *task)
+		return err
+	})
+	return
+}
+
+// checkNewBlocker checks that a task of the received owner is not blocked by the received blocker yet,
+// and that blocking it would not create a dependency cycle
+func (t *taskService) checkNewBlocker(ctx context.Context, ownerID, taskID, blockerID string) error {
+	dependencies, err := t.getDependencies(ctx, map[string]interface{}{"user_id": ownerID})
+	if err != nil {
+		return err
+	}
+
+	blockers := map[string][]string{}
+	for _, dependency := range dependencies {
+		if dependency.TaskID == taskID && dependency.BlockerID == blockerID {
+			return wrappers.NewValidationErr(fmt.Errorf("TaskID %s is already blocked by TaskID %s", taskID, blockerID))
+		}
+		blockers[dependency.TaskID] = append(blockers[dependency.TaskID], dependency.BlockerID)
+	}
+
+	if path := blockingPath(blockers, blockerID, taskID); path != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be blocked by TaskID %s, it would create the dependency cycle %s -> %s", taskID, blockerID, taskID, strings.Join(path, " -> ")))
+	}
+	return nil
+}
+
+// RemoveBlocker removes the dependency between a task and one of its blockers, incrementing the version of the task
+func (t *taskService) RemoveBlocker(ctx context.Context, userID, taskID string, version int, blockerID string) (newVersion int, err error) {
+	task, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
+		return
+	}
+
+	dependencies, err := t.getDependencies(ctx, map[string]interface{}{"task_id": taskID, "blocker_id": blockerID})
+	if err != nil {
+		return
+	}
+	if len(dependencies) == 0 {
+		return newVersion, wrappers.NewNonExistentErr(fmt.Errorf("TaskID %s is not blocked by TaskID %s", taskID, blockerID))
+	}
+
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.dependencyRepository.Delete(ctx, dependencies[0].ID)
+		if err != nil {
+			return err
+		}
+
+		newVersion, err = t.writer.touch(ctx, userID, *task)
+		return err
+	})
+	return
+}
+
+// GetGraph gets the dependency graph containing a task, with all the tasks that block it or are blocked by it, directly or transitively
+func (t *taskService) GetGraph(ctx context.Context, userID, taskID string) (resp models.GetTaskGraphResp, err error) {
+	task, err := t.getSharedTask(ctx, userID, taskID, entities.TaskRoleViewer, "get the graph of")
+	if err != nil {
+		return
+	}
+
+	dependencies, err := t.getDependencies(ctx, map[string]interface{}{"user_id": task.UserID})
+	if err != nil {
+		return
+	}
+
+	blockers := map[string][]string{}
+	blocked := map[string][]string{}
+	for _, dependency := range dependencies {
+		blockers[dependency.TaskID] = append(blockers[dependency.TaskID], dependency.BlockerID)
+		blocked[dependency.BlockerID] = append(blocked[dependency.BlockerID], dependency.TaskID)
+	}
+
+	nodeIDs := []string{taskID}
+	for i := 0; i < len(nodeIDs); i++ {
+		for _, id := range append(blockers[nodeIDs[i]], blocked[nodeIDs[i]]...) {
+			if !slices.Contains(nodeIDs, id) {
+				nodeIDs = append(nodeIDs, id)
+			}
+		}
+	}
+
+	tasks, err := t.findTasks(ctx, nodeIDs)
+	if err != nil {
+		return
+	}
+
+	resp.Nodes = make([]models.TaskGraphNode, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		if task, ok := tasks[id]; ok {
+			resp.Nodes = append(resp.Nodes, models.TaskGraphNode{ID: task.ID, Title: task.Title, Status: task.Status})
+		}
+	}
+
+	resp.Edges = []models.TaskGraphEdge{}
+	for _, dependency := range dependencies {
+		_, taskFound := tasks[dependency.TaskID]
+		_, blockerFound := tasks[dependency.BlockerID]
+		if taskFound && blockerFound {
+			resp.Edges = append(resp.Edges, models.TaskGraphEdge{From: dependency.BlockerID, To: dependency.TaskID})
+		}
+	}
+
+	return
+}
+
+// checkBlockers checks that none of the tasks blocking the received task is still open
+func (t *taskService) checkBlockers(ctx context.Context, taskID string) error {
+	dependencies, err := t.getDependencies(ctx, map[string]interface{}{"task_id": taskID})
+	if err != nil || len(dependencies) == 0 {
+		return err
+	}
+
+	blockerIDs := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		blockerIDs[i] = dependency.BlockerID
+	}
+
+	blockers, err := t.findTasks(ctx, blockerIDs)
+	if err != nil {
+		return err
+	}
+
+	var openBlockerIDs []string
+	for _, id := range blockerIDs {
+		if blocker, ok := blockers[id]; ok && slices.Contains(openTaskStatuses, blocker.Status) {
+			openBlockerIDs = append(openBlockerIDs, id)
+		}
+	}
+	if len(openBlockerIDs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be done while it is blocked by the open TaskIDs %s", taskID, strings.Join(openBlockerIDs, ", ")))
+	}
+	return nil
+}
+
+// getDependencies gets the task dependencies matching the received filter
+func (t *taskService) getDependencies(ctx context.Context, filter map[string]interface{}) ([]entities.TaskDependency, error) {
+	result, err := t.dependencyRepository.Get(ctx, filter, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	dependencies := make([]entities.TaskDependency, len(result))
+	for i, v := range result {
+		dependencies[i] = *(v.(*entities.TaskDependency))
+	}
+	return dependencies, nil
+}
+
+// findTasks gets the tasks with the received IDs, indexed by ID
+func (t *taskService) findTasks(ctx context.Context, ids []string) (map[string]entities.Task, error) {
+	result, err := t.repository.FindByIDs(ctx, ids)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	tasks := make(map[string]entities.Task, len(result))
+	for _, v := range result {
+		task := v.(*entities.Task)
+		tasks[task.ID] = *task
+	}
+	return tasks, nil
+}
+
+// blockingPath finds a path of blockers going from the task with ID from to the task with ID to, or nil when there is none
+func blockingPath(blockers map[string][]string, from, to string) []string {
+	visited := map[string]bool{}
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		for _, blockerID := range blockers[id] {
+			if path := visit(blockerID); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+
+	return visit(from)
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestAddBlocker_Ok checks that AddBlocker creates the dependency when it does not create a cycle
+func TestAddBlocker_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+	blocker := entities.Task{ID: "task-b", UserID: "user-123"}
+	dependencies := []interface{}{
+		&entities.TaskDependency{UserID: "user-123", TaskID: "task-b", BlockerID: "task-c"},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, blocker.ID).Return(&blocker, nil).Twice()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, blocker.ID, mock.Anything, blocker.Version).Return(nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123"}, mock.Anything, mock.Anything).Return(dependencies, nil).Once()
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Create), mock.Anything, mock.MatchedBy(func(d entities.TaskDependency) bool {
+		return d.TaskID == task.ID && d.BlockerID == blocker.ID && d.UserID == "user-123"
+	})).Return("dependency-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.Anything, task.Version).Return(nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.AddBlocker(context.Background(), "user-123", task.ID, task.Version, models.AddBlockerReq{BlockerID: blocker.ID})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, task.Version+1, newVersion)
+}
+
+// TestAddBlocker_StaleVersion checks that AddBlocker returns a PreconditionFailedErr when the task is no longer at the received version
+func TestAddBlocker_StaleVersion(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.AddBlocker(context.Background(), "user-123", task.ID, 2, models.AddBlockerReq{BlockerID: "task-b"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, taskWrappers.PreconditionFailedErr, err)
+}
+
+// TestAddBlocker_Cycle checks that AddBlocker returns a validation error when the dependency creates a cycle
+func TestAddBlocker_Cycle(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+	blocker := entities.Task{ID: "task-b", UserID: "user-123"}
+	dependencies := []interface{}{
+		&entities.TaskDependency{UserID: "user-123", TaskID: "task-b", BlockerID: "task-c"},
+		&entities.TaskDependency{UserID: "user-123", TaskID: "task-c", BlockerID: "task-a"},
+	}
+	expectedError := "TaskID task-a cannot be blocked by TaskID task-b, it would create the dependency cycle task-a -> task-b -> task-c -> task-a"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, blocker.ID).Return(&blocker, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dependencies, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+		transactions:         newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.AddBlocker(context.Background(), "user-123", task.ID, task.Version, models.AddBlockerReq{BlockerID: blocker.ID})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAddBlocker_ConcurrentReverse checks that AddBlocker finds the cycle when its transaction is run again after conflicting
+// with the reverse dependency being declared at the same time
+func TestAddBlocker_ConcurrentReverse(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+	blocker := entities.Task{ID: "task-b", UserID: "user-123"}
+	conflictError := "write-conflict"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, blocker.ID).Return(&blocker, nil).Twice()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, blocker.ID, mock.Anything, blocker.Version).Return(errors.New(conflictError)).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Create), mock.Anything, mock.AnythingOfType("entities.TaskDependency")).Return("dependency-id", nil).Once()
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{
+		&entities.TaskDependency{UserID: "user-123", TaskID: blocker.ID, BlockerID: task.ID},
+	}, nil).Once()
+
+	transactionManagerMock := mocks.NewTransactionManager(t)
+	transactionManagerMock.On(testutils.FunctionName(t, ports.TransactionManager.WithTransaction), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		err := fn(ctx)
+		if err != nil && err.Error() == conflictError {
+			return fn(ctx)
+		}
+		return err
+	}).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         transactionManagerMock,
+	}
+
+	// Act
+	_, err := service.AddBlocker(context.Background(), "user-123", task.ID, task.Version, models.AddBlockerReq{BlockerID: blocker.ID})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestAddBlocker_Itself checks that AddBlocker returns a validation error when a task is declared as its own blocker
+func TestAddBlocker_Itself(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.AddBlocker(context.Background(), "user-123", task.ID, task.Version, models.AddBlockerReq{BlockerID: task.ID})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestAddBlocker_BlockerOfAnotherUser checks that AddBlocker returns an error when the blocker belongs to another user
+func TestAddBlocker_BlockerOfAnotherUser(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+	blocker := entities.Task{ID: "task-b", UserID: "other-user"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, blocker.ID).Return(&blocker, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.AddBlocker(context.Background(), "user-123", task.ID, task.Version, models.AddBlockerReq{BlockerID: blocker.ID})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+}
+
+// TestRemoveBlocker_Ok checks that RemoveBlocker deletes the dependency between the task and the blocker
+func TestRemoveBlocker_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, map[string]interface{}{"task_id": task.ID, "blocker_id": "task-b"}, mock.Anything, mock.Anything).Return([]interface{}{&entities.TaskDependency{ID: "dependency-id"}}, nil).Once()
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Delete), mock.Anything, "dependency-id").Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.Anything, task.Version).Return(nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.RemoveBlocker(context.Background(), "user-123", task.ID, task.Version, "task-b")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, task.Version+1, newVersion)
+}
+
+// TestRemoveBlocker_NotFound checks that RemoveBlocker returns a NonExistentErr when the task is not blocked by the received task
+func TestRemoveBlocker_NotFound(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+	}
+
+	// Act
+	_, err := service.RemoveBlocker(context.Background(), "user-123", task.ID, task.Version, "task-b")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestGetGraph_Ok checks that GetGraph returns the tasks connected to the task and the dependencies between them
+func TestGetGraph_Ok(t *testing.T) {
+	// Arrange
+	tasks := []entities.Task{
+		{ID: "task-a", UserID: "user-123", Title: "a", Status: entities.TaskStatusTodo},
+		{ID: "task-b", UserID: "user-123", Title: "b", Status: entities.TaskStatusDone},
+		{ID: "task-c", UserID: "user-123", Title: "c", Status: entities.TaskStatusTodo},
+	}
+	dependencies := []interface{}{
+		&entities.TaskDependency{TaskID: "task-a", BlockerID: "task-b"},
+		&entities.TaskDependency{TaskID: "task-c", BlockerID: "task-a"},
+		&entities.TaskDependency{TaskID: "task-y", BlockerID: "task-z"},
+	}
+	expectedResponse := models.GetTaskGraphResp{
+		Nodes: []models.TaskGraphNode{
+			{ID: "task-a", Title: "a", Status: entities.TaskStatusTodo},
+			{ID: "task-b", Title: "b", Status: entities.TaskStatusDone},
+			{ID: "task-c", Title: "c", Status: entities.TaskStatusTodo},
+		},
+		Edges: []models.TaskGraphEdge{
+			{From: "task-b", To: "task-a"},
+			{From: "task-a", To: "task-c"},
+		},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, "task-a").Return(&tasks[0], nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindByIDs), mock.Anything, []string{"task-a", "task-b", "task-c"}).Return([]interface{}{&tasks[2], &tasks[1], &tasks[0]}, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dependencies, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetGraph(context.Background(), "user-123", "task-a")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestTransition_OpenBlockers checks that Transition refuses to move a task to done while any of its blockers is open
+func TestTransition_OpenBlockers(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-a", UserID: "user-123", Status: entities.TaskStatusInProgress}
+	blockers := []entities.Task{
+		{ID: "task-b", UserID: "user-123", Status: entities.TaskStatusDone},
+		{ID: "task-c", UserID: "user-123", Status: entities.TaskStatusBlocked},
+	}
+	expectedError := "TaskID task-a cannot be done while it is blocked by the open TaskIDs task-c"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindByIDs), mock.Anything, []string{"task-b", "task-c"}).Return([]interface{}{&blockers[0], &blockers[1]}, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, map[string]interface{}{"task_id": task.ID}, mock.Anything, mock.Anything).Return([]interface{}{
+		&entities.TaskDependency{TaskID: task.ID, BlockerID: "task-b"},
+		&entities.TaskDependency{TaskID: task.ID, BlockerID: "task-c"},
+	}, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusDone})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+)
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+	fragmentEllipsis = "…"
+	fragmentContext  = 40
+	maxFragments     = 3
+)
+
+type span struct {
+	start, end int
+}
+
+// searchTerms splits a text query into lowercase terms, ignoring negated terms and quotes
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		term := strings.Trim(field, `"`)
+		if term == "" {
+			continue
+		}
+		terms = append(terms, string(toLowerRunes(term)))
+	}
+	return terms
+}
+
+// highlightTask returns the fragments of the task fields that contain any of the received terms.
+// The title is returned as a whole while the description is split into fragments around the matches.
+func highlightTask(task entities.Task, terms []string) map[string][]string {
+	highlights := map[string][]string{}
+	if fragments := highlightFragments(task.Title, terms, -1); len(fragments) > 0 {
+		highlights["title"] = fragments
+	}
+	if fragments := highlightFragments(task.Description, terms, fragmentContext); len(fragments) > 0 {
+		highlights["description"] = fragments
+	}
+	return highlights
+}
+
+// highlightFragments wraps the occurrences of the terms in the text with highlight tags, escaping the rest of the text.
+// A negative context returns the whole text as a single fragment.
+func highlightFragments(text string, terms []string, context int) []string {
+	runes := []rune(text)
+	spans := matchSpans(toLowerRunes(text), terms)
+	if len(spans) == 0 {
+		return nil
+	}
+
+	if context < 0 {
+		return []string{highlightWindow(runes, spans, span{0, len(runes)})}
+	}
+
+	var windows []span
+	for _, s := range spans {
+		w := span{max(s.start-context, 0), min(s.end+context, len(runes))}
+		if len(windows) > 0 && w.start <= windows[len(windows)-1].end {
+			windows[len(windows)-1].end = w.end
+			continue
+		}
+		windows = append(windows, w)
+	}
+
+	var fragments []string
+	for _, w := range windows[:min(len(windows), maxFragments)] {
+		fragment := highlightWindow(runes, spans, w)
+		if w.start > 0 {
+			fragment = fragmentEllipsis + fragment
+		}
+		if w.end < len(runes) {
+			fragment += fragmentEllipsis
+		}
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+// matchSpans finds the sorted and non overlapping spans of the text where any of the terms occur
+func matchSpans(text []rune, terms []string) []span {
+	var spans []span
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(text); i++ {
+			if string(text[i:i+len(t)]) == term {
+				spans = append(spans, span{i, i + len(t)})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var merged []span
+	for _, s := range spans {
+		if len(merged) > 0 && s.start <= merged[len(merged)-1].end {
+			merged[len(merged)-1].end = max(merged[len(merged)-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// highlightWindow builds the escaped text of the window, with the spans inside it wrapped in highlight tags
+func highlightWindow(text []rune, spans []span, window span) string {
+	var b strings.Builder
+	pos := window.start
+	for _, s := range spans {
+		if s.start < window.start || s.end > window.end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(text[pos:s.start])))
+		b.WriteString(highlightPreTag)
+		b.WriteString(html.EscapeString(string(text[s.start:s.end])))
+		b.WriteString(highlightPostTag)
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(text[pos:window.end])))
+	return b.String()
+}
+
+// toLowerRunes lowercases the text rune by rune, so the result keeps the same rune offsets
+func toLowerRunes(text string) []rune {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSearchTerms checks that searchTerms splits the query as expected
+func TestSearchTerms(t *testing.T) {
+	// Act
+	terms := searchTerms(`Buy "Milk" -eggs  bread`)
+
+	// Assert
+	assert.Equal(t, []string{"buy", "milk", "bread"}, terms)
+}
+
+// TestHighlightTask_Ok checks that highlightTask highlights the whole title and the description fragments around the matches
+func TestHighlightTask_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		Title:       "Buy milk",
+		Description: "Remember to go to the supermarket before it closes because we ran out of MILK <again>",
+	}
+	expectedHighlights := map[string][]string{
+		"title":       {"Buy <em>milk</em>"},
+		"description": {"… before it closes because we ran out of <em>MILK</em> &lt;again&gt;"},
+	}
+
+	// Act
+	highlights := highlightTask(task, []string{"milk"})
+
+	// Assert
+	assert.Equal(t, expectedHighlights, highlights)
+}
+
+// TestHighlightTask_NoMatches checks that highlightTask returns no highlights when no term occurs in the task
+func TestHighlightTask_NoMatches(t *testing.T) {
+	// Act
+	highlights := highlightTask(entities.Task{Title: "Buy milk"}, []string{"bread"})
+
+	// Assert
+	assert.Empty(t, highlights)
+}
+
+// TestHighlightFragments_MaxFragments checks that highlightFragments merges close matches and limits the number of fragments
+func TestHighlightFragments_MaxFragments(t *testing.T) {
+	// Arrange
+	text := "a b a b a b a b a"
+
+	// Act
+	merged := highlightFragments(text, []string{"a", "a b"}, 1)
+	limited := highlightFragments("x.......................x.......................x.......................x", []string{"x"}, 2)
+
+	// Assert
+	assert.Equal(t, []string{"<em>a b</em> <em>a b</em> <em>a b</em> <em>a b</em> <em>a</em>"}, merged)
+	assert.Equal(t, maxFragments, len(limited))
+	assert.Equal(t, "<em>x</em>..…", limited[0])
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// untrackedTaskFields are the task fields left out of the history, as they are either immutable or maintained internally
+var untrackedTaskFields = []string{"_id", "user_id", "creator_id", "created_at", "updated_at", "version"}
+
+// taskHistoryEvents are the event types of the actions recorded in the task history. A task restored from the trash is an update of its deleted_at
+var taskHistoryEvents = map[string]string{
+	entities.TaskHistoryActionCreated:  entities.WebhookEventTaskCreated,
+	entities.TaskHistoryActionUpdated:  entities.WebhookEventTaskUpdated,
+	entities.TaskHistoryActionRestored: entities.WebhookEventTaskUpdated,
+	entities.TaskHistoryActionDeleted:  entities.WebhookEventTaskDeleted,
+}
+
+// GetHistory gets the changes made to a task, the oldest first. The history of the tasks in the trash is available as well
+func (t *taskService) GetHistory(ctx context.Context, userID, taskID string) (resp []models.GetTaskHistoryResp, err error) {
+	resp = []models.GetTaskHistoryResp{}
+	_, err = getTaskInTrashWithRole(ctx, t.repository, t.collaboratorRepository, userID, taskID, entities.TaskRoleViewer, "get the history of")
+	if err != nil {
+		return
+	}
+
+	result, err := t.historyRepository.FindByTaskID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	for _, v := range result {
+		entry := v.(*entities.TaskHistory)
+		changes := make([]models.FieldChange, len(entry.Changes))
+		for i, change := range entry.Changes {
+			changes[i] = models.FieldChange(change)
+		}
+
+		resp = append(resp, models.GetTaskHistoryResp{
+			ID:        entry.ID,
+			TaskID:    entry.TaskID,
+			ActorID:   entry.ActorID,
+			Action:    entry.Action,
+			Changes:   changes,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return
+}
+
+// diffTasks gets the tracked fields whose value differs between the received versions of a task, named as they are stored
+func diffTasks(before, after entities.Task) []entities.FieldChange {
+	var changes []entities.FieldChange
+
+	beforeValue, afterValue := reflect.ValueOf(before), reflect.ValueOf(after)
+	taskType := beforeValue.Type()
+	for i := 0; i < taskType.NumField(); i++ {
+		field, _, _ := strings.Cut(taskType.Field(i).Tag.Get("bson"), ",")
+		if slices.Contains(untrackedTaskFields, field) {
+			continue
+		}
+
+		from, to := historyValue(beforeValue.Field(i)), historyValue(afterValue.Field(i))
+		if !historyValuesEqual(from, to) {
+			changes = append(changes, entities.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// historyValue gets the value of a task field to be stored in the history, with nil pointers and empty slices as nil
+func historyValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return value.Elem().Interface()
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil
+		}
+	}
+	return value.Interface()
+}
+
+// historyValuesEqual compares two task field values, comparing times by instant
+func historyValuesEqual(a, b interface{}) bool {
+	aTime, aIsTime := a.(time.Time)
+	bTime, bIsTime := b.(time.Time)
+	if aIsTime && bIsTime {
+		return aTime.Equal(bTime)
+	}
+	return reflect.DeepEqual(a, b)
+}
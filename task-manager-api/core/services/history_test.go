@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTaskHistoryRepositoryMock creates a task history repository mock accepting any history entry
+func newTaskHistoryRepositoryMock(t *testing.T) *mocks.TaskHistoryRepository {
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.AnythingOfType("entities.TaskHistory")).Return("history-id", nil).Maybe()
+	return historyRepositoryMock
+}
+
+// TestDiffTasks_Ok checks that diffTasks returns the changed tracked fields only
+func TestDiffTasks_Ok(t *testing.T) {
+	// Arrange
+	dueAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	sameDueAt := dueAt.In(time.FixedZone("CEST", 2*60*60))
+	before := entities.Task{
+		ID:        "task-id",
+		Title:     "title",
+		Status:    entities.TaskStatusTodo,
+		Labels:    []string{},
+		DueAt:     &dueAt,
+		UpdatedAt: time.Now(),
+	}
+	after := before
+	after.Title = "new-title"
+	after.Labels = nil
+	after.DueAt = &sameDueAt
+	after.Priority = entities.TaskPriorityHigh
+	after.UpdatedAt = time.Now().Add(time.Minute)
+
+	expectedChanges := []entities.FieldChange{
+		{Field: "title", From: "title", To: "new-title"},
+		{Field: "priority", From: "", To: entities.TaskPriorityHigh},
+	}
+
+	// Act
+	changes := diffTasks(before, after)
+
+	// Assert
+	assert.Equal(t, expectedChanges, changes)
+}
+
+// TestDiffTasks_Created checks that diffTasks returns all the set fields of a new task
+func TestDiffTasks_Created(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		UserID:    "user-123",
+		Title:     "title",
+		Status:    entities.TaskStatusTodo,
+		Labels:    []string{"work"},
+		CreatedAt: time.Now(),
+	}
+	expectedChanges := []entities.FieldChange{
+		{Field: "title", From: "", To: "title"},
+		{Field: "status", From: "", To: entities.TaskStatusTodo},
+		{Field: "labels", From: nil, To: []string{"work"}},
+	}
+
+	// Act
+	changes := diffTasks(entities.Task{}, task)
+
+	// Assert
+	assert.Equal(t, expectedChanges, changes)
+}
+
+// TestUpdate_RecordsHistory checks that Update records the changed fields with the actor, writing them to the outbox as an event for the owner
+func TestUpdate_RecordsHistory(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:       "task-id",
+		UserID:   "user-123",
+		Title:    "title",
+		Priority: entities.TaskPriorityMedium,
+		Labels:   []string{},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.AnythingOfType("entities.Task"), mock.Anything).Return(nil).Once()
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(h entities.TaskHistory) bool {
+		return h.TaskID == task.ID && h.ActorID == task.UserID && h.Action == entities.TaskHistoryActionUpdated &&
+			assert.ObjectsAreEqual([]entities.FieldChange{{Field: "title", From: "title", To: "new-title"}}, h.Changes)
+	})).Return("history-id", nil).Once()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Create), mock.Anything, mock.MatchedBy(func(o entities.OutboxEvent) bool {
+		var e models.TaskEvent
+		return o.Type == entities.WebhookEventTaskUpdated && json.Unmarshal(o.Payload, &e) == nil &&
+			e.ID == "history-id" && e.Type == entities.WebhookEventTaskUpdated && e.TaskID == task.ID && e.UserID == task.UserID &&
+			assert.ObjectsAreEqual([]models.FieldChange{{Field: "title", From: "title", To: "new-title"}}, e.Changes)
+	})).Return("event-id", nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: historyRepositoryMock,
+		writer:            NewTaskWriter(taskRepositoryMock, historyRepositoryMock, outboxRepositoryMock, newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 0, models.UpdateTaskReq{Title: "new-title", Priority: entities.TaskPriorityMedium})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdate_NoChanges checks that Update does not record any history when nothing changes
+func TestUpdate_NoChanges(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:       "task-id",
+		UserID:   "user-123",
+		Title:    "title",
+		Priority: entities.TaskPriorityMedium,
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.AnythingOfType("entities.Task"), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: mocks.NewTaskHistoryRepository(t),
+		writer:            NewTaskWriter(taskRepositoryMock, mocks.NewTaskHistoryRepository(t), nil, newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 0, models.UpdateTaskReq{Title: task.Title})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetHistory_Ok checks that GetHistory returns the history entries of the task
+func TestGetHistory_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	entry := entities.TaskHistory{
+		ID:        "history-id",
+		TaskID:    task.ID,
+		ActorID:   task.UserID,
+		Action:    entities.TaskHistoryActionUpdated,
+		Changes:   []entities.FieldChange{{Field: "title", From: "title", To: "new-title"}},
+		CreatedAt: time.Now().UTC(),
+	}
+	expectedResponse := []models.GetTaskHistoryResp{{
+		ID:        entry.ID,
+		TaskID:    entry.TaskID,
+		ActorID:   entry.ActorID,
+		Action:    entry.Action,
+		Changes:   []models.FieldChange{{Field: "title", From: "title", To: "new-title"}},
+		CreatedAt: entry.CreatedAt,
+	}}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.FindByTaskID), mock.Anything, task.ID).Return([]interface{}{&entry}, nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: historyRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetHistory(context.Background(), task.UserID, task.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestGetHistory_InvalidUser checks that GetHistory returns an error when the task does not belong to the received userID
+func TestGetHistory_InvalidUser(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		historyRepository:      mocks.NewTaskHistoryRepository(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.GetHistory(context.Background(), "other-user", task.ID)
+
+	// Assert
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+}
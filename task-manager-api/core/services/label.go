@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// labelService adapter of a label service
+type labelService struct {
+	config         config.Config
+	repository     ports.LabelRepository
+	taskRepository ports.TaskRepository
+	writer         *TaskWriter
+	transactions   ports.TransactionManager
+}
+
+// NewLabelService creates a new label service
+func NewLabelService(cfg config.Config, repo ports.LabelRepository, taskRepo ports.TaskRepository, writer *TaskWriter, transactions ports.TransactionManager) ports.LabelService {
+	return &labelService{
+		config:         cfg,
+		repository:     repo,
+		taskRepository: taskRepo,
+		writer:         writer,
+		transactions:   transactions,
+	}
+}
+
+// Create label
+func (l *labelService) Create(ctx context.Context, userID string, label models.CreateLabelReq) (resp models.CreateLabelResp, err error) {
+	label.Name, err = validateLabelName(label.Name)
+	if err != nil {
+		return
+	}
+
+	err = l.checkNameAvailable(ctx, userID, label.Name)
+	if err != nil {
+		return
+	}
+
+	entity := entities.Label(label)
+	entity.UserID = userID
+	entity.CreatedAt = time.Now().UTC()
+
+	id, err := l.repository.Create(ctx, entity)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreateLabelResp{
+		ID: id,
+	}
+
+	return
+}
+
+// GetByUserID gets the label catalogue of the received userID
+func (l *labelService) GetByUserID(ctx context.Context, userID string) (resp []models.GetLabelResp, err error) {
+	resp = []models.GetLabelResp{}
+
+	result, err := l.repository.Get(ctx, map[string]interface{}{"user_id": userID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	resp = make([]models.GetLabelResp, len(result))
+	for i, v := range result {
+		resp[i] = models.GetLabelResp(*(v.(*entities.Label)))
+	}
+
+	return
+}
+
+// Rename changes the name of a label, renaming it as well in all the tasks of the user
+func (l *labelService) Rename(ctx context.Context, userID, labelID string, rename models.RenameLabelReq) (err error) {
+	entity, err := l.getOwnedLabel(ctx, userID, labelID, "rename")
+	if err != nil {
+		return
+	}
+
+	rename.Name, err = validateLabelName(rename.Name)
+	if err != nil {
+		return
+	}
+	if rename.Name == entity.Name {
+		return
+	}
+
+	err = l.checkNameAvailable(ctx, userID, rename.Name)
+	if err != nil {
+		return
+	}
+
+	previousName := entity.Name
+	entity.ID = ""
+	entity.Name = rename.Name
+	err = l.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		err := l.repository.Update(ctx, labelID, *entity)
+		if err != nil {
+			return err
+		}
+
+		return l.relabelTasks(ctx, userID, previousName, rename.Name)
+	})
+	return
+}
+
+// Delete removes a label from the catalogue and from all the tasks of the user
+func (l *labelService) Delete(ctx context.Context, userID, labelID string) (err error) {
+	entity, err := l.getOwnedLabel(ctx, userID, labelID, "delete")
+	if err != nil {
+		return
+	}
+
+	err = l.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		err := l.repository.Delete(ctx, labelID)
+		if err != nil {
+			return err
+		}
+
+		return l.relabelTasks(ctx, userID, entity.Name)
+	})
+	return
+}
+
+// relabelTasks replaces the received label in all the tasks of the received userID by the received replacements, removing it when there are none,
+// and records the change in the history of each task. It is meant to be run in the transaction of the change to the label
+func (l *labelService) relabelTasks(ctx context.Context, userID, label string, replacements ...string) error {
+	result, err := l.taskRepository.Get(ctx, map[string]interface{}{"user_id": userID, "labels": label}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return err
+	}
+
+	tasks := make([]entities.Task, len(result))
+	for i, v := range result {
+		tasks[i] = *(v.(*entities.Task))
+	}
+
+	now := time.Now().UTC()
+	return l.writer.saveTasks(ctx, userID, entities.TaskHistoryActionUpdated, tasks, func(task *entities.Task) {
+		// a new slice is built, as the previous labels are kept to record the change
+		labels := make([]string, 0, len(task.Labels))
+		for _, v := range task.Labels {
+			if v != label {
+				labels = append(labels, v)
+				continue
+			}
+			for _, replacement := range replacements {
+				if !slices.Contains(labels, replacement) && !slices.Contains(task.Labels, replacement) {
+					labels = append(labels, replacement)
+				}
+			}
+		}
+		task.Labels = labels
+		task.UpdatedAt = now
+	})
+}
+
+// getOwnedLabel gets a label and checks that it belongs to the received userID
+func (l *labelService) getOwnedLabel(ctx context.Context, userID, labelID, action string) (*entities.Label, error) {
+	result, err := l.repository.GetByID(ctx, labelID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("LabelID %s not found", labelID))
+		}
+		return nil, err
+	}
+
+	label := result.(*entities.Label)
+	if label.UserID != userID {
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to %s LabelID %s", userID, action, labelID))
+	}
+
+	return label, nil
+}
+
+// checkNameAvailable checks that the received userID does not have a label with the received name yet
+func (l *labelService) checkNameAvailable(ctx context.Context, userID, name string) error {
+	_, err := l.repository.Get(ctx, map[string]interface{}{"user_id": userID, "name": name}, nil, nil)
+	if err == nil {
+		return wrappers.NewValidationErr(fmt.Errorf("label %s already exists", name))
+	}
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return nil
+	}
+	return err
+}
+
+// validateLabelName trims the received label name and checks that it is not empty and does not contain commas,
+// as they are used to separate the labels in the task filters
+func validateLabelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", wrappers.NewValidationErr(errors.New("label name cannot be empty"))
+	}
+	if strings.Contains(name, ",") {
+		return "", wrappers.NewValidationErr(fmt.Errorf("label name %s cannot contain commas", name))
+	}
+	return name, nil
+}
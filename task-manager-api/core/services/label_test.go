@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateLabel_Ok checks that Create returns the expected response when a valid request is received
+func TestCreateLabel_Ok(t *testing.T) {
+	// Arrange
+	req := models.CreateLabelReq{
+		Name: " work ",
+	}
+
+	expectedResponse := models.CreateLabelResp{
+		ID: "new-id",
+	}
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123", "name": "work"}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Create), mock.Anything, mock.MatchedBy(func(label entities.Label) bool {
+		return label.Name == "work" && label.UserID == "user-123"
+	})).Return(expectedResponse.ID, nil).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-123", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestCreateLabel_InvalidName checks that Create returns a validation error when the label name is not valid
+func TestCreateLabel_InvalidName(t *testing.T) {
+	tests := []struct {
+		name      string
+		labelName string
+	}{
+		{"empty name", "  "},
+		{"name with commas", "work,urgent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &labelService{}
+
+			// Act
+			_, err := service.Create(context.Background(), "user-123", models.CreateLabelReq{Name: tt.labelName})
+
+			// Assert
+			assert.NotEmpty(t, err)
+			assert.IsType(t, wrappers.ValidationErr, err)
+		})
+	}
+}
+
+// TestCreateLabel_AlreadyExists checks that Create returns a validation error when the user already has a label with the same name
+func TestCreateLabel_AlreadyExists(t *testing.T) {
+	// Arrange
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&entities.Label{Name: "work"}}, nil).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", models.CreateLabelReq{Name: "work"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetLabelsByUserID_Ok checks that GetByUserID returns the label catalogue of the user
+func TestGetLabelsByUserID_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	labels := []entities.Label{
+		{ID: "label-id-1", UserID: "user-123", Name: "work", CreatedAt: now},
+		{ID: "label-id-2", UserID: "user-123", Name: "home", CreatedAt: now},
+	}
+	expectedResponse := []models.GetLabelResp{
+		models.GetLabelResp(labels[0]),
+		models.GetLabelResp(labels[1]),
+	}
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123"}, mock.Anything, mock.Anything).Return([]interface{}{&labels[0], &labels[1]}, nil).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), "user-123")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestGetLabelsByUserID_NoResourcesFound checks that GetByUserID returns an empty list when the user has no labels
+func TestGetLabelsByUserID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), "user-123")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.GetLabelResp{}, resp)
+}
+
+// TestRenameLabel_Ok checks that Rename updates the label and renames it in the tasks of the user
+func TestRenameLabel_Ok(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+	label := &entities.Label{ID: labelID, UserID: "user-123", Name: "work"}
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(label, nil).Once()
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Update), mock.Anything, labelID, mock.MatchedBy(func(label entities.Label) bool {
+		return label.Name == "office" && label.ID == ""
+	})).Return(nil).Once()
+
+	task := &entities.Task{ID: "task-id", UserID: "user-123", Labels: []string{"urgent", "work"}, Version: 3}
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123", "labels": "work"}, mock.Anything, mock.Anything).Return([]interface{}{task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return assert.ObjectsAreEqual([]string{"urgent", "office"}, e.Labels)
+	}), task.Version).Return(nil).Once()
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskHistory) bool {
+		return e.TaskID == task.ID && len(e.Changes) == 1 && e.Changes[0].Field == "labels"
+	})).Return("history-id", nil).Once()
+
+	service := &labelService{
+		config:         config.Config{},
+		repository:     labelRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		writer:         NewTaskWriter(taskRepositoryMock, historyRepositoryMock, newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:   newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Rename(context.Background(), "user-123", labelID, models.RenameLabelReq{Name: "office"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRenameLabel_SameName checks that Rename does nothing when the new name is the current one
+func TestRenameLabel_SameName(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(&entities.Label{ID: labelID, UserID: "user-123", Name: "work"}, nil).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	err := service.Rename(context.Background(), "user-123", labelID, models.RenameLabelReq{Name: "work"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRenameLabel_InvalidUser checks that Rename returns an error when the label belongs to another user
+func TestRenameLabel_InvalidUser(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(&entities.Label{ID: labelID, UserID: "other-user", Name: "work"}, nil).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	err := service.Rename(context.Background(), "user-123", labelID, models.RenameLabelReq{Name: "office"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+}
+
+// TestDeleteLabel_Ok checks that Delete removes the label from the catalogue and from the tasks of the user
+func TestDeleteLabel_Ok(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(&entities.Label{ID: labelID, UserID: "user-123", Name: "work"}, nil).Once()
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Delete), mock.Anything, labelID).Return(nil).Once()
+
+	task := &entities.Task{ID: "task-id", UserID: "user-123", Labels: []string{"work", "urgent"}, Version: 3}
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123", "labels": "work"}, mock.Anything, mock.Anything).Return([]interface{}{task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return assert.ObjectsAreEqual([]string{"urgent"}, e.Labels)
+	}), task.Version).Return(nil).Once()
+
+	service := &labelService{
+		config:         config.Config{},
+		repository:     labelRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		writer:         NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:   newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", labelID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteLabel_NotFound checks that Delete returns a NonExistentErr when the label does not exist
+func TestDeleteLabel_NotFound(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &labelService{
+		config:     config.Config{},
+		repository: labelRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", labelID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestDeleteLabel_RepositoryError checks that Delete returns an error when the repository fails
+func TestDeleteLabel_RepositoryError(t *testing.T) {
+	// Arrange
+	labelID := "label-id"
+	expectedError := "repository-error"
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.GetByID), mock.Anything, labelID).Return(&entities.Label{ID: labelID, UserID: "user-123", Name: "work"}, nil).Once()
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Delete), mock.Anything, labelID).Return(errors.New(expectedError)).Once()
+
+	service := &labelService{
+		config:       config.Config{},
+		repository:   labelRepositoryMock,
+		transactions: newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", labelID)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// outboxService adapter of an outbox service
+type outboxService struct {
+	config     config.Config
+	repository ports.OutboxRepository
+	publisher  ports.EventPublisher
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(cfg config.Config, repo ports.OutboxRepository, publisher ports.EventPublisher) ports.OutboxService {
+	return &outboxService{
+		config:     cfg,
+		repository: repo,
+		publisher:  publisher,
+	}
+}
+
+// Relay publishes up to the configured batch size of outbox events, the oldest first, removing each one once published.
+// An event is published at least once: when the process stops before removing it, it is published again once its lease expires.
+// It returns the number of events published
+func (o *outboxService) Relay(ctx context.Context) (int, error) {
+	for i := 0; i < o.config.Outbox.BatchSize; i++ {
+		now := time.Now().UTC()
+		result, err := o.repository.ClaimNext(ctx, now, now.Add(o.config.Outbox.Lease.Duration))
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				err = nil
+			}
+			return i, err
+		}
+		outboxEvent := result.(*entities.OutboxEvent)
+
+		var event models.TaskEvent
+		err = json.Unmarshal(outboxEvent.Payload, &event)
+		if err != nil {
+			return i, fmt.Errorf("outbox event %s not valid: %w", outboxEvent.ID, err)
+		}
+
+		err = o.publisher.Publish(ctx, event)
+		if err != nil {
+			return i, err
+		}
+
+		err = o.repository.Delete(ctx, outboxEvent.ID)
+		if err != nil {
+			return i + 1, err
+		}
+	}
+	return o.config.Outbox.BatchSize, nil
+}
+
+// newOutboxEvent builds the outbox event of a task event, available to be published right away
+func newOutboxEvent(event models.TaskEvent) (entities.OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return entities.OutboxEvent{}, err
+	}
+
+	return entities.OutboxEvent{
+		Type:        event.Type,
+		Payload:     payload,
+		AvailableAt: event.CreatedAt,
+		CreatedAt:   event.CreatedAt,
+	}, nil
+}
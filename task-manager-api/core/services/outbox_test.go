@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newOutboxRepositoryMock creates an outbox repository mock accepting any event
+func newOutboxRepositoryMock(t *testing.T) *mocks.OutboxRepository {
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Create), mock.Anything, mock.AnythingOfType("entities.OutboxEvent")).Return("event-id", nil).Maybe()
+	return outboxRepositoryMock
+}
+
+// newTransactionManagerMock creates a transaction manager mock running the received functions straight away
+func newTransactionManagerMock(t *testing.T) *mocks.TransactionManager {
+	transactionManagerMock := mocks.NewTransactionManager(t)
+	transactionManagerMock.On(testutils.FunctionName(t, ports.TransactionManager.WithTransaction), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return transactionManagerMock
+}
+
+// newOutboxConfig creates a config relaying up to 10 events per run
+func newOutboxConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Outbox.BatchSize = 10
+	cfg.Outbox.Lease.Duration = time.Minute
+	return cfg
+}
+
+// TestRelay_Ok checks that Relay publishes the available events and removes them once published
+func TestRelay_Ok(t *testing.T) {
+	// Arrange
+	event := models.TaskEvent{ID: "history-id", Type: entities.WebhookEventTaskCreated, TaskID: "task-id", UserID: "user-123", CreatedAt: time.Now().UTC()}
+	outboxEvent, err := newOutboxEvent(event)
+	assert.Nil(t, err)
+	outboxEvent.ID = "event-id"
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.ClaimNext), mock.Anything, mock.Anything, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		return leaseUntil.After(time.Now().Add(59 * time.Second))
+	})).Return(&outboxEvent, nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.ClaimNext), mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Delete), mock.Anything, outboxEvent.ID).Return(nil).Once()
+
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, mock.MatchedBy(func(e models.TaskEvent) bool {
+		return e.ID == event.ID && e.Type == event.Type && e.TaskID == event.TaskID && e.UserID == event.UserID && e.CreatedAt.Equal(event.CreatedAt)
+	})).Return(nil).Once()
+
+	service := &outboxService{
+		config:     newOutboxConfig(),
+		repository: outboxRepositoryMock,
+		publisher:  publisherMock,
+	}
+
+	// Act
+	published, err := service.Relay(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+}
+
+// TestRelay_PublishError checks that Relay keeps an event that cannot be published, so that it is published again once its lease expires
+func TestRelay_PublishError(t *testing.T) {
+	// Arrange
+	outboxEvent, err := newOutboxEvent(models.TaskEvent{ID: "history-id", Type: entities.WebhookEventTaskDeleted})
+	assert.Nil(t, err)
+	outboxEvent.ID = "event-id"
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.ClaimNext), mock.Anything, mock.Anything, mock.Anything).Return(&outboxEvent, nil).Once()
+
+	publisherMock := mocks.NewEventPublisher(t)
+	publisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, mock.AnythingOfType("models.TaskEvent")).Return(errors.New("publish-error")).Once()
+
+	service := &outboxService{
+		config:     newOutboxConfig(),
+		repository: outboxRepositoryMock,
+		publisher:  publisherMock,
+	}
+
+	// Act
+	published, err := service.Relay(context.Background())
+
+	// Assert
+	assert.Equal(t, "publish-error", err.Error())
+	assert.Equal(t, 0, published)
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	projectTasksInbox  = "inbox"
+	projectTasksDelete = "delete"
+)
+
+// projectColorRegexp matches the hexadecimal RGB colors of the projects
+var projectColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// projectService adapter of a project service
+type projectService struct {
+	config         config.Config
+	repository     ports.ProjectRepository
+	taskRepository ports.TaskRepository
+	writer         *TaskWriter
+	transactions   ports.TransactionManager
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(cfg config.Config, repo ports.ProjectRepository, taskRepo ports.TaskRepository, writer *TaskWriter, transactions ports.TransactionManager) ports.ProjectService {
+	return &projectService{
+		config:         cfg,
+		repository:     repo,
+		taskRepository: taskRepo,
+		writer:         writer,
+		transactions:   transactions,
+	}
+}
+
+// Create project
+func (p *projectService) Create(ctx context.Context, userID string, project models.CreateProjectReq) (resp models.CreateProjectResp, err error) {
+	project.Name, project.Color, err = validateProject(project.Name, project.Color)
+	if err != nil {
+		return
+	}
+
+	err = p.checkNameAvailable(ctx, userID, project.Name)
+	if err != nil {
+		return
+	}
+
+	entity := entities.Project(project)
+	entity.UserID = userID
+	entity.Archived = false
+	entity.CreatedAt = time.Now().UTC()
+	entity.UpdatedAt = entity.CreatedAt
+
+	id, err := p.repository.Create(ctx, entity)
+	if err != nil {
+		return
+	}
+
+	resp = models.CreateProjectResp{
+		ID: id,
+	}
+
+	return
+}
+
+// GetByUserID gets the projects of the received userID, archived or not, with their task counts
+func (p *projectService) GetByUserID(ctx context.Context, userID string) (resp []models.GetProjectResp, err error) {
+	resp = []models.GetProjectResp{}
+
+	result, err := p.repository.Get(ctx, map[string]interface{}{"user_id": userID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	projectIDs := make([]string, len(result))
+	for i, v := range result {
+		projectIDs[i] = v.(*entities.Project).ID
+	}
+
+	counts, err := p.taskRepository.CountByProject(ctx, projectIDs)
+	if err != nil {
+		return
+	}
+
+	for _, v := range result {
+		project := v.(*entities.Project)
+		resp = append(resp, newGetProjectResp(*project, counts[project.ID]))
+	}
+	return
+}
+
+// GetByID gets a project of the received userID with its task counts
+func (p *projectService) GetByID(ctx context.Context, userID, projectID string) (resp models.GetProjectResp, err error) {
+	project, err := getOwnedProject(ctx, p.repository, userID, projectID, "get")
+	if err != nil {
+		return
+	}
+
+	counts, err := p.taskRepository.CountByProject(ctx, []string{projectID})
+	if err != nil {
+		return
+	}
+
+	return newGetProjectResp(*project, counts[projectID]), nil
+}
+
+// Update replaces the name, color and archived flag of a project. Archived projects keep their tasks but cannot receive new ones
+func (p *projectService) Update(ctx context.Context, userID, projectID string, project models.UpdateProjectReq) (err error) {
+	entity, err := getOwnedProject(ctx, p.repository, userID, projectID, "update")
+	if err != nil {
+		return
+	}
+
+	project.Name, project.Color, err = validateProject(project.Name, project.Color)
+	if err != nil {
+		return
+	}
+
+	if project.Name != entity.Name {
+		err = p.checkNameAvailable(ctx, userID, project.Name)
+		if err != nil {
+			return
+		}
+	}
+
+	entity.ID = ""
+	entity.Name = project.Name
+	entity.Color = project.Color
+	entity.Archived = project.Archived
+	entity.UpdatedAt = time.Now().UTC()
+	return p.repository.Update(ctx, projectID, *entity)
+}
+
+// Delete removes a project, moving its tasks to the inbox or to the trash depending on the received tasks mode
+func (p *projectService) Delete(ctx context.Context, userID, projectID, tasks string) (err error) {
+	_, err = getOwnedProject(ctx, p.repository, userID, projectID, "delete")
+	if err != nil {
+		return
+	}
+
+	if !slices.Contains([]string{"", projectTasksInbox, projectTasksDelete}, tasks) {
+		return wrappers.NewValidationErr(fmt.Errorf("tasks %s not valid, allowed values: %s, %s", tasks, projectTasksInbox, projectTasksDelete))
+	}
+
+	err = p.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if tasks == projectTasksDelete {
+			err = p.trashTasks(ctx, userID, projectID)
+		} else {
+			err = p.clearTasks(ctx, userID, projectID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return p.repository.Delete(ctx, projectID)
+	})
+	return
+}
+
+// clearTasks moves all the tasks of a project, including the ones in the trash, to the inbox.
+// It is meant to be run in the transaction of the deletion of the project
+func (p *projectService) clearTasks(ctx context.Context, userID, projectID string) error {
+	tasks, err := p.getTasks(ctx, map[string]interface{}{"project_id": projectID})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return p.writer.saveTasks(ctx, userID, entities.TaskHistoryActionUpdated, tasks, func(task *entities.Task) {
+		task.ProjectID = ""
+		task.UpdatedAt = now
+	})
+}
+
+// trashTasks moves all the tasks of a project that are not in the trash yet to the trash together with their subtasks,
+// all with the same deletion time so that restoring a task restores its subtasks as well.
+// It is meant to be run in the transaction of the deletion of the project
+func (p *projectService) trashTasks(ctx context.Context, userID, projectID string) error {
+	tasks, err := p.getTasks(ctx, map[string]interface{}{"project_id": projectID, "deleted_at": nil})
+	if err != nil {
+		return err
+	}
+
+	deleted := make([]entities.Task, 0, len(tasks))
+	found := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if found[task.ID] {
+			continue
+		}
+		descendants, err := getDescendants(ctx, p.taskRepository, task.ID, nil)
+		if err != nil {
+			return err
+		}
+		for _, v := range append([]entities.Task{task}, descendants...) {
+			if !found[v.ID] {
+				found[v.ID] = true
+				deleted = append(deleted, v)
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	return p.writer.saveTasks(ctx, userID, entities.TaskHistoryActionDeleted, deleted, func(task *entities.Task) {
+		task.DeletedAt = &now
+		task.UpdatedAt = now
+	})
+}
+
+// getTasks gets the tasks matching the received filter, or none when no task matches it
+func (p *projectService) getTasks(ctx context.Context, filter map[string]interface{}) ([]entities.Task, error) {
+	result, err := p.taskRepository.Get(ctx, filter, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	tasks := make([]entities.Task, len(result))
+	for i, v := range result {
+		tasks[i] = *(v.(*entities.Task))
+	}
+	return tasks, nil
+}
+
+// checkNameAvailable checks that the received userID does not have a project with the received name yet
+func (p *projectService) checkNameAvailable(ctx context.Context, userID, name string) error {
+	_, err := p.repository.Get(ctx, map[string]interface{}{"user_id": userID, "name": name}, nil, nil)
+	if err == nil {
+		return wrappers.NewValidationErr(fmt.Errorf("project %s already exists", name))
+	}
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return nil
+	}
+	return err
+}
+
+// getOwnedProject gets a project and checks that it belongs to the received userID
+func getOwnedProject(ctx context.Context, projectRepository ports.ProjectRepository, userID, projectID, action string) (*entities.Project, error) {
+	result, err := projectRepository.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ProjectID %s not found", projectID))
+		}
+		return nil, err
+	}
+
+	project := result.(*entities.Project)
+	if project.UserID != userID {
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to %s ProjectID %s", userID, action, projectID))
+	}
+
+	return project, nil
+}
+
+// validateProject trims the received project name and checks that it is not empty, and that the color, if any, is a hexadecimal RGB color
+func validateProject(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", wrappers.NewValidationErr(errors.New("project name cannot be empty"))
+	}
+	if color != "" && !projectColorRegexp.MatchString(color) {
+		return "", "", wrappers.NewValidationErr(fmt.Errorf("color %s not valid, expected a hexadecimal RGB color such as #4f46e5", color))
+	}
+	return name, strings.ToLower(color), nil
+}
+
+// newGetProjectResp builds the response of a project with its task counts
+func newGetProjectResp(project entities.Project, counts models.ProjectTaskCounts) models.GetProjectResp {
+	return models.GetProjectResp{
+		ID:         project.ID,
+		UserID:     project.UserID,
+		Name:       project.Name,
+		Color:      project.Color,
+		Archived:   project.Archived,
+		CreatedAt:  project.CreatedAt,
+		UpdatedAt:  project.UpdatedAt,
+		TaskCounts: counts,
+	}
+}
@@ -0,0 +1,509 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateProject_Ok checks that Create returns the expected response when a valid request is received
+func TestCreateProject_Ok(t *testing.T) {
+	// Arrange
+	req := models.CreateProjectReq{
+		Name:  " Home ",
+		Color: "#4F46E5",
+	}
+
+	expectedResponse := models.CreateProjectResp{
+		ID: "new-id",
+	}
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123", "name": "Home"}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Create), mock.Anything, mock.MatchedBy(func(project entities.Project) bool {
+		return project.Name == "Home" && project.Color == "#4f46e5" && project.UserID == "user-123" && !project.Archived && !project.CreatedAt.IsZero()
+	})).Return(expectedResponse.ID, nil).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-123", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestCreateProject_InvalidRequest checks that Create returns a validation error when the name or the color are not valid
+func TestCreateProject_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		project models.CreateProjectReq
+	}{
+		{"empty name", models.CreateProjectReq{Name: "  "}},
+		{"invalid color", models.CreateProjectReq{Name: "Home", Color: "blue"}},
+		{"short color", models.CreateProjectReq{Name: "Home", Color: "#fff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &projectService{}
+
+			// Act
+			_, err := service.Create(context.Background(), "user-123", tt.project)
+
+			// Assert
+			assert.NotEmpty(t, err)
+			assert.IsType(t, wrappers.ValidationErr, err)
+		})
+	}
+}
+
+// TestCreateProject_AlreadyExists checks that Create returns a validation error when the user already has a project with the same name
+func TestCreateProject_AlreadyExists(t *testing.T) {
+	// Arrange
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&entities.Project{Name: "Home"}}, nil).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", models.CreateProjectReq{Name: "Home"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetProjectsByUserID_Ok checks that GetByUserID returns the projects of the user with their task counts
+func TestGetProjectsByUserID_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	projects := []entities.Project{
+		{ID: "project-1", UserID: "user-123", Name: "Home", CreatedAt: now, UpdatedAt: now},
+		{ID: "project-2", UserID: "user-123", Name: "Work", Archived: true, CreatedAt: now, UpdatedAt: now},
+	}
+	counts := map[string]models.ProjectTaskCounts{"project-1": {Total: 3, Open: 2, Done: 1}}
+	expectedResponse := []models.GetProjectResp{
+		newGetProjectResp(projects[0], counts["project-1"]),
+		newGetProjectResp(projects[1], models.ProjectTaskCounts{}),
+	}
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Get), mock.Anything, map[string]interface{}{"user_id": "user-123"}, mock.Anything, mock.Anything).Return([]interface{}{&projects[0], &projects[1]}, nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountByProject), mock.Anything, []string{"project-1", "project-2"}).Return(counts, nil).Once()
+
+	service := &projectService{
+		config:         config.Config{},
+		repository:     projectRepositoryMock,
+		taskRepository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), "user-123")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestGetProjectsByUserID_NoResourcesFound checks that GetByUserID returns an empty list when the user has no projects
+func TestGetProjectsByUserID_NoResourcesFound(t *testing.T) {
+	// Arrange
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), "user-123")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.GetProjectResp{}, resp)
+}
+
+// TestGetProjectByID_InvalidUser checks that GetByID returns an unauthenticated error when the project belongs to another user
+func TestGetProjectByID_InvalidUser(t *testing.T) {
+	// Arrange
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, "project-id").Return(&entities.Project{ID: "project-id", UserID: "other-user"}, nil).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetByID(context.Background(), "user-123", "project-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthenticatedErr))
+}
+
+// TestUpdateProject_Ok checks that Update replaces the name, color and archived flag of the project
+func TestUpdateProject_Ok(t *testing.T) {
+	// Arrange
+	project := entities.Project{ID: "project-id", UserID: "user-123", Name: "Home", Color: "#ffffff"}
+	req := models.UpdateProjectReq{Name: "Home", Color: "#000000", Archived: true}
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, project.ID).Return(&project, nil).Once()
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Update), mock.Anything, project.ID, mock.MatchedBy(func(e entities.Project) bool {
+		return e.ID == "" && e.Name == req.Name && e.Color == req.Color && e.Archived && !e.UpdatedAt.IsZero()
+	})).Return(nil).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	err := service.Update(context.Background(), project.UserID, project.ID, req)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteProject_Ok checks that Delete moves the tasks of the project to the inbox or to the trash depending on the requested mode
+func TestDeleteProject_Ok(t *testing.T) {
+	tests := []struct {
+		name           string
+		tasks          string
+		expectedFilter map[string]interface{}
+		expectedAction string
+		expectedTask   func(e entities.Task) bool
+	}{
+		{
+			"default to inbox", "", map[string]interface{}{"project_id": "project-id"}, entities.TaskHistoryActionUpdated,
+			func(e entities.Task) bool { return e.ProjectID == "" && e.DeletedAt == nil },
+		},
+		{
+			"inbox", projectTasksInbox, map[string]interface{}{"project_id": "project-id"}, entities.TaskHistoryActionUpdated,
+			func(e entities.Task) bool { return e.ProjectID == "" && e.DeletedAt == nil },
+		},
+		{
+			"delete", projectTasksDelete, map[string]interface{}{"project_id": "project-id", "deleted_at": nil}, entities.TaskHistoryActionDeleted,
+			func(e entities.Task) bool { return e.ProjectID == "project-id" && e.DeletedAt != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			projectID := "project-id"
+			task := entities.Task{ID: "task-id", UserID: "user-123", ProjectID: projectID, Version: 2}
+
+			projectRepositoryMock := mocks.NewProjectRepository(t)
+			projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, projectID).Return(&entities.Project{ID: projectID, UserID: "user-123"}, nil).Once()
+			projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Delete), mock.Anything, projectID).Return(nil).Once()
+
+			taskRepositoryMock := mocks.NewTaskRepository(t)
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, tt.expectedFilter, mock.Anything, mock.Anything).Return([]interface{}{&task}, nil).Once()
+			if tt.tasks == projectTasksDelete {
+				taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{task.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+			}
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(tt.expectedTask), task.Version).Return(nil).Once()
+
+			historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+			historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskHistory) bool {
+				return e.TaskID == task.ID && e.Action == tt.expectedAction
+			})).Return("history-id", nil).Once()
+
+			service := &projectService{
+				config:         config.Config{},
+				repository:     projectRepositoryMock,
+				taskRepository: taskRepositoryMock,
+				writer:         NewTaskWriter(taskRepositoryMock, historyRepositoryMock, newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+				transactions:   newTransactionManagerMock(t),
+			}
+
+			// Act
+			err := service.Delete(context.Background(), "user-123", projectID, tt.tasks)
+
+			// Assert
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// TestDeleteProject_TrashesSubtasks checks that Delete moves the subtasks of the tasks of the project to the trash together with them
+func TestDeleteProject_TrashesSubtasks(t *testing.T) {
+	// Arrange
+	projectID := "project-id"
+	task := entities.Task{ID: "task-id", UserID: "user-123", ProjectID: projectID}
+	subtask := entities.Task{ID: "subtask-id", UserID: "user-123", ProjectID: projectID, ParentID: task.ID}
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, projectID).Return(&entities.Project{ID: projectID, UserID: "user-123"}, nil).Once()
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.Delete), mock.Anything, projectID).Return(nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"project_id": projectID, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&task, &subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{task.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{subtask.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	var deletedAt []*time.Time
+	for _, id := range []string{task.ID, subtask.ID} {
+		taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, id, mock.MatchedBy(func(e entities.Task) bool {
+			return e.DeletedAt != nil
+		}), 0).Run(func(args mock.Arguments) {
+			deletedAt = append(deletedAt, args.Get(2).(entities.Task).DeletedAt)
+		}).Return(nil).Once()
+	}
+
+	service := &projectService{
+		config:         config.Config{},
+		repository:     projectRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		writer:         NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:   newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", projectID, projectTasksDelete)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, deletedAt, 2)
+	assert.Equal(t, deletedAt[0], deletedAt[1])
+}
+
+// TestDeleteProject_TaskError checks that Delete keeps the project when the tasks of the project cannot be moved
+func TestDeleteProject_TaskError(t *testing.T) {
+	// Arrange
+	projectID := "project-id"
+	expectedError := "repository-error"
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, projectID).Return(&entities.Project{ID: projectID, UserID: "user-123"}, nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&entities.Task{ID: "task-id", ProjectID: projectID}}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(expectedError)).Once()
+
+	service := &projectService{
+		config:         config.Config{},
+		repository:     projectRepositoryMock,
+		taskRepository: taskRepositoryMock,
+		writer:         NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions:   newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", projectID, projectTasksInbox)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteProject_InvalidMode checks that Delete returns a validation error and keeps the project when the tasks mode is not valid
+func TestDeleteProject_InvalidMode(t *testing.T) {
+	// Arrange
+	projectID := "project-id"
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, projectID).Return(&entities.Project{ID: projectID, UserID: "user-123"}, nil).Once()
+
+	service := &projectService{
+		config:     config.Config{},
+		repository: projectRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", projectID, "archive")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestCreate_ArchivedProject checks that Create returns a validation error when the task is added to an archived project
+func TestCreate_ArchivedProject(t *testing.T) {
+	// Arrange
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, "project-id").Return(&entities.Project{ID: "project-id", UserID: "user-123", Archived: true}, nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		projectRepository:    projectRepositoryMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", models.CreateTaskReq{Title: "title", ProjectID: "project-id"}, "token")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestUpdate_MovesProject checks that Update moves a top-level task to another project together with its subtasks
+func TestUpdate_MovesProject(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", ProjectID: "project-1"}
+	subtask := entities.Task{ID: "subtask-id", UserID: task.UserID, ParentID: task.ID, ProjectID: "project-1"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.ProjectID == "project-2"
+	}), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, subtask.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.ProjectID == "project-2"
+	}), subtask.Version).Return(nil).Once()
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, "project-2").Return(&entities.Project{ID: "project-2", UserID: task.UserID}, nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		projectRepository: projectRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 0, models.UpdateTaskReq{Title: "title", ProjectID: "project-2"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdate_MovesProjectSubtaskError checks that Update moves the subtasks in the same transaction as the task, so that nothing is stored
+// when a subtask cannot be moved
+func TestUpdate_MovesProjectSubtaskError(t *testing.T) {
+	// Arrange
+	type transactionKey struct{}
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", ProjectID: "project-1", Version: 2}
+	subtask := entities.Task{ID: "subtask-id", UserID: task.UserID, ParentID: task.ID, ProjectID: "project-1"}
+	inTransaction := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(transactionKey{}) != nil
+	})
+	expectedError := "update-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), inTransaction, task.ID, mock.Anything, task.Version).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), inTransaction, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), inTransaction, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), inTransaction, subtask.ID, mock.Anything, subtask.Version).Return(errors.New(expectedError)).Once()
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, "project-2").Return(&entities.Project{ID: "project-2", UserID: task.UserID}, nil).Once()
+
+	transactionManagerMock := mocks.NewTransactionManager(t)
+	transactionManagerMock.On(testutils.FunctionName(t, ports.TransactionManager.WithTransaction), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(context.WithValue(ctx, transactionKey{}, true))
+	}).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		projectRepository: projectRepositoryMock,
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      transactionManagerMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, task.Version, models.UpdateTaskReq{Title: "title", ProjectID: "project-2"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestRestore_DeletedProject checks that Restore moves the task to the inbox when its project was deleted while it was in the trash
+func TestRestore_DeletedProject(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", ProjectID: "project-id", DeletedAt: &deletedAt}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.DeletedAt == nil && e.ProjectID == ""
+	}), task.Version).Return(nil).Once()
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, task.ProjectID).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		projectRepository: projectRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Restore(context.Background(), task.UserID, task.ID, 0)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestGetByUserID_Project checks that GetByUserID only finds the tasks of the requested project
+func TestGetByUserID_Project(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", ProjectID: "project-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.UserID == task.UserID && f.ProjectID == task.ProjectID
+	})).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	projectRepositoryMock := mocks.NewProjectRepository(t)
+	projectRepositoryMock.On(testutils.FunctionName(t, ports.ProjectRepository.GetByID), mock.Anything, task.ProjectID).Return(&entities.Project{ID: task.ProjectID, UserID: task.UserID}, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:            cfg,
+		repository:        taskRepositoryMock,
+		projectRepository: projectRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), task.UserID, models.GetTasksReq{ProjectID: task.ProjectID})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Tasks, 1)
+	assert.Equal(t, task.ProjectID, resp.Tasks[0].ProjectID)
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// rankDigits are the digits of the ranks, sorted in byte order so that ranks compare lexicographically.
+// Ranks never end with the first digit, which keeps room for a rank before any other one
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Move places a task in a board column between the received neighbours, changing its status when the column differs from the current one.
+// Only the rank of the moved task changes, so the rest of the column is only rewritten when a concurrent move has placed another task
+// at the same rank. It returns the new version of the task
+func (t *taskService) Move(ctx context.Context, userID, taskID string, version int, move models.MoveTaskReq) (newVersion int, err error) {
+	entity, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "move")
+	if err != nil {
+		return
+	}
+
+	if move.Status == "" {
+		move.Status = entity.Status
+	}
+
+	lower, upper, err := t.neighbourRanks(ctx, userID, entity, move)
+	if err != nil {
+		return
+	}
+
+	before := *entity
+	if move.Status != entity.Status {
+		err = t.applyTransition(ctx, entity, move.Status)
+		if err != nil {
+			return
+		}
+	}
+
+	entity.Rank, err = rankBetween(lower, upper)
+	if err != nil {
+		return newVersion, wrappers.NewValidationErr(fmt.Errorf("before_id and after_id must be adjacent tasks in this order: %w", err))
+	}
+	entity.UpdatedAt = time.Now().UTC()
+
+	err = t.saveTransition(ctx, userID, before, entity)
+	if err != nil {
+		return
+	}
+	return t.resolveRankCollision(ctx, entity)
+}
+
+// maxRankCollisionAttempts is the number of times a column is rebalanced to resolve a rank collision before giving up
+const maxRankCollisionAttempts = 3
+
+// resolveRankCollision rebalances the column of the received task when another task has the same rank, which happens when
+// two tasks are moved into the same gap concurrently, as neither of them sees the rank taken by the other one. As the rebalance
+// may change the rank of the task, it returns its current version
+func (t *taskService) resolveRankCollision(ctx context.Context, entity *entities.Task) (int, error) {
+	column := models.TaskColumn{UserID: entity.UserID, Status: entity.Status}
+	for attempt := 0; ; attempt++ {
+		count, err := t.repository.CountRank(ctx, column.UserID, column.Status, entity.Rank)
+		if err != nil || count < 2 {
+			return entity.Version, err
+		}
+		if attempt == maxRankCollisionAttempts {
+			return entity.Version, taskWrappers.NewPreconditionFailedErr(fmt.Errorf("rank of TaskID %s could not be told apart from the rank of a concurrently moved task", entity.ID))
+		}
+
+		// the column changing while being rebalanced, such as by the rebalance of the other task of the collision, makes it check again
+		err = t.rebalanceColumn(ctx, column)
+		if err != nil && !errors.Is(err, taskWrappers.PreconditionFailedErr) {
+			return entity.Version, err
+		}
+
+		result, err := t.repository.GetByID(ctx, entity.ID)
+		if err != nil {
+			return entity.Version, err
+		}
+		entity = result.(*entities.Task)
+	}
+}
+
+// RebalanceRanks rewrites the ranks of the board columns with ranks longer than the configured maximum, or with unranked tasks,
+// spreading them evenly while keeping their order. It returns the number of columns rebalanced
+func (t *taskService) RebalanceRanks(ctx context.Context) (int, error) {
+	columns, err := t.repository.FindRankColumns(ctx, t.config.Ranks.MaxLength, t.config.Ranks.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		err = t.rebalanceColumn(ctx, column)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(columns), nil
+}
+
+// rebalanceColumn spreads the ranks of a board column evenly while keeping their order, only updating the tasks whose rank changes
+func (t *taskService) rebalanceColumn(ctx context.Context, column models.TaskColumn) error {
+	// the column is read in the transaction of the new ranks, so that a task changed meanwhile makes it run again
+	return t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		result, err := t.repository.FindColumn(ctx, column.UserID, column.Status)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				err = nil
+			}
+			return err
+		}
+
+		ranks := spreadRanks(len(result))
+		newRanks := make(map[string]string, len(result))
+		changed := make([]entities.Task, 0, len(result))
+		for j, v := range result {
+			task := v.(*entities.Task)
+			if task.Rank != ranks[j] {
+				newRanks[task.ID] = ranks[j]
+				changed = append(changed, *task)
+			}
+		}
+
+		return t.writer.saveTasks(ctx, entities.TaskHistoryActorSystem, entities.TaskHistoryActionUpdated, changed, func(task *entities.Task) {
+			task.Rank = newRanks[task.ID]
+		})
+	})
+}
+
+// neighbourRanks gets the ranks a moved task has to be placed between, checking that the neighbours are in the target column.
+// A missing before_id places the task at the top of the column and a missing after_id at the bottom, so when only one of them
+// is received it has to be the first or the last task of the column
+func (t *taskService) neighbourRanks(ctx context.Context, userID string, entity *entities.Task, move models.MoveTaskReq) (lower, upper string, err error) {
+	if move.BeforeID != "" {
+		lower, err = t.neighbourRank(ctx, userID, entity, move.BeforeID, move.Status)
+		if err != nil {
+			return
+		}
+	}
+	if move.AfterID != "" {
+		upper, err = t.neighbourRank(ctx, userID, entity, move.AfterID, move.Status)
+		if err != nil {
+			return
+		}
+	}
+
+	switch {
+	case move.BeforeID == "" && move.AfterID == "":
+		lower, err = t.repository.LastRank(ctx, entity.UserID, move.Status)
+	case move.AfterID == "":
+		err = t.checkColumnEdge(ctx, entity, move, lower, t.repository.LastRank, "last")
+	case move.BeforeID == "":
+		err = t.checkColumnEdge(ctx, entity, move, upper, t.repository.FirstRank, "first")
+	}
+	return
+}
+
+// checkColumnEdge checks that the neighbour with the received rank is the first or the last task of the target column, depending on the received edge
+func (t *taskService) checkColumnEdge(ctx context.Context, entity *entities.Task, move models.MoveTaskReq, rank string, edgeRank func(ctx context.Context, userID, status string) (string, error), edge string) error {
+	current, err := edgeRank(ctx, entity.UserID, move.Status)
+	if err != nil {
+		return err
+	}
+	if current != rank && (current != entity.Rank || move.Status != entity.Status) {
+		return wrappers.NewValidationErr(fmt.Errorf("both before_id and after_id are required, unless the neighbour is the %s task of the %s column", edge, move.Status))
+	}
+	return nil
+}
+
+// neighbourRank gets the rank of a neighbour of a moved task, checking that it is another task of the same owner in the received column
+// that the received userID can see
+func (t *taskService) neighbourRank(ctx context.Context, userID string, entity *entities.Task, neighbourID, status string) (string, error) {
+	if neighbourID == entity.ID {
+		return "", wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be moved next to itself", entity.ID))
+	}
+
+	neighbour, err := t.getSharedTask(ctx, userID, neighbourID, entities.TaskRoleViewer, "move a task next to")
+	if err != nil {
+		return "", err
+	}
+	if neighbour.UserID != entity.UserID || neighbour.Status != status {
+		return "", wrappers.NewValidationErr(fmt.Errorf("TaskID %s is not in the %s column of the board of TaskID %s", neighbourID, status, entity.ID))
+	}
+	if neighbour.Rank == "" {
+		return "", wrappers.NewValidationErr(fmt.Errorf("TaskID %s has no rank yet", neighbourID))
+	}
+	return neighbour.Rank, nil
+}
+
+// nextRank gets a rank placing a task at the bottom of a board column of the received userID
+func (t *taskService) nextRank(ctx context.Context, userID, status string) (string, error) {
+	last, err := t.repository.LastRank(ctx, userID, status)
+	if err != nil {
+		return "", err
+	}
+	return rankBetween(last, "")
+}
+
+// rankBetween gets a rank sorting strictly between the received ranks, where an empty lower rank stands for the top
+// and an empty upper rank for the bottom of the column. Ranks at the edges of a column grow logarithmically with the number of tasks
+// placed there, while ranks in between grow by a digit every few moves into the same gap, which the rebalance eventually shortens
+func rankBetween(lower, upper string) (string, error) {
+	if upper != "" && lower >= upper {
+		return "", fmt.Errorf("rank %s is not lower than rank %s", lower, upper)
+	}
+	if strings.HasSuffix(lower, rankDigits[:1]) || strings.HasSuffix(upper, rankDigits[:1]) {
+		return "", fmt.Errorf("ranks cannot end with %s", rankDigits[:1])
+	}
+
+	switch {
+	case lower != "" && upper == "":
+		return rankAfter(lower), nil
+	case lower == "" && upper != "":
+		return rankBefore(upper), nil
+	}
+	return rankMidpoint(lower, upper), nil
+}
+
+// rankAfter increments the received rank, doubling its length once all its digits are the last one
+func rankAfter(rank string) string {
+	first, last := rankDigits[0], rankDigits[len(rankDigits)-1]
+	digits := []byte(rank)
+	for {
+		i := len(digits) - 1
+		for i >= 0 && digits[i] == last {
+			digits[i] = first
+			i--
+		}
+		if i < 0 {
+			return rank + strings.Repeat(rankDigits[:1], len(rank)-1) + rankDigits[1:2]
+		}
+		digits[i] = rankDigits[strings.IndexByte(rankDigits, digits[i])+1]
+		if digits[len(digits)-1] != first {
+			return string(digits)
+		}
+	}
+}
+
+// rankBefore decrements the received rank, doubling its length once there is no lower rank of the same length
+func rankBefore(rank string) string {
+	first, last := rankDigits[0], rankDigits[len(rankDigits)-1]
+	digits := []byte(rank)
+	for {
+		i := len(digits) - 1
+		for i >= 0 && digits[i] == first {
+			digits[i] = last
+			i--
+		}
+		digits[i] = rankDigits[strings.IndexByte(rankDigits, digits[i])-1]
+		if strings.Trim(string(digits), rankDigits[:1]) == "" {
+			return strings.Repeat(rankDigits[:1], len(rank)) + strings.Repeat(rankDigits[len(rankDigits)-1:], len(rank))
+		}
+		if digits[len(digits)-1] != first {
+			return string(digits)
+		}
+	}
+}
+
+// rankMidpoint gets a rank halfway between the received ones, padding the lower rank with the first digit
+func rankMidpoint(lower, upper string) string {
+	if upper != "" {
+		n := 0
+		for n < len(upper) && rankDigit(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			return upper[:n] + rankMidpoint(rankSuffix(lower, n), upper[n:])
+		}
+	}
+
+	digitLower := 0
+	if lower != "" {
+		digitLower = strings.IndexByte(rankDigits, lower[0])
+	}
+	digitUpper := len(rankDigits)
+	if upper != "" {
+		digitUpper = strings.IndexByte(rankDigits, upper[0])
+	}
+
+	if digitUpper-digitLower > 1 {
+		return string(rankDigits[(digitLower+digitUpper+1)/2])
+	}
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+	return string(rankDigits[digitLower]) + rankMidpoint(rankSuffix(lower, 1), "")
+}
+
+// rankDigit gets the digit of a rank at the received position, or the first digit when the rank is shorter
+func rankDigit(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+// rankSuffix gets the digits of a rank after the received position
+func rankSuffix(rank string, i int) string {
+	if i < len(rank) {
+		return rank[i:]
+	}
+	return ""
+}
+
+// spreadRanks gets the received number of ranks, evenly spread and as short as possible
+func spreadRanks(n int) []string {
+	width, space := 1, len(rankDigits)
+	for space <= n {
+		width++
+		space *= len(rankDigits)
+	}
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * space / (n + 1)
+		digits := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			digits[j] = rankDigits[value%len(rankDigits)]
+			value /= len(rankDigits)
+		}
+		ranks[i] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return ranks
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRankBetween_Ok checks that rankBetween returns the expected rank for each pair of ranks
+func TestRankBetween_Ok(t *testing.T) {
+	tests := []struct {
+		name     string
+		lower    string
+		upper    string
+		expected string
+	}{
+		{"empty column", "", "", "V"},
+		{"bottom of the column", "V", "", "W"},
+		{"bottom after the last digit", "z", "", "z1"},
+		{"bottom with carry", "Vz", "", "W1"},
+		{"top of the column", "", "V", "U"},
+		{"top before the first digit", "", "1", "0z"},
+		{"between distant ranks", "V", "X", "W"},
+		{"between adjacent ranks", "V", "W", "VV"},
+		{"between a rank and its extension", "a", "a1", "a0V"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := rankBetween(tt.lower, tt.upper)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, rank)
+			assert.Greater(t, rank, tt.lower)
+			if tt.upper != "" {
+				assert.Less(t, rank, tt.upper)
+			}
+		})
+	}
+}
+
+// TestRankBetween_InvalidRanks checks that rankBetween returns an error when the ranks are not in order or end with the first digit
+func TestRankBetween_InvalidRanks(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower string
+		upper string
+	}{
+		{"equal ranks", "V", "V"},
+		{"reversed ranks", "W", "V"},
+		{"trailing first digit", "V0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rankBetween(tt.lower, tt.upper)
+
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+// TestRankBetween_Appends checks that the ranks of the tasks appended to a column grow logarithmically
+func TestRankBetween_Appends(t *testing.T) {
+	// Arrange
+	rank := ""
+
+	// Act
+	for i := 0; i < 10000; i++ {
+		next, err := rankBetween(rank, "")
+		assert.Nil(t, err)
+		assert.Greater(t, next, rank)
+		rank = next
+	}
+
+	// Assert
+	assert.LessOrEqual(t, len(rank), 8)
+}
+
+// TestRankBetween_Inserts checks that inserting repeatedly into the same gap keeps the ranks sorted
+func TestRankBetween_Inserts(t *testing.T) {
+	// Arrange
+	ranks := []string{"V", "W"}
+
+	// Act
+	for i := 0; i < 100; i++ {
+		rank, err := rankBetween(ranks[0], ranks[1])
+		assert.Nil(t, err)
+		ranks = append([]string{ranks[0], rank}, ranks[1:]...)
+	}
+
+	// Assert
+	assert.True(t, sort.StringsAreSorted(ranks))
+	for i := 1; i < len(ranks); i++ {
+		assert.NotEqual(t, ranks[i-1], ranks[i])
+		assert.False(t, strings.HasSuffix(ranks[i], "0"))
+	}
+}
+
+// TestSpreadRanks_Ok checks that spreadRanks returns short, sorted and distinct ranks
+func TestSpreadRanks_Ok(t *testing.T) {
+	for _, n := range []int{1, 61, 62, 5000} {
+		// Act
+		ranks := spreadRanks(n)
+
+		// Assert
+		assert.Len(t, ranks, n)
+		assert.True(t, sort.StringsAreSorted(ranks))
+		for i, rank := range ranks {
+			assert.LessOrEqual(t, len(rank), 3)
+			assert.False(t, strings.HasSuffix(rank, "0"))
+			if i > 0 {
+				assert.NotEqual(t, ranks[i-1], rank)
+			}
+		}
+	}
+}
+
+// TestMove_Ok checks that Move places the task between its new neighbours without changing its status
+func TestMove_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusTodo, Rank: "a"}
+	before := entities.Task{ID: "before-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "V"}
+	after := entities.Task{ID: "after-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "W"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, before.ID).Return(&before, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, after.ID).Return(&after, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Rank == "VV" && e.Status == entities.TaskStatusTodo
+	}), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountRank), mock.Anything, task.UserID, entities.TaskStatusTodo, "VV").Return(1, nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{BeforeID: before.ID, AfterID: after.ID})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, newVersion)
+}
+
+// TestMove_RankCollision checks that Move rebalances the column when a concurrent move has placed another task at the same rank,
+// returning the version of the task after the rebalance
+func TestMove_RankCollision(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusTodo, Rank: "a"}
+	before := entities.Task{ID: "before-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "V"}
+	after := entities.Task{ID: "after-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "W"}
+	concurrent := entities.Task{ID: "concurrent-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "VV", Version: 1}
+	moved := entities.Task{ID: task.ID, UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "VV", Version: 1}
+	column := []interface{}{&before, &concurrent, &moved, &after}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, before.ID).Return(&before, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, after.ID).Return(&after, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Rank == "VV"
+	}), 0).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountRank), mock.Anything, task.UserID, entities.TaskStatusTodo, "VV").Return(2, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindColumn), mock.Anything, task.UserID, entities.TaskStatusTodo).Return(column, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&entities.Task{ID: task.ID, UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "k", Version: 2}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountRank), mock.Anything, task.UserID, entities.TaskStatusTodo, "k").Return(1, nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{BeforeID: before.ID, AfterID: after.ID})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, newVersion)
+}
+
+// TestMove_OtherColumn checks that Move transitions the task to the status of the target column, placing it at the bottom when no neighbours are received
+func TestMove_OtherColumn(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusTodo, Rank: "a"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, task.UserID, entities.TaskStatusInProgress).Return("k", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Rank == "l" && e.Status == entities.TaskStatusInProgress
+	}), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountRank), mock.Anything, task.UserID, entities.TaskStatusInProgress, "l").Return(1, nil).Once()
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskHistory) bool {
+		return len(e.Changes) == 2 && e.Changes[0].Field == "rank" && e.Changes[1].Field == "status"
+	})).Return("history-id", nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: historyRepositoryMock,
+		writer:            NewTaskWriter(taskRepositoryMock, historyRepositoryMock, newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{Status: entities.TaskStatusInProgress})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestMove_NotAllowedTransition checks that Move returns a validation error when the task cannot transition to the status of the target column
+func TestMove_NotAllowedTransition(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusDone, Rank: "a"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, task.UserID, entities.TaskStatusBlocked).Return("", nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{Status: entities.TaskStatusBlocked})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestMove_NeighbourInOtherColumn checks that Move returns a validation error when a neighbour is not in the target column
+func TestMove_NeighbourInOtherColumn(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusTodo, Rank: "a"}
+	before := entities.Task{ID: "before-id", UserID: task.UserID, Status: entities.TaskStatusDone, Rank: "V"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, before.ID).Return(&before, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{BeforeID: before.ID})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Contains(t, err.Error(), "is not in the todo column")
+}
+
+// TestMove_MissingNeighbour checks that Move returns a validation error when only before_id is received and it is not the last task of the column
+func TestMove_MissingNeighbour(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Status: entities.TaskStatusTodo, Rank: "a"}
+	before := entities.Task{ID: "before-id", UserID: task.UserID, Status: entities.TaskStatusTodo, Rank: "V"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, before.ID).Return(&before, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, task.UserID, entities.TaskStatusTodo).Return("k", nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Move(context.Background(), task.UserID, task.ID, 0, models.MoveTaskReq{BeforeID: before.ID})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Contains(t, err.Error(), "both before_id and after_id are required")
+}
+
+// TestRebalanceRanks_Ok checks that RebalanceRanks spreads the ranks of the columns found, only updating the tasks whose rank changes
+func TestRebalanceRanks_Ok(t *testing.T) {
+	// Arrange
+	column := models.TaskColumn{UserID: "user-123", Status: entities.TaskStatusTodo}
+	tasks := []interface{}{
+		&entities.Task{ID: "task-1", Rank: ""},
+		&entities.Task{ID: "task-2", Rank: "V"},
+		&entities.Task{ID: "task-3", Rank: "VVVVVVVVVVVVVVVVVV1"},
+	}
+	expectedRanks := map[string]string{"task-1": "F", "task-3": "k"}
+
+	cfg := config.Config{}
+	cfg.Ranks = config.Ranks{MaxLength: 16, BatchSize: 100}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindRankColumns), mock.Anything, cfg.Ranks.MaxLength, cfg.Ranks.BatchSize).Return([]models.TaskColumn{column}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindColumn), mock.Anything, column.UserID, column.Status).Return(tasks, nil).Once()
+	for id, rank := range expectedRanks {
+		taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, id, mock.MatchedBy(func(e entities.Task) bool {
+			return e.Rank == rank
+		}), 0).Return(nil).Once()
+	}
+
+	service := &taskService{
+		config:            cfg,
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	rebalanced, err := service.RebalanceRanks(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rebalanced)
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	recurrenceDaily   = "DAILY"
+	recurrenceWeekly  = "WEEKLY"
+	recurrenceMonthly = "MONTHLY"
+	recurrenceYearly  = "YEARLY"
+)
+
+// maxRecurrenceIterations bounds the periods checked when looking for the next occurrence,
+// enough to skip the months or years where the requested day does not exist
+const maxRecurrenceIterations = 48
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// recurrenceRule is a parsed RFC 5545 RRULE, supporting the FREQ, INTERVAL, BYDAY (weekly), BYMONTHDAY (monthly) and UNTIL parts
+type recurrenceRule struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	byMonthDay []int
+	until      *time.Time
+}
+
+// parseRecurrence parses a recurrence rule such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH
+func parseRecurrence(rule string) (recurrenceRule, error) {
+	r := recurrenceRule{interval: 1}
+	invalid := func(format string, args ...interface{}) (recurrenceRule, error) {
+		return r, wrappers.NewValidationErr(fmt.Errorf("recurrence %s not valid: %s", rule, fmt.Sprintf(format, args...)))
+	}
+
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return invalid("part %s must be formatted as KEY=VALUE", part)
+		}
+
+		switch key {
+		case "FREQ":
+			if !slices.Contains([]string{recurrenceDaily, recurrenceWeekly, recurrenceMonthly, recurrenceYearly}, value) {
+				return invalid("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+			}
+			r.freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return invalid("INTERVAL must be a positive number")
+			}
+			r.interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[day]
+				if !ok {
+					return invalid("BYDAY %s must be one of MO, TU, WE, TH, FR, SA, SU", day)
+				}
+				r.byDay = append(r.byDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return invalid("BYMONTHDAY %s must be between 1 and 31 or between -31 and -1", day)
+				}
+				r.byMonthDay = append(r.byMonthDay, monthDay)
+			}
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				until, err = time.Parse("20060102", value)
+			}
+			if err != nil {
+				return invalid("UNTIL must be formatted as YYYYMMDD or YYYYMMDDTHHMMSSZ")
+			}
+			r.until = &until
+		default:
+			return invalid("part %s not supported, allowed parts: FREQ, INTERVAL, BYDAY, BYMONTHDAY, UNTIL", key)
+		}
+	}
+
+	if r.freq == "" {
+		return invalid("FREQ is required")
+	}
+	if len(r.byDay) > 0 && r.freq != recurrenceWeekly {
+		return invalid("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(r.byMonthDay) > 0 && r.freq != recurrenceMonthly {
+		return invalid("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+
+	slices.SortFunc(r.byDay, func(a, b time.Weekday) int { return daysSinceMonday(a) - daysSinceMonday(b) })
+	return r, nil
+}
+
+// next gets the first occurrence of the rule after the received occurrence, or false when the rule has ended
+func (r recurrenceRule) next(after time.Time) (time.Time, bool) {
+	next, ok := r.nextInPeriods(after)
+	if !ok || (r.until != nil && next.After(*r.until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r recurrenceRule) nextInPeriods(after time.Time) (time.Time, bool) {
+	year, month, day := after.Date()
+	hour, minute, second := after.Clock()
+	date := func(year int, month time.Month, day int) (time.Time, bool) {
+		t := time.Date(year, month, day, hour, minute, second, after.Nanosecond(), after.Location())
+		return t, t.Day() == day
+	}
+
+	switch r.freq {
+	case recurrenceDaily:
+		return after.AddDate(0, 0, r.interval), true
+
+	case recurrenceWeekly:
+		if len(r.byDay) == 0 {
+			return after.AddDate(0, 0, 7*r.interval), true
+		}
+		weekStart := after.AddDate(0, 0, -daysSinceMonday(after.Weekday()))
+		for _, weeks := range []int{0, r.interval} {
+			for _, weekday := range r.byDay {
+				candidate := weekStart.AddDate(0, 0, 7*weeks+daysSinceMonday(weekday))
+				if candidate.After(after) {
+					return candidate, true
+				}
+			}
+		}
+
+	case recurrenceMonthly:
+		for i := 0; i < maxRecurrenceIterations; i++ {
+			if len(r.byMonthDay) == 0 {
+				if candidate, ok := date(year, month+time.Month((i+1)*r.interval), day); ok {
+					return candidate, true
+				}
+				continue
+			}
+
+			periodMonth := month + time.Month(i*r.interval)
+			daysInMonth := time.Date(year, periodMonth+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			var days []int
+			for _, monthDay := range r.byMonthDay {
+				if monthDay < 0 {
+					monthDay = daysInMonth + monthDay + 1
+				}
+				if monthDay >= 1 && monthDay <= daysInMonth {
+					days = append(days, monthDay)
+				}
+			}
+			slices.Sort(days)
+			for _, d := range days {
+				if candidate, _ := date(year, periodMonth, d); candidate.After(after) {
+					return candidate, true
+				}
+			}
+		}
+
+	case recurrenceYearly:
+		for i := 1; i <= maxRecurrenceIterations; i++ {
+			if candidate, ok := date(year+i*r.interval, month, day); ok {
+				return candidate, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// daysSinceMonday counts the days from Monday to the received weekday, as RFC 5545 weeks start on Monday by default
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// MaterializeOccurrences creates the next occurrence of the recurring tasks due within the configured horizon,
+// until all the occurrences within the horizon exist. A task with a recurrence rule that cannot be parsed is logged
+// and left out of the following batches, so that it does not hold back the rest of them. It returns the number of occurrences created
+func (t *taskService) MaterializeOccurrences(ctx context.Context) (created int, err error) {
+	horizon := time.Now().UTC().Add(t.config.Recurrence.Horizon.Duration)
+	var skippedIDs []string
+	for {
+		result, err := t.repository.FindPendingOccurrences(ctx, horizon, skippedIDs, t.config.Recurrence.BatchSize)
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				err = nil
+			}
+			return created, err
+		}
+
+		batchCreated, batchSkipped := 0, 0
+		for _, v := range result {
+			task := *(v.(*entities.Task))
+			ok, err := t.createNextOccurrence(ctx, entities.TaskHistoryActorSystem, task)
+			if errors.Is(err, wrappers.ValidationErr) {
+				observability.Logger().Printf("next occurrence of TaskID %s skipped - error: %s", task.ID, err)
+				skippedIDs = append(skippedIDs, task.ID)
+				batchSkipped++
+				continue
+			}
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				batchCreated++
+			}
+		}
+
+		created += batchCreated
+		if batchCreated == 0 && batchSkipped == 0 {
+			return created, nil
+		}
+	}
+}
+
+// createNextOccurrence creates the occurrence following the received recurring task, shared with the same collaborators, and links it to the task.
+// When the recurrence has ended, the recurrence is removed from the task so that it stops being processed.
+// The changes are recorded as made by the received actor. It returns false when no occurrence has been created
+func (t *taskService) createNextOccurrence(ctx context.Context, actorID string, task entities.Task) (bool, error) {
+	rule, err := parseRecurrence(task.Recurrence)
+	if err != nil {
+		return false, err
+	}
+
+	dueAt, ok := rule.next(*task.DueAt)
+	if !ok {
+		before := task
+		task.Recurrence = ""
+		task.UpdatedAt = time.Now().UTC()
+		return false, t.writer.saveChanges(ctx, actorID, before, &task)
+	}
+
+	occurrence := entities.Task{
+		UserID:      task.UserID,
+		CreatorID:   task.CreatorID,
+		AssigneeID:  task.AssigneeID,
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		Position:    task.Position,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      entities.TaskStatusTodo,
+		Priority:    task.Priority,
+		Labels:      task.Labels,
+		DueAt:       &dueAt,
+		Recurrence:  task.Recurrence,
+		CreatedAt:   time.Now().UTC(),
+		Version:     1,
+	}
+	occurrence.UpdatedAt = occurrence.CreatedAt
+
+	occurrence.Rank, err = t.nextRank(ctx, occurrence.UserID, occurrence.Status)
+	if err != nil {
+		return false, err
+	}
+
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := t.repository.Create(ctx, occurrence)
+		if err != nil {
+			return err
+		}
+
+		err = t.writer.recordHistory(ctx, actorID, occurrence.UserID, entities.TaskHistoryActionCreated, id, diffTasks(entities.Task{}, occurrence))
+		if err != nil {
+			return err
+		}
+
+		linked := task
+		linked.NextOccurrenceID = id
+		err = t.writer.saveTask(ctx, actorID, entities.TaskHistoryActionUpdated, task, &linked)
+		if err != nil {
+			return err
+		}
+		return t.copyCollaborators(ctx, task.ID, id)
+	})
+	// the task has been changed since it was read, such as by a concurrent creation of its next occurrence, so the occurrence is discarded
+	if errors.Is(err, taskWrappers.PreconditionFailedErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// validateRecurrence checks the recurrence rule of a task, which requires a due date to schedule its occurrences from
+func validateRecurrence(recurrence string, dueAt *time.Time) error {
+	if recurrence == "" {
+		return nil
+	}
+	if dueAt == nil {
+		return wrappers.NewValidationErr(errors.New("due_at is required for recurring tasks"))
+	}
+
+	_, err := parseRecurrence(recurrence)
+	return err
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
+	commonMocks "github.com/sergicanet9/go-microservices-demo/common/test/mocks"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestParseRecurrence_Ok checks that parseRecurrence parses all the supported parts of a rule
+func TestParseRecurrence_Ok(t *testing.T) {
+	// Arrange
+	expectedUntil := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	weekly, weeklyErr := parseRecurrence("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,MO;UNTIL=20261231")
+	monthly, monthlyErr := parseRecurrence("FREQ=MONTHLY;BYMONTHDAY=1,-1")
+
+	// Assert
+	assert.Nil(t, weeklyErr)
+	assert.Equal(t, recurrenceRule{freq: recurrenceWeekly, interval: 2, byDay: []time.Weekday{time.Monday, time.Thursday}, until: &expectedUntil}, weekly)
+	assert.Nil(t, monthlyErr)
+	assert.Equal(t, recurrenceRule{freq: recurrenceMonthly, interval: 1, byMonthDay: []int{1, -1}}, monthly)
+}
+
+// TestParseRecurrence_Invalid checks that parseRecurrence returns a validation error for the rules out of the supported subset
+func TestParseRecurrence_Invalid(t *testing.T) {
+	// Arrange
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;UNTIL=tomorrow",
+	}
+
+	for _, rule := range rules {
+		// Act
+		_, err := parseRecurrence(rule)
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.ValidationErr, rule)
+	}
+}
+
+// TestRecurrenceNext_Ok checks that next returns the expected occurrence for each frequency
+func TestRecurrenceNext_Ok(t *testing.T) {
+	// Arrange
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		rule     string
+		after    time.Time
+		expected time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=3", date(2026, time.February, 27), date(2026, time.March, 2)},
+		{"FREQ=WEEKLY", date(2026, time.October, 14), date(2026, time.October, 21)},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", date(2026, time.October, 14), date(2026, time.October, 16)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", date(2026, time.October, 14), date(2026, time.October, 26)},
+		{"FREQ=MONTHLY", date(2026, time.January, 31), date(2026, time.March, 31)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", date(2026, time.January, 31), date(2026, time.February, 28)},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", date(2026, time.October, 1), date(2026, time.October, 15)},
+		{"FREQ=YEARLY", date(2024, time.February, 29), date(2028, time.February, 29)},
+	}
+
+	for _, c := range cases {
+		rule, err := parseRecurrence(c.rule)
+		assert.Nil(t, err)
+
+		// Act
+		next, ok := rule.next(c.after)
+
+		// Assert
+		assert.True(t, ok, c.rule)
+		assert.Equal(t, c.expected, next, c.rule)
+	}
+}
+
+// TestRecurrenceNext_Ended checks that next returns false when the next occurrence is after the end of the rule
+func TestRecurrenceNext_Ended(t *testing.T) {
+	// Arrange
+	rule, err := parseRecurrence("FREQ=DAILY;UNTIL=20261017T120000Z")
+	assert.Nil(t, err)
+
+	// Act
+	_, ok := rule.next(time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.False(t, ok)
+}
+
+// TestCreate_RecurrenceWithoutDueAt checks that Create returns a validation error when a recurring task has no due date
+func TestCreate_RecurrenceWithoutDueAt(t *testing.T) {
+	// Arrange
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		userManagementClient: userManagementClientMock,
+	}
+	expectedError := "due_at is required for recurring tasks"
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", models.CreateTaskReq{Title: "test-title", Recurrence: "FREQ=DAILY"}, "token")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestTransition_DoneRecurring checks that Transition creates and links the next occurrence when a recurring task is completed
+func TestTransition_DoneRecurring(t *testing.T) {
+	// Arrange
+	dueAt := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.UTC)
+	expectedDueAt := dueAt.AddDate(0, 0, 7)
+	task := entities.Task{
+		ID:         "task-id",
+		UserID:     "user-123",
+		Title:      "Take out the trash",
+		Status:     entities.TaskStatusTodo,
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=WEEKLY",
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.AnythingOfType("entities.Task"), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Title == task.Title && e.Status == entities.TaskStatusTodo && e.DueAt.Equal(expectedDueAt) && e.Recurrence == task.Recurrence
+	})).Return("next-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.NextOccurrenceID == "next-id"
+	}), mock.Anything).Return(nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		dependencyRepository:   dependencyRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusDone})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestMaterializeOccurrences_Ok checks that MaterializeOccurrences creates the next occurrence of the pending recurring tasks
+func TestMaterializeOccurrences_Ok(t *testing.T) {
+	// Arrange
+	dueAt := time.Now().UTC()
+	task := entities.Task{
+		ID:         "task-id",
+		UserID:     "user-123",
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=MONTHLY",
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, 10).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, 10).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("next-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.NextOccurrenceID == "next-id"
+	}), mock.Anything).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Recurrence = config.Recurrence{Horizon: utils.Duration{Duration: 7 * 24 * time.Hour}, BatchSize: 10}
+	service := &taskService{
+		config:                 cfg,
+		repository:             taskRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	created, err := service.MaterializeOccurrences(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created)
+}
+
+// TestMaterializeOccurrences_InvalidRecurrence checks that MaterializeOccurrences skips a task whose recurrence cannot be parsed and goes on with the rest of them,
+// leaving it out of the following batches
+func TestMaterializeOccurrences_InvalidRecurrence(t *testing.T) {
+	// Arrange
+	dueAt := time.Now().UTC()
+	invalid := entities.Task{ID: "invalid-id", UserID: "user-123", DueAt: &dueAt, Recurrence: "FREQ=HOURLY"}
+	task := entities.Task{ID: "task-id", UserID: "user-123", DueAt: &dueAt, Recurrence: "FREQ=DAILY"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), []string(nil), mock.Anything).Return([]interface{}{&invalid, &task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), []string{invalid.ID}, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("next-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.NextOccurrenceID == "next-id"
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	created, err := service.MaterializeOccurrences(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created)
+}
+
+// TestMaterializeOccurrences_InvalidRecurrencesFillBatch checks that MaterializeOccurrences goes on with the tasks behind a whole batch of tasks
+// whose recurrence cannot be parsed
+func TestMaterializeOccurrences_InvalidRecurrencesFillBatch(t *testing.T) {
+	// Arrange
+	dueAt := time.Now().UTC()
+	invalid := entities.Task{ID: "invalid-id", UserID: "user-123", DueAt: &dueAt, Recurrence: "FREQ=HOURLY"}
+	task := entities.Task{ID: "task-id", UserID: "user-123", DueAt: &dueAt, Recurrence: "FREQ=DAILY"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), []string(nil), 1).Return([]interface{}{&invalid}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), []string{invalid.ID}, 1).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), []string{invalid.ID}, 1).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("next-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.Anything, mock.Anything).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Recurrence.BatchSize = 1
+	service := &taskService{
+		config:                 cfg,
+		repository:             taskRepositoryMock,
+		historyRepository:      newTaskHistoryRepositoryMock(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	created, err := service.MaterializeOccurrences(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created)
+}
+
+// TestMaterializeOccurrences_AlreadyLinked checks that MaterializeOccurrences discards the created occurrence when the task got changed concurrently, such as by another link
+func TestMaterializeOccurrences_AlreadyLinked(t *testing.T) {
+	// Arrange
+	dueAt := time.Now().UTC()
+	task := entities.Task{
+		ID:         "task-id",
+		UserID:     "user-123",
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=DAILY",
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, mock.Anything).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("next-id", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.AnythingOfType("entities.Task"), task.Version).Return(taskWrappers.NewPreconditionFailedErr(errors.New("already linked"))).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	created, err := service.MaterializeOccurrences(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, created)
+}
+
+// TestMaterializeOccurrences_Ended checks that MaterializeOccurrences removes the recurrence of a task whose rule has ended
+func TestMaterializeOccurrences_Ended(t *testing.T) {
+	// Arrange
+	dueAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	task := entities.Task{
+		ID:         "task-id",
+		UserID:     "user-123",
+		DueAt:      &dueAt,
+		Recurrence: "FREQ=DAILY;UNTIL=20261017",
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.FindPendingOccurrences), mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, mock.Anything).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Recurrence == ""
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	created, err := service.MaterializeOccurrences(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, created)
+}
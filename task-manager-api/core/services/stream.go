@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+)
+
+// taskEventStream adapter of a task event stream, building the events of the tasks changed in a task change stream
+type taskEventStream struct {
+	changes ports.TaskChangeStream
+	service *taskService
+}
+
+// Stream opens a stream of the changes to the tasks owned by the received userID, or to all the tasks when empty, resuming right after the received lastEventID when set.
+// Tasks removed permanently from the trash are not streamed
+func (t *taskService) Stream(ctx context.Context, userID, lastEventID string) (ports.TaskEventStream, error) {
+	changes, err := t.repository.Watch(ctx, userID, lastEventID, t.config.Stream.Heartbeat.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &taskEventStream{
+		changes: changes,
+		service: t,
+	}, nil
+}
+
+// Next waits for the next change, returning a nil event when none arrives within the configured heartbeat so that the connection can be kept alive.
+// Changes to tasks in the trash are task.deleted events, and the rest of changes task.updated events unless they create the task
+func (s *taskEventStream) Next(ctx context.Context) (*models.TaskChangeEvent, error) {
+	change, err := s.changes.Next(ctx)
+	if err != nil || change == nil {
+		return nil, err
+	}
+
+	resps, err := s.service.getTaskResps(ctx, []entities.Task{change.Task})
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.TaskChangeEvent{
+		ID:   change.Token,
+		Type: entities.WebhookEventTaskUpdated,
+		Task: resps[0],
+	}
+	switch {
+	case change.Task.DeletedAt != nil:
+		event.Type = entities.WebhookEventTaskDeleted
+	case change.Operation == entities.TaskChangeOperationInsert:
+		event.Type = entities.WebhookEventTaskCreated
+	}
+	return event, nil
+}
+
+// Close closes the underlying change stream
+func (s *taskEventStream) Close(ctx context.Context) error {
+	return s.changes.Close(ctx)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestStream_Ok checks that Stream opens a stream of the changes to the tasks of the user resuming after the received ID,
+// whose events are typed after the change and carry the task with its progress
+func TestStream_Ok(t *testing.T) {
+	deletedAt := time.Now().UTC()
+	tests := []struct {
+		name         string
+		change       *entities.TaskChange
+		expectedType string
+	}{
+		{
+			name:         "created",
+			change:       &entities.TaskChange{Token: "token-1", Operation: entities.TaskChangeOperationInsert, Task: entities.Task{ID: "task-id"}},
+			expectedType: entities.WebhookEventTaskCreated,
+		},
+		{
+			name:         "updated",
+			change:       &entities.TaskChange{Token: "token-1", Operation: entities.TaskChangeOperationUpdate, Task: entities.Task{ID: "task-id"}},
+			expectedType: entities.WebhookEventTaskUpdated,
+		},
+		{
+			name:         "deleted",
+			change:       &entities.TaskChange{Token: "token-1", Operation: entities.TaskChangeOperationUpdate, Task: entities.Task{ID: "task-id", DeletedAt: &deletedAt}},
+			expectedType: entities.WebhookEventTaskDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := config.Config{}
+			cfg.Stream.Heartbeat.Duration = time.Second
+
+			changeStreamMock := mocks.NewTaskChangeStream(t)
+			changeStreamMock.On(testutils.FunctionName(t, ports.TaskChangeStream.Next), mock.Anything).Return(tt.change, nil).Once()
+
+			taskRepositoryMock := mocks.NewTaskRepository(t)
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Watch), mock.Anything, "user-123", "token-0", time.Second).Return(changeStreamMock, nil).Once()
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, []string{"task-id"}).Return(map[string]models.SubtaskCount{"task-id": {Total: 2, Done: 1}}, nil).Once()
+
+			service := &taskService{
+				config:     cfg,
+				repository: taskRepositoryMock,
+			}
+
+			// Act
+			stream, err := service.Stream(context.Background(), "user-123", "token-0")
+			assert.Nil(t, err)
+			event, err := stream.Next(context.Background())
+
+			// Assert
+			assert.Nil(t, err)
+			assert.Equal(t, "token-1", event.ID)
+			assert.Equal(t, tt.expectedType, event.Type)
+			assert.Equal(t, "task-id", event.Task.ID)
+			assert.Equal(t, 50, *event.Task.Progress)
+		})
+	}
+}
+
+// TestStream_NoChanges checks that the stream opened by Stream returns no event when no change arrives in time
+func TestStream_NoChanges(t *testing.T) {
+	// Arrange
+	changeStreamMock := mocks.NewTaskChangeStream(t)
+	changeStreamMock.On(testutils.FunctionName(t, ports.TaskChangeStream.Next), mock.Anything).Return(nil, nil).Once()
+	changeStreamMock.On(testutils.FunctionName(t, ports.TaskChangeStream.Close), mock.Anything).Return(nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Watch), mock.Anything, "user-123", "", mock.Anything).Return(changeStreamMock, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	stream, err := service.Stream(context.Background(), "user-123", "")
+	assert.Nil(t, err)
+	event, err := stream.Next(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, event)
+	assert.Nil(t, stream.Close(context.Background()))
+}
+
+// TestStream_RepositoryError checks that Stream returns an error when the changes cannot be watched
+func TestStream_RepositoryError(t *testing.T) {
+	// Arrange
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Watch), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("watch-error")).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	stream, err := service.Stream(context.Background(), "user-123", "token-0")
+
+	// Assert
+	assert.Nil(t, stream)
+	assert.Equal(t, "watch-error", err.Error())
+}
@@ -2,8 +2,12 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	commonPorts "github.com/sergicanet9/go-microservices-demo/common/clients/ports"
@@ -11,41 +15,109 @@ import (
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 )
 
+// taskStatusTransitions defines the allowed target statuses for each task status
+var taskStatusTransitions = map[string][]string{
+	entities.TaskStatusTodo:       {entities.TaskStatusInProgress, entities.TaskStatusBlocked, entities.TaskStatusDone, entities.TaskStatusArchived},
+	entities.TaskStatusInProgress: {entities.TaskStatusTodo, entities.TaskStatusBlocked, entities.TaskStatusDone, entities.TaskStatusArchived},
+	entities.TaskStatusBlocked:    {entities.TaskStatusTodo, entities.TaskStatusInProgress, entities.TaskStatusArchived},
+	entities.TaskStatusDone:       {entities.TaskStatusInProgress, entities.TaskStatusArchived},
+	entities.TaskStatusArchived:   {},
+}
+
+// taskPriorities are the allowed task priorities
+var taskPriorities = []string{entities.TaskPriorityLow, entities.TaskPriorityMedium, entities.TaskPriorityHigh}
+
+// taskSortFields are the fields the tasks can be sorted by, prefixed with "-" for descending order
+var taskSortFields = []string{"created_at", "due_at", "title", "rank"}
+
+const defaultTaskSort = "due_at"
+
+const (
+	labelMatchAny = "any"
+	labelMatchAll = "all"
+)
+
+const (
+	taskScopeOwn    = "own"
+	taskScopeShared = "shared"
+)
+
+// openTaskStatuses are the statuses of the tasks with pending work
+var openTaskStatuses = []string{entities.TaskStatusTodo, entities.TaskStatusInProgress, entities.TaskStatusBlocked}
+
 // taskService adapter of an task service
 type taskService struct {
-	config               config.Config
-	repository           ports.TaskRepository
-	userManagementClient commonPorts.UserManagementV1GRPCClient
+	config                 config.Config
+	repository             ports.TaskRepository
+	labelRepository        ports.LabelRepository
+	projectRepository      ports.ProjectRepository
+	dependencyRepository   ports.TaskDependencyRepository
+	historyRepository      ports.TaskHistoryRepository
+	commentRepository      ports.CommentRepository
+	attachmentRepository   ports.AttachmentRepository
+	blobStore              ports.BlobStore
+	collaboratorRepository ports.TaskCollaboratorRepository
+	writer                 *TaskWriter
+	transactions           ports.TransactionManager
+	userManagementClient   commonPorts.UserManagementV1GRPCClient
 }
 
 // NewTaskService creates a new task service
-func NewTaskService(cfg config.Config, repo ports.TaskRepository, userManagementClient commonPorts.UserManagementV1GRPCClient) ports.TaskService {
+func NewTaskService(cfg config.Config, repo ports.TaskRepository, labelRepo ports.LabelRepository, projectRepo ports.ProjectRepository, dependencyRepo ports.TaskDependencyRepository, historyRepo ports.TaskHistoryRepository, commentRepo ports.CommentRepository, attachmentRepo ports.AttachmentRepository, blobStore ports.BlobStore, collaboratorRepo ports.TaskCollaboratorRepository, writer *TaskWriter, transactions ports.TransactionManager, userManagementClient commonPorts.UserManagementV1GRPCClient) ports.TaskService {
 	return &taskService{
-		config:               cfg,
-		repository:           repo,
-		userManagementClient: userManagementClient,
+		config:                 cfg,
+		repository:             repo,
+		labelRepository:        labelRepo,
+		projectRepository:      projectRepo,
+		dependencyRepository:   dependencyRepo,
+		historyRepository:      historyRepo,
+		commentRepository:      commentRepo,
+		attachmentRepository:   attachmentRepo,
+		blobStore:              blobStore,
+		collaboratorRepository: collaboratorRepo,
+		writer:                 writer,
+		transactions:           transactions,
+		userManagementClient:   userManagementClient,
 	}
 }
 
 // Create task
 func (t *taskService) Create(ctx context.Context, userID string, task models.CreateTaskReq, token string) (resp models.CreateTaskResp, err error) {
+	return t.create(ctx, userID, userID, task, token)
+}
 
-	exists, err := t.userManagementClient.Exists(ctx, token, userID)
+// create creates a task owned by the received ownerID on behalf of the received actor, who may be a collaborator of the owner
+func (t *taskService) create(ctx context.Context, actorID, ownerID string, task models.CreateTaskReq, token string) (resp models.CreateTaskResp, err error) {
+	exists, err := t.userManagementClient.Exists(ctx, token, actorID)
 	if err != nil {
 		return
 	}
 	if !exists {
-		return resp, wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", userID))
+		return resp, wrappers.NewNonExistentErr(fmt.Errorf("UserID %s not found", actorID))
 	}
 
-	entity := entities.Task(task)
-	entity.UserID = userID
-	entity.CreatedAt = time.Now().UTC()
+	entity, err := t.newTask(ctx, actorID, ownerID, task)
+	if err != nil {
+		return
+	}
 
-	id, err := t.repository.Create(ctx, entity)
+	entity.Rank, err = t.nextRank(ctx, ownerID, entity.Status)
+	if err != nil {
+		return
+	}
+
+	var id string
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		id, err = t.repository.Create(ctx, entity)
+		if err != nil {
+			return
+		}
+		return t.writer.recordHistory(ctx, actorID, ownerID, entities.TaskHistoryActionCreated, id, diffTasks(entities.Task{}, entity))
+	})
 	if err != nil {
 		return
 	}
@@ -57,11 +129,127 @@ func (t *taskService) Create(ctx context.Context, userID string, task models.Cre
 	return
 }
 
-// GetByUserID tasks
-func (t *taskService) GetByUserID(ctx context.Context, userID string) (resp []models.GetTaskResp, err error) {
-	filter := map[string]interface{}{"user_id": userID}
+// newTask validates the received task and builds the entity of a new task owned by the received ownerID on behalf of the received actor
+func (t *taskService) newTask(ctx context.Context, actorID, ownerID string, task models.CreateTaskReq) (entity entities.Task, err error) {
+	task.Priority, err = validatePriority(task.Priority)
+	if err != nil {
+		return
+	}
+
+	task.Labels, err = t.validateLabels(ctx, ownerID, task.Labels)
+	if err != nil {
+		return
+	}
+
+	err = t.validateProject(ctx, ownerID, task.ProjectID)
+	if err != nil {
+		return
+	}
+
+	err = validateRecurrence(task.Recurrence, task.DueAt)
+	if err != nil {
+		return
+	}
+
+	entity = entities.Task(task)
+	entity.UserID = ownerID
+	entity.CreatorID = actorID
+	entity.Status = entities.TaskStatusTodo
+	entity.CreatedAt = time.Now().UTC()
+	entity.UpdatedAt = entity.CreatedAt
+	entity.Version = 1
+	return
+}
+
+// CreateSubtask creates a task nested under the received parent task, placed after its current subtasks.
+// The subtask belongs to the owner and to the project of the parent task, even when it is created by a collaborator
+func (t *taskService) CreateSubtask(ctx context.Context, userID, parentID string, task models.CreateTaskReq, token string) (resp models.CreateTaskResp, err error) {
+	parent, err := t.getSharedTask(ctx, userID, parentID, entities.TaskRoleEditor, "add subtasks to")
+	if err != nil {
+		return
+	}
+
+	depth, err := t.taskDepth(ctx, parent)
+	if err != nil {
+		return
+	}
+	if depth >= t.config.Subtasks.MaxDepth {
+		return resp, wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot have subtasks, the maximum subtask depth is %d", parentID, t.config.Subtasks.MaxDepth))
+	}
+
+	siblings, err := t.getSubtasks(ctx, []string{parentID})
+	if err != nil {
+		return
+	}
+
+	task.ParentID = parentID
+	task.ProjectID = parent.ProjectID
+	task.Position = len(siblings)
+	return t.create(ctx, userID, parent.UserID, task, token)
+}
 
-	result, err := t.repository.Get(ctx, filter, nil, nil)
+// GetByUserID gets a page of the tasks owned by the received userID, or shared with it when the shared scope is requested, sorted by the requested field
+func (t *taskService) GetByUserID(ctx context.Context, userID string, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	resp.Tasks = []models.GetTaskResp{}
+	filter, err := newTaskFilter(userID, req)
+	if err != nil {
+		return
+	}
+
+	if req.ProjectID != "" {
+		_, err = getOwnedProject(ctx, t.projectRepository, userID, req.ProjectID, "get the tasks of")
+		if err != nil {
+			return
+		}
+		filter.ProjectID = req.ProjectID
+	}
+
+	if req.Scope == taskScopeShared {
+		filter.UserID = ""
+		filter.IDs, err = t.getSharedTaskIDs(ctx, userID)
+		if err != nil || len(filter.IDs) == 0 {
+			return
+		}
+	}
+
+	return t.findSortedPage(ctx, filter, req)
+}
+
+// GetAssigned gets a page of the tasks assigned to the received userID, whoever owns them, sorted by the requested field
+func (t *taskService) GetAssigned(ctx context.Context, userID string, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	resp.Tasks = []models.GetTaskResp{}
+	filter, err := newTaskFilter(userID, req)
+	if err != nil {
+		return
+	}
+	filter.UserID = ""
+	filter.AssigneeID = userID
+
+	return t.findSortedPage(ctx, filter, req)
+}
+
+// Search gets the tasks of the received userID matching a text query, sorted by relevance and with the matched fragments highlighted
+func (t *taskService) Search(ctx context.Context, userID string, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	resp.Tasks = []models.GetTaskResp{}
+
+	terms := searchTerms(req.Query)
+	if len(terms) == 0 {
+		return resp, wrappers.NewValidationErr(errors.New("search query cannot be empty"))
+	}
+
+	filter, err := newTaskFilter(userID, req)
+	if err != nil {
+		return
+	}
+	if req.Scope == taskScopeShared {
+		return resp, wrappers.NewValidationErr(fmt.Errorf("scope %s not supported when searching", taskScopeShared))
+	}
+	filter.Limit, err = pageLimit(t.config.Pagination, req.Limit)
+	if err != nil {
+		return
+	}
+
+	result, err := t.repository.Search(ctx, filter, req.Query)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
 			err = nil
@@ -69,29 +257,865 @@ func (t *taskService) GetByUserID(ctx context.Context, userID string) (resp []mo
 		return
 	}
 
-	resp = make([]models.GetTaskResp, len(result))
+	tasks := make([]entities.Task, len(result))
+	resp.Matches = make([]models.TaskMatch, len(result))
 	for i, v := range result {
-		resp[i] = models.GetTaskResp(*(v.(*entities.Task)))
+		match := v.(*entities.TaskSearchResult)
+		tasks[i] = match.Task
+		resp.Matches[i] = models.TaskMatch{
+			ID:         match.ID,
+			Score:      match.Score,
+			Highlights: highlightTask(match.Task, terms),
+		}
 	}
 
+	resp.Tasks, err = t.getTaskResps(ctx, tasks)
 	return
 }
 
-// Delete task
-func (t *taskService) Delete(ctx context.Context, userID string, taskID string) (err error) {
-	result, err := t.repository.GetByID(ctx, taskID)
+// GetSubtasks gets the direct subtasks of a task, sorted by their position
+func (t *taskService) GetSubtasks(ctx context.Context, userID, taskID string) (resp []models.GetTaskResp, err error) {
+	_, err = t.getSharedTask(ctx, userID, taskID, entities.TaskRoleViewer, "get subtasks of")
 	if err != nil {
-		if errors.Is(err, wrappers.NonExistentErr) {
-			err = wrappers.NewNonExistentErr(fmt.Errorf("TaskID %s not found", taskID))
+		return
+	}
+
+	subtasks, err := t.getSubtasks(ctx, []string{taskID})
+	if err != nil {
+		return
+	}
+
+	return t.getTaskResps(ctx, subtasks)
+}
+
+// GetTrash gets a page of the tasks of the received userID that are in the trash, the most recently deleted first
+func (t *taskService) GetTrash(ctx context.Context, userID string, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	filter := models.TaskFilter{
+		UserID:   userID,
+		Deleted:  true,
+		SortBy:   "deleted_at",
+		SortDesc: true,
+	}
+	return t.findPage(ctx, filter, "-deleted_at", req)
+}
+
+// GetStats counts the tasks of the received userID by status
+func (t *taskService) GetStats(ctx context.Context, userID string) (resp models.GetTaskStatsResp, err error) {
+	counts, err := t.repository.CountByStatus(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	resp.ByStatus = make(map[string]int, len(taskStatusTransitions))
+	for status := range taskStatusTransitions {
+		resp.ByStatus[status] = counts[status]
+		resp.Total += counts[status]
+	}
+	for _, status := range openTaskStatuses {
+		resp.Open += counts[status]
+	}
+	resp.Done = counts[entities.TaskStatusDone]
+
+	return
+}
+
+// Get gets a task the received userID can see
+func (t *taskService) Get(ctx context.Context, userID, taskID string) (resp models.GetTaskResp, err error) {
+	entity, err := t.getSharedTask(ctx, userID, taskID, entities.TaskRoleViewer, "get")
+	if err != nil {
+		return
+	}
+
+	resps, err := t.getTaskResps(ctx, []entities.Task{*entity})
+	if err != nil {
+		return
+	}
+
+	resp = resps[0]
+	return
+}
+
+// Update task. It returns the new version of the task
+func (t *taskService) Update(ctx context.Context, userID, taskID string, version int, task models.UpdateTaskReq) (newVersion int, err error) {
+	entity, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
+		return
+	}
+
+	err = t.update(ctx, userID, entity, task)
+	return entity.Version, err
+}
+
+// Patch task. It returns the new version of the task
+func (t *taskService) Patch(ctx context.Context, userID, taskID string, version int, patch models.PatchTaskReq) (newVersion int, err error) {
+	entity, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
+		return
+	}
+
+	task, err := mergePatch(models.UpdateTaskReq{
+		ProjectID:   entity.ProjectID,
+		Title:       entity.Title,
+		Description: entity.Description,
+		Priority:    entity.Priority,
+		Labels:      entity.Labels,
+		DueAt:       entity.DueAt,
+		Recurrence:  entity.Recurrence,
+	}, patch)
+	if err != nil {
+		return
+	}
+
+	err = t.update(ctx, userID, entity, task)
+	return entity.Version, err
+}
+
+// Transition moves a task to a new status, as long as the transition is allowed from its current status.
+// The task is placed at the bottom of the board column of its new status. It returns the new version of the task
+func (t *taskService) Transition(ctx context.Context, userID, taskID string, version int, transition models.TransitionTaskReq) (newVersion int, err error) {
+	entity, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
+		return
+	}
+
+	before := *entity
+	err = t.applyTransition(ctx, entity, transition.Status)
+	if err != nil {
+		return
+	}
+
+	entity.Rank, err = t.nextRank(ctx, entity.UserID, entity.Status)
+	if err != nil {
+		return
+	}
+
+	err = t.saveTransition(ctx, userID, before, entity)
+	return entity.Version, err
+}
+
+// applyTransition changes the status of the received entity, as long as the transition is allowed from its current status
+func (t *taskService) applyTransition(ctx context.Context, entity *entities.Task, status string) error {
+	if _, ok := taskStatusTransitions[status]; !ok {
+		return wrappers.NewValidationErr(fmt.Errorf("status %s not valid", status))
+	}
+	if !slices.Contains(taskStatusTransitions[entity.Status], status) {
+		return wrappers.NewValidationErr(fmt.Errorf("transition from status %s to %s not allowed for TaskID %s", entity.Status, status, entity.ID))
+	}
+
+	if status == entities.TaskStatusDone {
+		err := t.checkBlockers(ctx, entity.ID)
+		if err != nil {
+			return err
 		}
+	}
+
+	now := time.Now().UTC()
+	switch status {
+	case entities.TaskStatusDone:
+		entity.CompletedAt = &now
+	case entities.TaskStatusArchived:
+	default:
+		entity.CompletedAt = nil
+	}
+	entity.Status = status
+	entity.UpdatedAt = now
+	return nil
+}
+
+// saveTransition persists a task that may have changed its status, creating the next occurrence of the recurring tasks that have been completed
+func (t *taskService) saveTransition(ctx context.Context, actorID string, before entities.Task, entity *entities.Task) error {
+	err := t.writer.saveChanges(ctx, actorID, before, entity)
+	if err != nil {
+		return err
+	}
+
+	if entity.Status == entities.TaskStatusDone && before.Status != entities.TaskStatusDone && entity.Recurrence != "" && entity.NextOccurrenceID == "" {
+		_, err = t.createNextOccurrence(ctx, actorID, *entity)
+	}
+	return err
+}
+
+// ReorderSubtasks sets the position of the subtasks of a task to the order of the received IDs, incrementing the version of the task
+func (t *taskService) ReorderSubtasks(ctx context.Context, userID, taskID string, version int, reorder models.ReorderSubtasksReq) (newVersion int, err error) {
+	parent, err := t.getVersionedTask(ctx, userID, taskID, version, entities.TaskRoleEditor, "update")
+	if err != nil {
 		return
 	}
-	task := models.GetTaskResp(*result.(*entities.Task))
+
+	subtasks, err := t.getSubtasks(ctx, []string{taskID})
+	if err != nil {
+		return
+	}
+
+	positions := make(map[string]int, len(reorder.IDs))
+	for i, id := range reorder.IDs {
+		positions[id] = i
+	}
+	invalidIDs := len(positions) != len(reorder.IDs) || len(positions) != len(subtasks)
+	var moved []entities.Task
+	for _, subtask := range subtasks {
+		position, ok := positions[subtask.ID]
+		if !ok {
+			invalidIDs = true
+		}
+		if subtask.Position != position {
+			moved = append(moved, subtask)
+		}
+	}
+	if invalidIDs {
+		return newVersion, wrappers.NewValidationErr(fmt.Errorf("ids must contain all the subtasks of TaskID %s exactly once", taskID))
+	}
+
+	now := time.Now().UTC()
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		err := t.writer.saveTasks(ctx, userID, entities.TaskHistoryActionUpdated, moved, func(subtask *entities.Task) {
+			subtask.Position = positions[subtask.ID]
+			subtask.UpdatedAt = now
+		})
+		if err != nil {
+			return err
+		}
+
+		newVersion, err = t.writer.touch(ctx, userID, *parent)
+		return err
+	})
+	return
+}
+
+// Delete moves a task to the trash. A task with subtasks is only deleted, together with all its subtasks, when cascade is set
+func (t *taskService) Delete(ctx context.Context, userID string, taskID string, version int, cascade bool) (err error) {
+	deleted, err := t.getDeletableTasks(ctx, userID, taskID, version, cascade)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	err = t.writer.saveTasks(ctx, userID, entities.TaskHistoryActionDeleted, deleted, func(task *entities.Task) {
+		task.DeletedAt = &now
+		task.UpdatedAt = now
+	})
+	return
+}
+
+// getDeletableTasks checks that the received userID owns a task at the received version and gets the task and its subtasks
+// to move to the trash, which is only allowed for a task with subtasks when cascade is set
+func (t *taskService) getDeletableTasks(ctx context.Context, userID, taskID string, version int, cascade bool) ([]entities.Task, error) {
+	task, err := t.getSharedTask(ctx, userID, taskID, entities.TaskRoleViewer, "delete")
+	if err != nil {
+		return nil, err
+	}
 	if task.UserID != userID {
-		err = wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to delete TaskID %s", userID, taskID))
+		if task.AssigneeID == userID {
+			return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s is the assignee of TaskID %s, only its owner can delete it", userID, taskID))
+		}
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to delete TaskID %s", userID, taskID))
+	}
+	if err := checkVersion(task, version); err != nil {
+		return nil, err
+	}
+
+	descendants, err := getDescendants(ctx, t.repository, taskID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(descendants) > 0 && !cascade {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("TaskID %s has %d subtasks, set cascade to delete them as well", taskID, len(descendants)))
+	}
+	return append([]entities.Task{*task}, descendants...), nil
+}
+
+// Restore takes a task out of the trash, together with the subtasks that were deleted with it. It returns the new version of the task
+func (t *taskService) Restore(ctx context.Context, userID, taskID string, version int) (newVersion int, err error) {
+	task, err := t.getUserTask(ctx, userID, taskID, "restore")
+	if err != nil {
+		return
+	}
+	err = checkVersion(task, version)
+	if err != nil {
+		return
+	}
+	if task.DeletedAt == nil {
+		return newVersion, wrappers.NewValidationErr(fmt.Errorf("TaskID %s is not in the trash", taskID))
+	}
+
+	if task.ParentID != "" {
+		result, err := t.repository.GetByID(ctx, task.ParentID)
+		if errors.Is(err, wrappers.NonExistentErr) {
+			return newVersion, wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be restored as its parent TaskID %s has been permanently deleted", taskID, task.ParentID))
+		}
+		if err != nil {
+			return newVersion, err
+		}
+		if result.(*entities.Task).DeletedAt != nil {
+			return newVersion, wrappers.NewValidationErr(fmt.Errorf("TaskID %s cannot be restored while its parent TaskID %s is in the trash", taskID, task.ParentID))
+		}
+	}
+
+	descendants, err := getDescendants(ctx, t.repository, taskID, task.DeletedAt)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		// the project of the task may have been deleted while it was in the trash, in which case it is restored to the inbox
+		projectDeleted := false
+		if task.ProjectID != "" {
+			_, err := t.projectRepository.GetByID(ctx, task.ProjectID)
+			projectDeleted = errors.Is(err, wrappers.NonExistentErr)
+			if err != nil && !projectDeleted {
+				return err
+			}
+		}
+
+		restore := func(task *entities.Task) {
+			task.DeletedAt = nil
+			task.UpdatedAt = now
+			if projectDeleted {
+				task.ProjectID = ""
+			}
+		}
+
+		restored := *task
+		restore(&restored)
+		err := t.writer.saveTask(ctx, userID, entities.TaskHistoryActionRestored, *task, &restored)
+		if err != nil {
+			return err
+		}
+		newVersion = restored.Version
+
+		return t.writer.saveTasks(ctx, userID, entities.TaskHistoryActionRestored, descendants, restore)
+	})
+	return
+}
+
+// PurgeTrash permanently removes the tasks that have been in the trash for longer than the configured retention,
+// together with their dependencies, collaborators, comments and attachments, in a single transaction. The content of the attachments
+// is only removed from the blob store once committed, as it cannot be rolled back. It returns the number of tasks removed
+func (t *taskService) PurgeTrash(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().UTC().Add(-t.config.Trash.Retention.Duration)
+
+	var ids, blobKeys []string
+	err := t.transactions.WithTransaction(ctx, func(ctx context.Context) (err error) {
+		blobKeys = nil
+		ids, err = t.repository.PurgeDeleted(ctx, deletedBefore)
+		if err != nil || len(ids) == 0 {
+			return
+		}
+
+		err = t.dependencyRepository.DeleteByTaskIDs(ctx, ids)
+		if err != nil {
+			return
+		}
+
+		err = t.collaboratorRepository.DeleteByTaskIDs(ctx, ids)
+		if err != nil {
+			return
+		}
+
+		err = t.commentRepository.DeleteByTaskIDs(ctx, ids)
+		if err != nil {
+			return
+		}
+
+		blobKeys, err = t.deleteAttachments(ctx, ids)
+		return
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, blobKey := range blobKeys {
+		err = t.blobStore.Delete(ctx, blobKey)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return len(ids), err
+		}
+	}
+	return len(ids), nil
+}
+
+// deleteAttachments removes the attachments of the received tasks, returning the keys of their content in the blob store
+func (t *taskService) deleteAttachments(ctx context.Context, taskIDs []string) ([]string, error) {
+	result, err := t.attachmentRepository.FindByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	blobKeys := make([]string, len(result))
+	for i, v := range result {
+		blobKeys[i] = v.(*entities.Attachment).BlobKey
+	}
+	return blobKeys, t.attachmentRepository.DeleteByTaskIDs(ctx, taskIDs)
+}
+
+// getOwnedTask gets a task that is not in the trash and checks that it belongs to the received userID
+func (t *taskService) getOwnedTask(ctx context.Context, userID, taskID, action string) (*entities.Task, error) {
+	return t.getSharedTask(ctx, userID, taskID, entities.TaskRoleOwner, action)
+}
+
+// getVersionedTask gets a task that is not in the trash, checking that the received userID has the received role on it
+// and that it is still at the received version
+func (t *taskService) getVersionedTask(ctx context.Context, userID, taskID string, version int, role, action string) (*entities.Task, error) {
+	task, err := t.getSharedTask(ctx, userID, taskID, role, action)
+	if err != nil {
+		return nil, err
+	}
+	return task, checkVersion(task, version)
+}
+
+// checkVersion checks that a task is still at the version a request was based on, so that concurrent changes are not overwritten
+func checkVersion(task *entities.Task, version int) error {
+	if task.Version != version {
+		return taskWrappers.NewPreconditionFailedErr(fmt.Errorf("TaskID %s is at version %d, not at version %d", task.ID, task.Version, version))
+	}
+	return nil
+}
+
+// getUserTask gets a task, even when it is in the trash, and checks that it belongs to the received userID
+func (t *taskService) getUserTask(ctx context.Context, userID, taskID, action string) (*entities.Task, error) {
+	return getTaskInTrashWithRole(ctx, t.repository, t.collaboratorRepository, userID, taskID, entities.TaskRoleOwner, action)
+}
+
+// getSubtasks gets the direct subtasks of the received parent tasks that are not in the trash, sorted by their position
+func (t *taskService) getSubtasks(ctx context.Context, parentIDs []string) ([]entities.Task, error) {
+	return getSubtasksDeletedAt(ctx, t.repository, parentIDs, nil)
+}
+
+// getSubtasksDeletedAt gets the direct subtasks of the received parent tasks moved to the trash at the received time,
+// or the ones that are not in the trash when deletedAt is nil, sorted by their position
+func getSubtasksDeletedAt(ctx context.Context, taskRepository ports.TaskRepository, parentIDs []string, deletedAt *time.Time) ([]entities.Task, error) {
+	var deleted interface{}
+	if deletedAt != nil {
+		deleted = *deletedAt
+	}
+
+	result, err := taskRepository.Get(ctx, map[string]interface{}{"parent_id": map[string]interface{}{"$in": parentIDs}, "deleted_at": deleted}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	subtasks := make([]entities.Task, len(result))
+	for i, v := range result {
+		subtasks[i] = *(v.(*entities.Task))
+	}
+	slices.SortStableFunc(subtasks, func(a, b entities.Task) int {
+		return a.Position - b.Position
+	})
+	return subtasks, nil
+}
+
+// getDescendants gets all the subtasks under a task, at any depth, moved to the trash at the received time,
+// or the ones that are not in the trash when deletedAt is nil
+func getDescendants(ctx context.Context, taskRepository ports.TaskRepository, taskID string, deletedAt *time.Time) ([]entities.Task, error) {
+	var descendants []entities.Task
+	parentIDs := []string{taskID}
+	for len(parentIDs) > 0 {
+		subtasks, err := getSubtasksDeletedAt(ctx, taskRepository, parentIDs, deletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		parentIDs = nil
+		for _, subtask := range subtasks {
+			parentIDs = append(parentIDs, subtask.ID)
+		}
+		descendants = append(descendants, subtasks...)
+	}
+	return descendants, nil
+}
+
+// taskDepth counts the ancestors of the received task
+func (t *taskService) taskDepth(ctx context.Context, task *entities.Task) (int, error) {
+	depth := 0
+	for task.ParentID != "" && depth <= t.config.Subtasks.MaxDepth {
+		result, err := t.repository.GetByID(ctx, task.ParentID)
+		if err != nil {
+			return 0, err
+		}
+
+		task = result.(*entities.Task)
+		depth++
+	}
+	return depth, nil
+}
+
+// getTaskResps builds the response of the received tasks, including the progress of the ones with subtasks
+func (t *taskService) getTaskResps(ctx context.Context, tasks []entities.Task) ([]models.GetTaskResp, error) {
+	resps := make([]models.GetTaskResp, len(tasks))
+	if len(tasks) == 0 {
+		return resps, nil
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	counts, err := t.repository.CountSubtasks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, task := range tasks {
+		resps[i] = newGetTaskResp(task, counts[task.ID])
+	}
+	return resps, nil
+}
+
+// newGetTaskResp builds the response of a task, with the percentage of its subtasks that are done
+func newGetTaskResp(task entities.Task, subtasks models.SubtaskCount) models.GetTaskResp {
+	resp := models.GetTaskResp{
+		ID:               task.ID,
+		UserID:           task.UserID,
+		CreatorID:        task.CreatorID,
+		AssigneeID:       task.AssigneeID,
+		ProjectID:        task.ProjectID,
+		ParentID:         task.ParentID,
+		Position:         task.Position,
+		Rank:             task.Rank,
+		Title:            task.Title,
+		Description:      task.Description,
+		Status:           task.Status,
+		Priority:         task.Priority,
+		Labels:           task.Labels,
+		DueAt:            task.DueAt,
+		Recurrence:       task.Recurrence,
+		NextOccurrenceID: task.NextOccurrenceID,
+		CreatedAt:        task.CreatedAt,
+		UpdatedAt:        task.UpdatedAt,
+		CompletedAt:      task.CompletedAt,
+		DeletedAt:        task.DeletedAt,
+		Version:          task.Version,
+	}
+	if subtasks.Total > 0 {
+		progress := subtasks.Done * 100 / subtasks.Total
+		resp.Progress = &progress
+	}
+	return resp
+}
+
+// update replaces the editable fields of the received entity and persists it, recording the changes made by the received actor.
+// When its project changes, its subtasks are moved in the same transaction, and the entity only gets its new version once committed
+func (t *taskService) update(ctx context.Context, actorID string, entity *entities.Task, task models.UpdateTaskReq) (err error) {
+	before := *entity
+	projectChanged, err := t.applyUpdate(ctx, entity, task)
+	if err != nil {
+		return
+	}
+
+	var saved entities.Task
+	err = t.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		saved = *entity
+		err := t.writer.saveTask(ctx, actorID, entities.TaskHistoryActionUpdated, before, &saved)
+		if err != nil || !projectChanged {
+			return err
+		}
+		return t.cascadeProject(ctx, actorID, &saved)
+	})
+	if err != nil {
+		return
+	}
+
+	*entity = saved
+	return
+}
+
+// applyUpdate validates the received task and replaces the editable fields of the received entity, reporting whether its project has changed
+func (t *taskService) applyUpdate(ctx context.Context, entity *entities.Task, task models.UpdateTaskReq) (projectChanged bool, err error) {
+	entity.Priority, err = validatePriority(task.Priority)
+	if err != nil {
+		return
+	}
+
+	entity.Labels, err = t.validateLabels(ctx, entity.UserID, task.Labels)
+	if err != nil {
+		return
+	}
+
+	err = validateRecurrence(task.Recurrence, task.DueAt)
+	if err != nil {
 		return
 	}
 
-	err = t.repository.Delete(ctx, taskID)
+	// subtasks always belong to the project of their parent, so only top-level tasks can be moved between projects
+	projectChanged = entity.ParentID == "" && task.ProjectID != entity.ProjectID
+	if projectChanged {
+		err = t.validateProject(ctx, entity.UserID, task.ProjectID)
+		if err != nil {
+			return
+		}
+		entity.ProjectID = task.ProjectID
+	}
+
+	entity.Recurrence = task.Recurrence
+	entity.Title = task.Title
+	entity.Description = task.Description
+	entity.DueAt = task.DueAt
+	entity.UpdatedAt = time.Now().UTC()
 	return
 }
+
+// cascadeProject moves all the subtasks under the received entity to its project on behalf of the received actor
+func (t *taskService) cascadeProject(ctx context.Context, actorID string, entity *entities.Task) error {
+	descendants, err := getDescendants(ctx, t.repository, entity.ID, nil)
+	if err != nil || len(descendants) == 0 {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return t.writer.saveTasks(ctx, actorID, entities.TaskHistoryActionUpdated, descendants, func(task *entities.Task) {
+		task.ProjectID = entity.ProjectID
+		task.UpdatedAt = now
+	})
+}
+
+// validateProject checks that the received project, if any, belongs to the received userID and is not archived
+func (t *taskService) validateProject(ctx context.Context, userID, projectID string) error {
+	if projectID == "" {
+		return nil
+	}
+
+	result, err := t.projectRepository.GetByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewValidationErr(fmt.Errorf("project %s not found", projectID))
+		}
+		return err
+	}
+
+	project := result.(*entities.Project)
+	if project.UserID != userID {
+		return wrappers.NewValidationErr(fmt.Errorf("project %s not found", projectID))
+	}
+	if project.Archived {
+		return wrappers.NewValidationErr(fmt.Errorf("project %s is archived and cannot receive tasks", projectID))
+	}
+	return nil
+}
+
+// validatePriority checks the received priority, defaulting to medium when it is empty
+func validatePriority(priority string) (string, error) {
+	if priority == "" {
+		return entities.TaskPriorityMedium, nil
+	}
+	if !slices.Contains(taskPriorities, priority) {
+		return "", wrappers.NewValidationErr(fmt.Errorf("priority %s not valid", priority))
+	}
+	return priority, nil
+}
+
+// validateLabels removes the duplicated labels and checks that all of them are in the label catalogue of the received userID
+func (t *taskService) validateLabels(ctx context.Context, userID string, labels []string) ([]string, error) {
+	unique := []string{}
+	for _, label := range labels {
+		if !slices.Contains(unique, label) {
+			unique = append(unique, label)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	result, err := t.labelRepository.Get(ctx, map[string]interface{}{"user_id": userID, "name": map[string]interface{}{"$in": unique}}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return nil, err
+	}
+
+	found := make([]string, len(result))
+	for i, v := range result {
+		found[i] = v.(*entities.Label).Name
+	}
+	for _, label := range unique {
+		if !slices.Contains(found, label) {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("label %s not found, create it before assigning it to a task", label))
+		}
+	}
+	return unique, nil
+}
+
+// newTaskFilter builds the filter of the tasks of the received userID matching the request criteria
+func newTaskFilter(userID string, req models.GetTasksReq) (models.TaskFilter, error) {
+	filter := models.TaskFilter{
+		UserID:    userID,
+		DueBefore: req.DueBefore,
+		DueAfter:  req.DueAfter,
+		Labels:    req.Labels,
+	}
+	switch req.Scope {
+	case "", taskScopeOwn, taskScopeShared:
+	default:
+		return filter, wrappers.NewValidationErr(fmt.Errorf("scope %s not valid, allowed values: %s, %s", req.Scope, taskScopeOwn, taskScopeShared))
+	}
+	switch req.LabelMatch {
+	case "", labelMatchAny:
+	case labelMatchAll:
+		filter.AllLabels = true
+	default:
+		return filter, wrappers.NewValidationErr(fmt.Errorf("label_match %s not valid, allowed values: %s, %s", req.LabelMatch, labelMatchAny, labelMatchAll))
+	}
+	if req.Overdue {
+		now := time.Now().UTC()
+		if filter.DueBefore == nil || filter.DueBefore.After(now) {
+			filter.DueBefore = &now
+		}
+		filter.Statuses = openTaskStatuses
+	}
+	return filter, nil
+}
+
+// findSortedPage gets a page of the tasks matching the received filter, sorted by the requested field or by the default one
+func (t *taskService) findSortedPage(ctx context.Context, filter models.TaskFilter, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	resp.Tasks = []models.GetTaskResp{}
+	sort := req.Sort
+	if sort == "" {
+		sort = defaultTaskSort
+	}
+	filter.SortBy, filter.SortDesc = strings.CutPrefix(sort, "-")
+	if !slices.Contains(taskSortFields, filter.SortBy) {
+		return resp, wrappers.NewValidationErr(fmt.Errorf("sort %s not valid, allowed fields: %s", sort, strings.Join(taskSortFields, ", ")))
+	}
+
+	return t.findPage(ctx, filter, sort, req)
+}
+
+// findPage gets a page of the tasks matching the received filter, sorted by the received sort, and builds the cursor of the next page
+func (t *taskService) findPage(ctx context.Context, filter models.TaskFilter, sort string, req models.GetTasksReq) (resp models.GetTasksResp, err error) {
+	resp.Tasks = []models.GetTaskResp{}
+
+	limit, err := pageLimit(t.config.Pagination, req.Limit)
+	if err != nil {
+		return
+	}
+	filter.Limit = limit + 1
+
+	if req.Cursor != "" {
+		filter.After, err = decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return
+		}
+	}
+
+	result, err := t.repository.Find(ctx, filter)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	if len(result) > limit {
+		result = result[:limit]
+		resp.NextCursor, err = encodeCursor(sort, result[limit-1].(*entities.Task))
+		if err != nil {
+			return
+		}
+	}
+
+	tasks := make([]entities.Task, len(result))
+	for i, v := range result {
+		tasks[i] = *(v.(*entities.Task))
+	}
+
+	resp.Tasks, err = t.getTaskResps(ctx, tasks)
+	return
+}
+
+// pageLimit checks the received page limit, defaulting to the configured one when it is not set
+func pageLimit(pagination config.Pagination, limit int) (int, error) {
+	if limit == 0 {
+		return pagination.DefaultLimit, nil
+	}
+	if limit < 0 || limit > pagination.MaxLimit {
+		return 0, wrappers.NewValidationErr(fmt.Errorf("limit %d not valid, it must be between 1 and %d", limit, pagination.MaxLimit))
+	}
+	return limit, nil
+}
+
+// encodeCursor builds the opaque cursor pointing after the received task
+func encodeCursor(sort string, task *entities.Task) (string, error) {
+	cursor, err := json.Marshal(models.TaskCursor{
+		Sort:      sort,
+		ID:        task.ID,
+		Title:     task.Title,
+		Rank:      task.Rank,
+		DueAt:     task.DueAt,
+		CreatedAt: task.CreatedAt,
+		DeletedAt: task.DeletedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(cursor), nil
+}
+
+// decodeCursor parses an opaque cursor, checking that it was issued for the received sort
+func decodeCursor(encoded, sort string) (*models.TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, wrappers.NewValidationErr(errors.New("cursor not valid"))
+	}
+
+	var cursor models.TaskCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, wrappers.NewValidationErr(errors.New("cursor not valid"))
+	}
+	if cursor.Sort != sort {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("cursor was issued for sort %s, not for %s", cursor.Sort, sort))
+	}
+	return &cursor, nil
+}
+
+// mergePatch applies a JSON Merge Patch document (RFC 7396) over the received task
+func mergePatch(task models.UpdateTaskReq, patch models.PatchTaskReq) (models.UpdateTaskReq, error) {
+	original, err := json.Marshal(task)
+	if err != nil {
+		return task, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(original, &document); err != nil {
+		return task, err
+	}
+
+	merged, err := json.Marshal(mergeObjects(document, patch))
+	if err != nil {
+		return task, err
+	}
+
+	var result models.UpdateTaskReq
+	if err := json.Unmarshal(merged, &result); err != nil {
+		return task, wrappers.NewValidationErr(fmt.Errorf("invalid patch document: %w", err))
+	}
+
+	return result, nil
+}
+
+// mergeObjects recursively merges the patch object into the target object, removing the members set to null
+func mergeObjects(target, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchObject, ok := value.(map[string]interface{})
+		if !ok {
+			target[key] = value
+			continue
+		}
+
+		targetObject, ok := target[key].(map[string]interface{})
+		if !ok {
+			targetObject = map[string]interface{}{}
+		}
+		target[key] = mergeObjects(targetObject, patchObject)
+	}
+	return target
+}
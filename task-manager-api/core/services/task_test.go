@@ -13,7 +13,9 @@ import (
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
 	"github.com/sergicanet9/scv-go-tools/v4/testutils"
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,7 @@ func TestCreate_Ok(t *testing.T) {
 	}
 
 	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return(expectedResponse.ID, nil).Once()
 
 	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
@@ -41,6 +44,9 @@ func TestCreate_Ok(t *testing.T) {
 	service := &taskService{
 		config:               config.Config{},
 		repository:           taskRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
 		userManagementClient: userManagementClientMock,
 	}
 
@@ -52,6 +58,41 @@ func TestCreate_Ok(t *testing.T) {
 	assert.Equal(t, expectedResponse, resp)
 }
 
+// TestCreate_OutboxError checks that Create returns an error, failing the transaction the task is created in, when its event cannot be written to the outbox
+func TestCreate_OutboxError(t *testing.T) {
+	// Arrange
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("new-id", nil).Once()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Create), mock.Anything, mock.AnythingOfType("entities.OutboxEvent")).Return("", errors.New("outbox-error")).Once()
+
+	transactionManagerMock := mocks.NewTransactionManager(t)
+	transactionManagerMock.On(testutils.FunctionName(t, ports.TransactionManager.WithTransaction), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), outboxRepositoryMock, transactionManagerMock),
+		transactions:         transactionManagerMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	resp, err := service.Create(context.Background(), "user-123", models.CreateTaskReq{Title: "test-title"}, "Bearer test-token")
+
+	// Assert
+	assert.Equal(t, "outbox-error", err.Error())
+	assert.Empty(t, resp.ID)
+}
+
 // TestCreate_UserManagementClientError checks that Create returns an error when the user management client fails
 func TestCreate_UserManagementClientError(t *testing.T) {
 	// Arrange
@@ -89,6 +130,7 @@ func TestCreate_RepositoryError(t *testing.T) {
 	expectedError := "repository-error"
 
 	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.AnythingOfType("entities.Task")).Return("", errors.New(expectedError)).Once()
 
 	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
@@ -98,6 +140,33 @@ func TestCreate_RepositoryError(t *testing.T) {
 		config:               config.Config{},
 		repository:           taskRepositoryMock,
 		userManagementClient: userManagementClientMock,
+		writer:               NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", req, "Bearer test-token")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreate_InvalidPriority checks that Create returns a validation error when the received priority is not valid
+func TestCreate_InvalidPriority(t *testing.T) {
+	// Arrange
+	req := models.CreateTaskReq{
+		Title:    "test-title",
+		Priority: "invalid-priority",
+	}
+	expectedError := "priority invalid-priority not valid"
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		userManagementClient: userManagementClientMock,
 	}
 
 	// Act
@@ -105,9 +174,76 @@ func TestCreate_RepositoryError(t *testing.T) {
 
 	// Assert
 	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestCreate_Labels checks that Create removes the duplicated labels and checks them against the label catalogue
+func TestCreate_Labels(t *testing.T) {
+	// Arrange
+	req := models.CreateTaskReq{
+		Title:  "test-title",
+		Labels: []string{"work", "urgent", "work"},
+	}
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&entities.Label{Name: "work"}, &entities.Label{Name: "urgent"}}, nil).Once()
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+		return assert.ObjectsAreEqual([]string{"work", "urgent"}, task.Labels)
+	})).Return("new-id", nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		labelRepository:      labelRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", req, "Bearer test-token")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestCreate_LabelNotFound checks that Create returns a validation error when a label is not in the label catalogue
+func TestCreate_LabelNotFound(t *testing.T) {
+	// Arrange
+	req := models.CreateTaskReq{
+		Title:  "test-title",
+		Labels: []string{"work", "urgent"},
+	}
+
+	labelRepositoryMock := mocks.NewLabelRepository(t)
+	labelRepositoryMock.On(testutils.FunctionName(t, ports.LabelRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&entities.Label{Name: "work"}}, nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		labelRepository:      labelRepositoryMock,
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", req, "Bearer test-token")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Contains(t, err.Error(), "label urgent not found")
+}
+
 // TestGetByUserID_Ok checks that GetByUserID returns the expected response when a valid request is received
 func TestGetByUserID_Ok(t *testing.T) {
 	// Arrange
@@ -131,68 +267,290 @@ func TestGetByUserID_Ok(t *testing.T) {
 		},
 	}
 	repoResponse = append(repoResponse, &tasks[0], &tasks[1])
-	expectedResponse := []models.GetTaskResp{
-		models.GetTaskResp(tasks[0]),
-		models.GetTaskResp(tasks[1]),
+	expectedResponse := models.GetTasksResp{
+		Tasks: []models.GetTaskResp{
+			newGetTaskResp(tasks[0], models.SubtaskCount{}),
+			newGetTaskResp(tasks[1], models.SubtaskCount{}),
+		},
 	}
 
 	taskRepositoryMock := mocks.NewTaskRepository(t)
-	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(repoResponse, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.Anything).Return(repoResponse, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
 
 	service := &taskService{
-		config:     config.Config{},
+		config:     cfg,
 		repository: taskRepositoryMock,
 	}
 
 	// Act
-	resp, err := service.GetByUserID(context.Background(), userID)
+	resp, err := service.GetByUserID(context.Background(), userID, models.GetTasksReq{})
 
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResponse, resp)
 }
 
+// TestGetByUserID_Overdue checks that GetByUserID only looks for open tasks due before now when overdue is requested
+func TestGetByUserID_Overdue(t *testing.T) {
+	// Arrange
+	userID := "test-user-id"
+	dueBefore := time.Now().UTC().Add(time.Hour)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.UserID == userID && f.DueBefore.Before(dueBefore) && assert.ObjectsAreEqual(openTaskStatuses, f.Statuses)
+	})).Return(nil, wrappers.NonExistentErr).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetByUserID(context.Background(), userID, models.GetTasksReq{Overdue: true, DueBefore: &dueBefore})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(resp.Tasks))
+}
+
 // TestGetByUserID_NoResourcesFound checks that GetByUserID does not return an error when the repository does not return any task for the received userID
 func TestGetByUserID_NoResourcesFound(t *testing.T) {
 	// Arrange
 	taskRepositoryMock := mocks.NewTaskRepository(t)
-	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
 
 	service := &taskService{
-		config:     config.Config{},
+		config:     cfg,
 		repository: taskRepositoryMock,
 	}
 
 	// Act
-	resp, err := service.GetByUserID(context.Background(), "user-123")
+	resp, err := service.GetByUserID(context.Background(), "user-123", models.GetTasksReq{})
 
 	// Assert
 	assert.Nil(t, err)
-	assert.Equal(t, 0, len(resp))
+	assert.Equal(t, 0, len(resp.Tasks))
 }
 
-// TestGetByUserID_RepositoryError checks that GetByID returns an error when the repository fails
+// TestGetByUserID_RepositoryError checks that GetByUserID returns an error when the repository fails
 func TestGetByUserID_RepositoryError(t *testing.T) {
 	// Arrange
 	expectedError := "repository-error"
 
 	taskRepositoryMock := mocks.NewTaskRepository(t)
-	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(expectedError)).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.Anything).Return(nil, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
 
 	service := &taskService{
-		config:     config.Config{},
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetByUserID(context.Background(), "user-123", models.GetTasksReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetByUserID_NextPage checks that GetByUserID returns a cursor when there are more tasks than the requested limit, and that the cursor is accepted back
+func TestGetByUserID_NextPage(t *testing.T) {
+	// Arrange
+	userID := "test-user-id"
+	tasks := []entities.Task{
+		{ID: "64b7f0c2a1b2c3d4e5f60001", UserID: userID, Title: "title-1"},
+		{ID: "64b7f0c2a1b2c3d4e5f60002", UserID: userID, Title: "title-2"},
+		{ID: "64b7f0c2a1b2c3d4e5f60003", UserID: userID, Title: "title-3"},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.SortBy == "title" && f.SortDesc && f.Limit == 3 && f.After == nil
+	})).Return([]interface{}{&tasks[0], &tasks[1], &tasks[2]}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.After != nil && f.After.ID == tasks[1].ID && f.After.Title == tasks[1].Title
+	})).Return([]interface{}{&tasks[2]}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	firstPage, firstErr := service.GetByUserID(context.Background(), userID, models.GetTasksReq{Sort: "-title", Limit: 2})
+	secondPage, secondErr := service.GetByUserID(context.Background(), userID, models.GetTasksReq{Sort: "-title", Limit: 2, Cursor: firstPage.NextCursor})
+
+	// Assert
+	assert.Nil(t, firstErr)
+	assert.Equal(t, 2, len(firstPage.Tasks))
+	assert.NotEmpty(t, firstPage.NextCursor)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, 1, len(secondPage.Tasks))
+	assert.Empty(t, secondPage.NextCursor)
+}
+
+// TestGetByUserID_InvalidRequest checks that GetByUserID returns a validation error when the pagination or filter parameters are not valid
+func TestGetByUserID_InvalidRequest(t *testing.T) {
+	otherSortCursor, _ := encodeCursor("title", &entities.Task{ID: "task-id"})
+
+	tests := []struct {
+		name string
+		req  models.GetTasksReq
+	}{
+		{"invalid sort", models.GetTasksReq{Sort: "description"}},
+		{"limit too big", models.GetTasksReq{Limit: 101}},
+		{"negative limit", models.GetTasksReq{Limit: -1}},
+		{"malformed cursor", models.GetTasksReq{Cursor: "not-a-cursor"}},
+		{"cursor for another sort", models.GetTasksReq{Cursor: otherSortCursor}},
+		{"invalid label match", models.GetTasksReq{Labels: []string{"work"}, LabelMatch: "some"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := config.Config{}
+			cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+			service := &taskService{
+				config: cfg,
+			}
+
+			// Act
+			_, err := service.GetByUserID(context.Background(), "user-123", tt.req)
+
+			// Assert
+			assert.NotEmpty(t, err)
+			assert.IsType(t, wrappers.ValidationErr, err)
+		})
+	}
+}
+
+// TestSearch_Ok checks that Search returns the matching tasks with their scores and highlights
+func TestSearch_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	userID := "test-user-id"
+	results := []entities.TaskSearchResult{
+		{
+			Task: entities.Task{
+				ID:          "task-id-1",
+				UserID:      userID,
+				Title:       "Quarterly report",
+				Description: "description-1",
+				CreatedAt:   now,
+			},
+			Score: 1.5,
+		},
+	}
+	repoResponse := []interface{}{&results[0]}
+	expectedResponse := models.GetTasksResp{
+		Tasks: []models.GetTaskResp{newGetTaskResp(results[0].Task, models.SubtaskCount{})},
+		Matches: []models.TaskMatch{
+			{
+				ID:         "task-id-1",
+				Score:      1.5,
+				Highlights: map[string][]string{"title": {"Quarterly <em>report</em>"}},
+			},
+		},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Search), mock.Anything, mock.Anything, "report").Return(repoResponse, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, mock.Anything).Return(map[string]models.SubtaskCount{}, nil)
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Search(context.Background(), userID, models.GetTasksReq{Query: "report"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestSearch_EmptyQuery checks that Search returns a validation error when the query has no searchable terms
+func TestSearch_EmptyQuery(t *testing.T) {
+	// Arrange
+	service := &taskService{}
+
+	// Act
+	_, err := service.Search(context.Background(), "user-123", models.GetTasksReq{Query: "-report"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestSearch_NoResourcesFound checks that Search returns an empty response when no tasks match the query
+func TestSearch_NoResourcesFound(t *testing.T) {
+	// Arrange
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Search), mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Search(context.Background(), "user-123", models.GetTasksReq{Query: "report"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(resp.Tasks))
+}
+
+// TestSearch_RepositoryError checks that Search returns an error when the repository fails
+func TestSearch_RepositoryError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Search), mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+	service := &taskService{
+		config:     cfg,
 		repository: taskRepositoryMock,
 	}
 
 	// Act
-	_, err := service.GetByUserID(context.Background(), "user-123")
+	_, err := service.Search(context.Background(), "user-123", models.GetTasksReq{Query: "report"})
 
 	// Assert
 	assert.NotEmpty(t, err)
 	assert.Equal(t, expectedError, err.Error())
 }
 
-// TestDelete_Ok checks that Delete does not return an error when a valid request is received
+// TestDelete_Ok checks that Delete moves the task to the trash when a valid request is received
 func TestDelete_Ok(t *testing.T) {
 	// Arrange
 	task := entities.Task{
@@ -204,15 +562,21 @@ func TestDelete_Ok(t *testing.T) {
 	}
 	taskRepositoryMock := mocks.NewTaskRepository(t)
 	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
-	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Delete), mock.Anything, mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.DeletedAt != nil
+	}), task.Version).Return(nil).Once()
 
 	service := &taskService{
-		config:     config.Config{},
-		repository: taskRepositoryMock,
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
 	}
 
 	// Act
-	err := service.Delete(context.Background(), task.UserID, task.ID)
+	err := service.Delete(context.Background(), task.UserID, task.ID, 0, false)
 
 	// Assert
 	assert.Nil(t, err)
@@ -236,7 +600,7 @@ func TestDelete_NotFound(t *testing.T) {
 	}
 
 	// Act
-	err := service.Delete(context.Background(), task.UserID, task.ID)
+	err := service.Delete(context.Background(), task.UserID, task.ID, 0, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -258,12 +622,13 @@ func TestDelete_InvalidUser(t *testing.T) {
 	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
 
 	service := &taskService{
-		config:     config.Config{},
-		repository: taskRepositoryMock,
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
 	}
 
 	// Act
-	err := service.Delete(context.Background(), wrongUserID, task.ID)
+	err := service.Delete(context.Background(), wrongUserID, task.ID, 0, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -285,7 +650,34 @@ func TestDelete_RepositoryError(t *testing.T) {
 
 	taskRepositoryMock := mocks.NewTaskRepository(t)
 	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
-	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Delete), mock.Anything, mock.Anything).Return(errors.New(expectedError)).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(expectedError)).Once()
+
+	service := &taskService{
+		config:       config.Config{},
+		repository:   taskRepositoryMock,
+		writer:       NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions: newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), task.UserID, task.ID, 0, false)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDelete_WithSubtasks checks that Delete refuses to delete a task with subtasks when cascade is not set
+func TestDelete_WithSubtasks(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	subtask := entities.Task{ID: "subtask-id", UserID: "user-123", ParentID: task.ID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{task.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{subtask.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &taskService{
 		config:     config.Config{},
@@ -293,9 +685,953 @@ func TestDelete_RepositoryError(t *testing.T) {
 	}
 
 	// Act
-	err := service.Delete(context.Background(), task.UserID, task.ID)
+	err := service.Delete(context.Background(), task.UserID, task.ID, 0, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestDelete_Cascade checks that Delete moves the task and all its subtasks to the trash when cascade is set
+func TestDelete_Cascade(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	subtask := entities.Task{ID: "subtask-id", UserID: "user-123", ParentID: task.ID}
+	nestedSubtask := entities.Task{ID: "nested-subtask-id", UserID: "user-123", ParentID: subtask.ID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{task.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{subtask.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&nestedSubtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{nestedSubtask.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	for _, id := range []string{task.ID, subtask.ID, nestedSubtask.ID} {
+		taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, id, mock.MatchedBy(func(e entities.Task) bool {
+			return e.DeletedAt != nil
+		}), 0).Return(nil).Once()
+	}
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	err := service.Delete(context.Background(), task.UserID, task.ID, 0, true)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRestore_Ok checks that Restore takes the task out of the trash together with the subtasks deleted with it
+func TestRestore_Ok(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", DeletedAt: &deletedAt, Version: 4}
+	subtask := entities.Task{ID: "subtask-id", UserID: "user-123", ParentID: task.ID, DeletedAt: &deletedAt, Version: 2}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{task.ID}}, "deleted_at": deletedAt}, mock.Anything, mock.Anything).Return([]interface{}{&subtask}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{subtask.ID}}, "deleted_at": deletedAt}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	for _, restored := range []entities.Task{task, subtask} {
+		taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, restored.ID, mock.MatchedBy(func(e entities.Task) bool {
+			return e.DeletedAt == nil
+		}), restored.Version).Return(nil).Once()
+	}
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.Restore(context.Background(), task.UserID, task.ID, task.Version)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, task.Version+1, newVersion)
+}
+
+// TestRestore_NotInTrash checks that Restore returns a validation error when the task is not in the trash
+func TestRestore_NotInTrash(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123"}
+	expectedError := fmt.Sprintf("TaskID %s is not in the trash", task.ID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Restore(context.Background(), task.UserID, task.ID, 0)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
 	assert.Equal(t, expectedError, err.Error())
 }
+
+// TestRestore_ParentInTrash checks that Restore returns a validation error when the parent of the task is still in the trash
+func TestRestore_ParentInTrash(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	parent := entities.Task{ID: "parent-id", UserID: "user-123", DeletedAt: &deletedAt}
+	task := entities.Task{ID: "task-id", UserID: "user-123", ParentID: parent.ID, DeletedAt: &deletedAt}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Restore(context.Background(), task.UserID, task.ID, 0)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestRestore_PurgedParent checks that Restore returns a validation error when the parent of the task has been permanently deleted
+func TestRestore_PurgedParent(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", ParentID: "parent-id", DeletedAt: &deletedAt}
+	expectedError := fmt.Sprintf("TaskID %s cannot be restored as its parent TaskID %s has been permanently deleted", task.ID, task.ParentID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ParentID).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Restore(context.Background(), task.UserID, task.ID, 0)
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetTrash_Ok checks that GetTrash gets the tasks in the trash, the most recently deleted first
+func TestGetTrash_Ok(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", DeletedAt: &deletedAt}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Find), mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.UserID == task.UserID && f.Deleted && f.SortBy == "deleted_at" && f.SortDesc
+	})).Return([]interface{}{&task}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, []string{task.ID}).Return(map[string]models.SubtaskCount{}, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Pagination = config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetTrash(context.Background(), task.UserID, models.GetTasksReq{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.GetTaskResp{newGetTaskResp(task, models.SubtaskCount{})}, resp.Tasks)
+}
+
+// TestPurgeTrash_Ok checks that PurgeTrash removes the expired tasks together with their dependencies
+func TestPurgeTrash_Ok(t *testing.T) {
+	// Arrange
+	purgedIDs := []string{"task-id", "subtask-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.PurgeDeleted), mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-23 * time.Hour))
+	})).Return(purgedIDs, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	attachmentRepositoryMock := mocks.NewAttachmentRepository(t)
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.FindByTaskIDs), mock.Anything, purgedIDs).Return([]interface{}{&entities.Attachment{ID: "attachment-id", TaskID: "task-id", BlobKey: "blob-key"}}, nil).Once()
+	attachmentRepositoryMock.On(testutils.FunctionName(t, ports.AttachmentRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	blobStoreMock := mocks.NewBlobStore(t)
+	blobStoreMock.On(testutils.FunctionName(t, ports.BlobStore.Delete), mock.Anything, "blob-key").Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Trash = config.Trash{Retention: utils.Duration{Duration: 24 * time.Hour}}
+	service := &taskService{
+		collaboratorRepository: collaboratorRepositoryMock,
+		config:                 cfg,
+		repository:             taskRepositoryMock,
+		dependencyRepository:   dependencyRepositoryMock,
+		commentRepository:      commentRepositoryMock,
+		attachmentRepository:   attachmentRepositoryMock,
+		blobStore:              blobStoreMock,
+		writer:                 NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+	}
+
+	// Act
+	purged, err := service.PurgeTrash(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, len(purgedIDs), purged)
+}
+
+// TestPurgeTrash_CleanupError checks that PurgeTrash returns the error of a failed cleanup without removing any content from the blob store,
+// as the transaction removing the tasks is rolled back
+func TestPurgeTrash_CleanupError(t *testing.T) {
+	// Arrange
+	purgedIDs := []string{"task-id"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.PurgeDeleted), mock.Anything, mock.Anything).Return(purgedIDs, nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	collaboratorRepositoryMock := mocks.NewTaskCollaboratorRepository(t)
+	collaboratorRepositoryMock.On(testutils.FunctionName(t, ports.TaskCollaboratorRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(nil).Once()
+
+	commentRepositoryMock := mocks.NewCommentRepository(t)
+	commentRepositoryMock.On(testutils.FunctionName(t, ports.CommentRepository.DeleteByTaskIDs), mock.Anything, purgedIDs).Return(errors.New("comment-error")).Once()
+
+	service := &taskService{
+		collaboratorRepository: collaboratorRepositoryMock,
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		dependencyRepository:   dependencyRepositoryMock,
+		commentRepository:      commentRepositoryMock,
+		attachmentRepository:   mocks.NewAttachmentRepository(t),
+		blobStore:              mocks.NewBlobStore(t),
+		writer:                 NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions:           newTransactionManagerMock(t),
+	}
+
+	// Act
+	purged, err := service.PurgeTrash(context.Background())
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, purged)
+}
+
+// TestUpdate_InTrash checks that Update returns a non existent error when the task is in the trash
+func TestUpdate_InTrash(t *testing.T) {
+	// Arrange
+	deletedAt := time.Now().UTC()
+	task := entities.Task{ID: "task-id", UserID: "user-123", DeletedAt: &deletedAt}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 0, models.UpdateTaskReq{Title: "title"})
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestUpdate_Ok checks that Update replaces the editable fields of the task when a valid request is received
+func TestUpdate_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:          "task-id",
+		UserID:      "user-123",
+		Title:       "title",
+		Description: "description",
+		CreatedAt:   time.Now(),
+		Version:     3,
+	}
+	req := models.UpdateTaskReq{
+		Title:       "new-title",
+		Description: "new-description",
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Title == req.Title && e.Description == req.Description && e.UserID == task.UserID && !e.UpdatedAt.IsZero()
+	}), 3).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	version, err := service.Update(context.Background(), task.UserID, task.ID, 3, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 4, version)
+}
+
+// TestUpdate_VersionMismatch checks that Update returns a precondition failed error without saving the task when it is at another version
+func TestUpdate_VersionMismatch(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", Version: 4}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 3, models.UpdateTaskReq{Title: "new-title"})
+
+	// Assert
+	assert.True(t, errors.Is(err, taskWrappers.PreconditionFailedErr))
+}
+
+// TestUpdate_ConcurrentUpdate checks that Update returns the precondition failed error of the repository when the task is saved concurrently
+func TestUpdate_ConcurrentUpdate(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.Anything, 3).Return(taskWrappers.NewPreconditionFailedErr(errors.New("modified"))).Once()
+
+	service := &taskService{
+		config:       config.Config{},
+		repository:   taskRepositoryMock,
+		writer:       NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions: newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 3, models.UpdateTaskReq{Title: "new-title"})
+
+	// Assert
+	assert.True(t, errors.Is(err, taskWrappers.PreconditionFailedErr))
+}
+
+// TestGet_Ok checks that Get returns the task with its version when the user can see it
+func TestGet_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, []string{task.ID}).Return(map[string]models.SubtaskCount{}, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Get(context.Background(), task.UserID, task.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, task.Title, resp.Title)
+	assert.Equal(t, 3, resp.Version)
+}
+
+// TestUpdate_NotFound checks that Update returns an error when the provided taskID does not exist
+func TestUpdate_NotFound(t *testing.T) {
+	// Arrange
+	taskID := "task-id"
+	expectedError := fmt.Sprintf("TaskID %s not found", taskID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), "user-123", taskID, 0, models.UpdateTaskReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdate_InvalidUser checks that Update returns an error when the provided taskID does not correspond to the received userID
+func TestUpdate_InvalidUser(t *testing.T) {
+	// Arrange
+	wrongUserID := "other-user"
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+	}
+	expectedError := fmt.Sprintf("UserID %s not allowed to update TaskID %s", wrongUserID, task.ID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), wrongUserID, task.ID, 0, models.UpdateTaskReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdate_RepositoryError checks that Update returns an error when the repository fails
+func TestUpdate_RepositoryError(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+	}
+	expectedError := "repository-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New(expectedError)).Once()
+
+	service := &taskService{
+		config:       config.Config{},
+		repository:   taskRepositoryMock,
+		writer:       NewTaskWriter(taskRepositoryMock, nil, nil, newTransactionManagerMock(t)),
+		transactions: newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Update(context.Background(), task.UserID, task.ID, 0, models.UpdateTaskReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestPatch_Ok checks that Patch only modifies the fields present in the merge patch document and resets the ones set to null
+func TestPatch_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:          "task-id",
+		UserID:      "user-123",
+		Title:       "title",
+		Description: "description",
+	}
+	patch := models.PatchTaskReq{"title": "new-title"}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Title == "new-title" && e.Description == "description"
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Patch(context.Background(), task.UserID, task.ID, 0, patch)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPatch_NullMember checks that Patch resets the fields set to null in the merge patch document
+func TestPatch_NullMember(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:          "task-id",
+		UserID:      "user-123",
+		Title:       "title",
+		Description: "description",
+	}
+	patch := models.PatchTaskReq{"description": nil}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Title == "title" && e.Description == ""
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Patch(context.Background(), task.UserID, task.ID, 0, patch)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPatch_InvalidDocument checks that Patch returns a validation error when the merge patch document does not match the task fields
+func TestPatch_InvalidDocument(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+	}
+	patch := models.PatchTaskReq{"title": 123}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Patch(context.Background(), task.UserID, task.ID, 0, patch)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestPatch_InvalidUser checks that Patch returns an error when the provided taskID does not correspond to the received userID
+func TestPatch_InvalidUser(t *testing.T) {
+	// Arrange
+	wrongUserID := "other-user"
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+	}
+	expectedError := fmt.Sprintf("UserID %s not allowed to update TaskID %s", wrongUserID, task.ID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, mock.Anything).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.Patch(context.Background(), wrongUserID, task.ID, 0, models.PatchTaskReq{})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetStats_Ok checks that GetStats aggregates the counts returned by the repository
+func TestGetStats_Ok(t *testing.T) {
+	// Arrange
+	counts := map[string]int{
+		entities.TaskStatusTodo:       3,
+		entities.TaskStatusInProgress: 2,
+		entities.TaskStatusBlocked:    1,
+		entities.TaskStatusDone:       4,
+		entities.TaskStatusArchived:   5,
+	}
+	expectedResponse := models.GetTaskStatsResp{
+		Total:    15,
+		Open:     6,
+		Done:     4,
+		ByStatus: counts,
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountByStatus), mock.Anything, "user-123").Return(counts, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetStats(context.Background(), "user-123")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResponse, resp)
+}
+
+// TestGetStats_RepositoryError checks that GetStats returns an error when the repository fails
+func TestGetStats_RepositoryError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountByStatus), mock.Anything, mock.Anything).Return(nil, errors.New(expectedError)).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetStats(context.Background(), "user-123")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestTransition_Done checks that Transition records the completion time when a task is moved to done
+func TestTransition_Done(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+		Status: entities.TaskStatusInProgress,
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Status == entities.TaskStatusDone && e.CompletedAt != nil
+	}), mock.Anything).Return(nil).Once()
+
+	dependencyRepositoryMock := mocks.NewTaskDependencyRepository(t)
+	dependencyRepositoryMock.On(testutils.FunctionName(t, ports.TaskDependencyRepository.Get), mock.Anything, map[string]interface{}{"task_id": task.ID}, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &taskService{
+		config:               config.Config{},
+		repository:           taskRepositoryMock,
+		dependencyRepository: dependencyRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusDone})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestTransition_Reopen checks that Transition clears the completion time when a done task is reopened, placing it at the bottom of its new column
+func TestTransition_Reopen(t *testing.T) {
+	// Arrange
+	completedAt := time.Now().UTC()
+	task := entities.Task{
+		ID:          "task-id",
+		UserID:      "user-123",
+		Status:      entities.TaskStatusDone,
+		CompletedAt: &completedAt,
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, task.UserID, entities.TaskStatusInProgress).Return("W", nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return e.Status == entities.TaskStatusInProgress && e.CompletedAt == nil && e.Rank == "X"
+	}), mock.Anything).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusInProgress})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestTransition_NotAllowed checks that Transition returns a validation error when the transition is not allowed from the current status
+func TestTransition_NotAllowed(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+		Status: entities.TaskStatusArchived,
+	}
+	expectedError := fmt.Sprintf("transition from status %s to %s not allowed for TaskID %s", entities.TaskStatusArchived, entities.TaskStatusTodo, task.ID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusTodo})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestTransition_InvalidStatus checks that Transition returns a validation error when the target status does not exist
+func TestTransition_InvalidStatus(t *testing.T) {
+	// Arrange
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+		Status: entities.TaskStatusTodo,
+	}
+	expectedError := "status invalid-status not valid"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), task.UserID, task.ID, 0, models.TransitionTaskReq{Status: "invalid-status"})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestTransition_InvalidUser checks that Transition returns an error when the provided taskID does not correspond to the received userID
+func TestTransition_InvalidUser(t *testing.T) {
+	// Arrange
+	wrongUserID := "other-user"
+	task := entities.Task{
+		ID:     "task-id",
+		UserID: "user-123",
+		Status: entities.TaskStatusTodo,
+	}
+	expectedError := fmt.Sprintf("UserID %s not allowed to update TaskID %s", wrongUserID, task.ID)
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, task.ID).Return(&task, nil).Once()
+
+	service := &taskService{
+		config:                 config.Config{},
+		repository:             taskRepositoryMock,
+		collaboratorRepository: newTaskCollaboratorRepositoryMock(t),
+	}
+
+	// Act
+	_, err := service.Transition(context.Background(), wrongUserID, task.ID, 0, models.TransitionTaskReq{Status: entities.TaskStatusDone})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.UnauthenticatedErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestCreateSubtask_Ok checks that CreateSubtask creates the task under the parent, placed after its current subtasks
+func TestCreateSubtask_Ok(t *testing.T) {
+	// Arrange
+	parent := entities.Task{ID: "parent-id", UserID: "user-123"}
+	sibling := entities.Task{ID: "sibling-id", UserID: "user-123", ParentID: parent.ID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.LastRank), mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&sibling}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Create), mock.Anything, mock.MatchedBy(func(task entities.Task) bool {
+		return task.ParentID == parent.ID && task.Position == 1
+	})).Return("new-id", nil).Once()
+
+	userManagementClientMock := commonMocks.NewUserManagementV1GRPCClient(t)
+	userManagementClientMock.On(testutils.FunctionName(t, commonPorts.UserManagementV1GRPCClient.Exists), mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Subtasks = config.Subtasks{MaxDepth: 2}
+
+	service := &taskService{
+		config:               cfg,
+		repository:           taskRepositoryMock,
+		historyRepository:    newTaskHistoryRepositoryMock(t),
+		writer:               NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:         newTransactionManagerMock(t),
+		userManagementClient: userManagementClientMock,
+	}
+
+	// Act
+	resp, err := service.CreateSubtask(context.Background(), "user-123", parent.ID, models.CreateTaskReq{Title: "step"}, "Bearer test-token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "new-id", resp.ID)
+}
+
+// TestCreateSubtask_MaxDepth checks that CreateSubtask returns a validation error when the parent is already at the maximum depth
+func TestCreateSubtask_MaxDepth(t *testing.T) {
+	// Arrange
+	root := entities.Task{ID: "root-id", UserID: "user-123"}
+	parent := entities.Task{ID: "parent-id", UserID: "user-123", ParentID: root.ID}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, root.ID).Return(&root, nil).Once()
+
+	cfg := config.Config{}
+	cfg.Subtasks = config.Subtasks{MaxDepth: 1}
+
+	service := &taskService{
+		config:     cfg,
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	_, err := service.CreateSubtask(context.Background(), "user-123", parent.ID, models.CreateTaskReq{Title: "step"}, "Bearer test-token")
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestGetSubtasks_Ok checks that GetSubtasks returns the subtasks sorted by position and with their progress
+func TestGetSubtasks_Ok(t *testing.T) {
+	// Arrange
+	parent := entities.Task{ID: "parent-id", UserID: "user-123"}
+	subtasks := []entities.Task{
+		{ID: "subtask-2", UserID: "user-123", ParentID: parent.ID, Position: 1},
+		{ID: "subtask-1", UserID: "user-123", ParentID: parent.ID, Position: 0},
+	}
+	progress := 50
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, map[string]interface{}{"parent_id": map[string]interface{}{"$in": []string{parent.ID}}, "deleted_at": nil}, mock.Anything, mock.Anything).Return([]interface{}{&subtasks[0], &subtasks[1]}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.CountSubtasks), mock.Anything, []string{"subtask-1", "subtask-2"}).Return(map[string]models.SubtaskCount{"subtask-1": {Total: 4, Done: 2}}, nil).Once()
+
+	service := &taskService{
+		config:     config.Config{},
+		repository: taskRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetSubtasks(context.Background(), "user-123", parent.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(resp))
+	assert.Equal(t, "subtask-1", resp[0].ID)
+	assert.Equal(t, &progress, resp[0].Progress)
+	assert.Nil(t, resp[1].Progress)
+}
+
+// TestReorderSubtasks_Ok checks that ReorderSubtasks only saves the subtasks whose position changes and increments the version of the parent task
+func TestReorderSubtasks_Ok(t *testing.T) {
+	// Arrange
+	parent := entities.Task{ID: "parent-id", UserID: "user-123", Version: 3}
+	subtasks := []entities.Task{
+		{ID: "64b7f0c2a1b2c3d4e5f60001", UserID: "user-123", ParentID: parent.ID, Position: 0},
+		{ID: "64b7f0c2a1b2c3d4e5f60002", UserID: "user-123", ParentID: parent.ID, Position: 1},
+		{ID: "64b7f0c2a1b2c3d4e5f60003", UserID: "user-123", ParentID: parent.ID, Position: 2},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&subtasks[0], &subtasks[1], &subtasks[2]}, nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, subtasks[1].ID, mock.MatchedBy(func(task entities.Task) bool {
+		return task.Position == 2
+	}), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, subtasks[2].ID, mock.MatchedBy(func(task entities.Task) bool {
+		return task.Position == 1
+	}), mock.Anything).Return(nil).Once()
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, parent.ID, mock.Anything, parent.Version).Return(nil).Once()
+
+	service := &taskService{
+		config:            config.Config{},
+		repository:        taskRepositoryMock,
+		historyRepository: newTaskHistoryRepositoryMock(t),
+		writer:            NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), newOutboxRepositoryMock(t), newTransactionManagerMock(t)),
+		transactions:      newTransactionManagerMock(t),
+	}
+
+	// Act
+	newVersion, err := service.ReorderSubtasks(context.Background(), "user-123", parent.ID, parent.Version, models.ReorderSubtasksReq{IDs: []string{subtasks[0].ID, subtasks[2].ID, subtasks[1].ID}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, parent.Version+1, newVersion)
+}
+
+// TestReorderSubtasks_InvalidIDs checks that ReorderSubtasks returns a validation error when the IDs are not exactly the subtasks of the task
+func TestReorderSubtasks_InvalidIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+	}{
+		{"missing subtask", []string{"subtask-1"}},
+		{"duplicated subtask", []string{"subtask-1", "subtask-1"}},
+		{"unknown subtask", []string{"subtask-1", "subtask-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			parent := entities.Task{ID: "parent-id", UserID: "user-123"}
+			subtasks := []entities.Task{
+				{ID: "subtask-1", UserID: "user-123", ParentID: parent.ID, Position: 0},
+				{ID: "subtask-2", UserID: "user-123", ParentID: parent.ID, Position: 1},
+			}
+
+			taskRepositoryMock := mocks.NewTaskRepository(t)
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.GetByID), mock.Anything, parent.ID).Return(&parent, nil).Once()
+			taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]interface{}{&subtasks[0], &subtasks[1]}, nil).Once()
+
+			service := &taskService{
+				config:     config.Config{},
+				repository: taskRepositoryMock,
+			}
+
+			// Act
+			_, err := service.ReorderSubtasks(context.Background(), "user-123", parent.ID, parent.Version, models.ReorderSubtasksReq{IDs: tt.ids})
+
+			// Assert
+			assert.NotEmpty(t, err)
+			assert.IsType(t, wrappers.ValidationErr, err)
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// webhookEvents are the events a webhook can subscribe to
+var webhookEvents = []string{entities.WebhookEventTaskCreated, entities.WebhookEventTaskUpdated, entities.WebhookEventTaskDeleted}
+
+const minWebhookSecretLength = 16
+
+// headers of the webhook deliveries
+const (
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// webhookService adapter of a webhook service
+type webhookService struct {
+	config             config.Config
+	repository         ports.WebhookRepository
+	deliveryRepository ports.WebhookDeliveryRepository
+	client             ports.WebhookClient
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(cfg config.Config, repo ports.WebhookRepository, deliveryRepo ports.WebhookDeliveryRepository, client ports.WebhookClient) ports.WebhookService {
+	return &webhookService{
+		config:             cfg,
+		repository:         repo,
+		deliveryRepository: deliveryRepo,
+		client:             client,
+	}
+}
+
+// Create registers a webhook of the received userID, generating the secret its deliveries are signed with when not received
+func (w *webhookService) Create(ctx context.Context, userID string, webhook models.CreateWebhookReq) (resp models.CreateWebhookResp, err error) {
+	err = validateWebhook(webhook)
+	if err != nil {
+		return
+	}
+
+	// the client checks the target again on every delivery, as the host may resolve to another address by then
+	err = w.client.CheckURL(ctx, webhook.URL)
+	if err != nil {
+		return
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret, err = newWebhookSecret()
+		if err != nil {
+			return
+		}
+	}
+
+	id, err := w.repository.Create(ctx, entities.Webhook{
+		UserID:    userID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(webhook.Events))),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.CreateWebhookResp{
+		ID:     id,
+		Secret: webhook.Secret,
+	}
+	return
+}
+
+// GetByUserID gets the webhooks of the received userID, without their secrets
+func (w *webhookService) GetByUserID(ctx context.Context, userID string) (resp []models.GetWebhookResp, err error) {
+	resp = []models.GetWebhookResp{}
+
+	result, err := w.repository.Get(ctx, map[string]interface{}{"user_id": userID}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	resp = make([]models.GetWebhookResp, len(result))
+	for i, v := range result {
+		webhook := v.(*entities.Webhook)
+		resp[i] = models.GetWebhookResp{
+			ID:        webhook.ID,
+			URL:       webhook.URL,
+			Events:    webhook.Events,
+			CreatedAt: webhook.CreatedAt,
+		}
+	}
+	return
+}
+
+// Delete removes a webhook of the received userID together with its pending and dead deliveries
+func (w *webhookService) Delete(ctx context.Context, userID, webhookID string) (err error) {
+	_, err = w.getOwnedWebhook(ctx, userID, webhookID, "delete")
+	if err != nil {
+		return
+	}
+
+	err = w.repository.Delete(ctx, webhookID)
+	if err != nil {
+		return
+	}
+
+	err = w.deliveryRepository.DeleteByWebhookID(ctx, webhookID)
+	return
+}
+
+// GetDeadLetters gets the deliveries of a webhook of the received userID that ran out of attempts
+func (w *webhookService) GetDeadLetters(ctx context.Context, userID, webhookID string) (resp []models.GetWebhookDeliveryResp, err error) {
+	resp = []models.GetWebhookDeliveryResp{}
+	_, err = w.getOwnedWebhook(ctx, userID, webhookID, "get the dead letters of")
+	if err != nil {
+		return
+	}
+
+	result, err := w.deliveryRepository.Get(ctx, map[string]interface{}{"webhook_id": webhookID, "status": entities.WebhookDeliveryStatusDead}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return
+	}
+
+	resp = make([]models.GetWebhookDeliveryResp, len(result))
+	for i, v := range result {
+		delivery := v.(*entities.WebhookDelivery)
+		resp[i] = models.GetWebhookDeliveryResp{
+			ID:             delivery.ID,
+			WebhookID:      delivery.WebhookID,
+			Event:          delivery.Event,
+			Payload:        delivery.Payload,
+			Attempts:       delivery.Attempts,
+			LastStatusCode: delivery.LastStatusCode,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+		}
+	}
+	return
+}
+
+// Redeliver gives a dead delivery of a webhook of the received userID a new round of attempts, the first one as soon as possible
+func (w *webhookService) Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (err error) {
+	_, err = w.getOwnedWebhook(ctx, userID, webhookID, "redeliver")
+	if err != nil {
+		return
+	}
+
+	result, err := w.deliveryRepository.GetByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("DeliveryID %s not found", deliveryID))
+		}
+		return
+	}
+
+	delivery := result.(*entities.WebhookDelivery)
+	if delivery.WebhookID != webhookID {
+		return wrappers.NewNonExistentErr(fmt.Errorf("DeliveryID %s not found in WebhookID %s", deliveryID, webhookID))
+	}
+	if delivery.Status != entities.WebhookDeliveryStatusDead {
+		return wrappers.NewValidationErr(fmt.Errorf("DeliveryID %s is not dead", deliveryID))
+	}
+
+	delivery.ID = ""
+	delivery.Status = entities.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	err = w.deliveryRepository.Update(ctx, deliveryID, *delivery)
+	return
+}
+
+// Publish queues a delivery of the received event for each webhook of the owner of the task subscribed to it.
+// A webhook gets a single delivery of an event, even when the event is published again
+func (w *webhookService) Publish(ctx context.Context, event models.TaskEvent) error {
+	result, err := w.repository.Get(ctx, map[string]interface{}{"user_id": event.UserID, "events": event.Type}, nil, nil)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = nil
+		}
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, v := range result {
+		webhook := v.(*entities.Webhook)
+		err = w.deliveryRepository.Enqueue(ctx, entities.WebhookDelivery{
+			EventID:       event.ID,
+			WebhookID:     webhook.ID,
+			UserID:        webhook.UserID,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        entities.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts up to the configured batch size of due deliveries, one at a time. A failed attempt is retried with exponential backoff,
+// until the delivery runs out of attempts and is kept as a dead letter. It returns the number of deliveries attempted
+func (w *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	for i := 0; i < w.config.Webhooks.BatchSize; i++ {
+		// the lease outlasts the attempt, so a delivery is only claimed again if the process stops in the middle of it
+		now := time.Now().UTC()
+		result, err := w.deliveryRepository.ClaimDue(ctx, now, now.Add(2*w.config.Webhooks.Timeout.Duration))
+		if err != nil {
+			if errors.Is(err, wrappers.NonExistentErr) {
+				err = nil
+			}
+			return i, err
+		}
+
+		err = w.deliver(ctx, result.(*entities.WebhookDelivery))
+		if err != nil {
+			return i + 1, err
+		}
+	}
+	return w.config.Webhooks.BatchSize, nil
+}
+
+// deliver attempts a delivery, removing it once delivered or scheduling its next attempt otherwise.
+// The deliveries of webhooks deleted meanwhile are dropped
+func (w *webhookService) deliver(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	result, err := w.repository.GetByID(ctx, delivery.WebhookID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return w.deliveryRepository.Delete(ctx, delivery.ID)
+	}
+	if err != nil {
+		return err
+	}
+	webhook := result.(*entities.Webhook)
+
+	sendCtx, cancel := context.WithTimeout(ctx, w.config.Webhooks.Timeout.Duration)
+	defer cancel()
+
+	now := time.Now().UTC()
+	statusCode, err := w.client.Send(sendCtx, webhook.URL, map[string]string{
+		"Content-Type":         "application/json",
+		webhookEventHeader:     delivery.Event,
+		webhookDeliveryHeader:  delivery.ID,
+		webhookSignatureHeader: signWebhookPayload(webhook.Secret, now, delivery.Payload),
+	}, delivery.Payload)
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		return w.deliveryRepository.Delete(ctx, delivery.ID)
+	}
+
+	id := delivery.ID
+	delivery.ID = ""
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	if err != nil {
+		delivery.LastError = err.Error()
+	}
+	if delivery.Attempts >= w.config.Webhooks.MaxAttempts {
+		delivery.Status = entities.WebhookDeliveryStatusDead
+	} else {
+		delivery.NextAttemptAt = now.Add(webhookBackoff(w.config.Webhooks, delivery.Attempts))
+	}
+	return w.deliveryRepository.Update(ctx, id, *delivery)
+}
+
+// getOwnedWebhook gets a webhook and checks that it belongs to the received userID
+func (w *webhookService) getOwnedWebhook(ctx context.Context, userID, webhookID, action string) (*entities.Webhook, error) {
+	result, err := w.repository.GetByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("WebhookID %s not found", webhookID))
+		}
+		return nil, err
+	}
+
+	webhook := result.(*entities.Webhook)
+	if webhook.UserID != userID {
+		return nil, wrappers.NewUnauthenticatedErr(fmt.Errorf("UserID %s not allowed to %s WebhookID %s", userID, action, webhookID))
+	}
+	return webhook, nil
+}
+
+// validateWebhook checks that the URL of a webhook is an absolute HTTP URL, that it subscribes to known events only
+// and that its secret, if received, is long enough
+func validateWebhook(webhook models.CreateWebhookReq) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("webhook url %s must be an absolute http or https URL", webhook.URL))
+	}
+
+	if len(webhook.Events) == 0 {
+		return wrappers.NewValidationErr(errors.New("webhook events cannot be empty"))
+	}
+	for _, event := range webhook.Events {
+		if !slices.Contains(webhookEvents, event) {
+			return wrappers.NewValidationErr(fmt.Errorf("webhook event %s not valid, allowed values: %v", event, webhookEvents))
+		}
+	}
+
+	if webhook.Secret != "" && len(webhook.Secret) < minWebhookSecretLength {
+		return wrappers.NewValidationErr(fmt.Errorf("webhook secret must be at least %d characters long", minWebhookSecretLength))
+	}
+	return nil
+}
+
+// newWebhookSecret generates a random secret to sign the deliveries of a webhook with
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// signWebhookPayload gets the signature header of a delivery: the time of the attempt and the HMAC-SHA256 of the time and the payload,
+// so that receivers can check both the origin and the freshness of the delivery
+func signWebhookPayload(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff gets the delay before the next attempt of a delivery after the received number of failed attempts,
+// doubling the configured base delay after each attempt up to the configured maximum
+func webhookBackoff(cfg config.Webhooks, attempts int) time.Duration {
+	delay := cfg.BaseDelay.Duration
+	for i := 1; i < attempts && delay < cfg.MaxDelay.Duration; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxDelay.Duration)
+}
@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/config"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newWebhookConfig creates a config retrying the deliveries up to 3 times, one minute after the first failed attempt
+func newWebhookConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Webhooks.BatchSize = 10
+	cfg.Webhooks.MaxAttempts = 3
+	cfg.Webhooks.Timeout.Duration = time.Second
+	cfg.Webhooks.BaseDelay.Duration = time.Minute
+	cfg.Webhooks.MaxDelay.Duration = time.Hour
+	return cfg
+}
+
+// TestCreateWebhook_Ok checks that Create stores a webhook with a generated secret and its events without duplicates
+func TestCreateWebhook_Ok(t *testing.T) {
+	// Arrange
+	userID := "user-123"
+	req := models.CreateWebhookReq{URL: "https://example.com/hook", Events: []string{entities.WebhookEventTaskUpdated, entities.WebhookEventTaskCreated, entities.WebhookEventTaskUpdated}}
+
+	var secret string
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.Create), mock.Anything, mock.MatchedBy(func(w entities.Webhook) bool {
+		secret = w.Secret
+		return w.UserID == userID && w.URL == req.URL && len(w.Secret) == 64 &&
+			assert.ObjectsAreEqual([]string{entities.WebhookEventTaskCreated, entities.WebhookEventTaskUpdated}, w.Events)
+	})).Return("webhook-1", nil).Once()
+
+	webhookClientMock := mocks.NewWebhookClient(t)
+	webhookClientMock.On(testutils.FunctionName(t, ports.WebhookClient.CheckURL), mock.Anything, req.URL).Return(nil).Once()
+
+	service := &webhookService{
+		config:     newWebhookConfig(),
+		repository: webhookRepositoryMock,
+		client:     webhookClientMock,
+	}
+
+	// Act
+	resp, err := service.Create(context.Background(), userID, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CreateWebhookResp{ID: "webhook-1", Secret: secret}, resp)
+}
+
+// TestCreateWebhook_InvalidRequest checks that Create returns a validation error when the received webhook is not valid
+func TestCreateWebhook_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.CreateWebhookReq
+	}{
+		{name: "relative url", req: models.CreateWebhookReq{URL: "/hook", Events: []string{entities.WebhookEventTaskCreated}}},
+		{name: "invalid scheme", req: models.CreateWebhookReq{URL: "ftp://example.com/hook", Events: []string{entities.WebhookEventTaskCreated}}},
+		{name: "no events", req: models.CreateWebhookReq{URL: "https://example.com/hook"}},
+		{name: "unknown event", req: models.CreateWebhookReq{URL: "https://example.com/hook", Events: []string{"task.moved"}}},
+		{name: "short secret", req: models.CreateWebhookReq{URL: "https://example.com/hook", Events: []string{entities.WebhookEventTaskCreated}, Secret: "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &webhookService{
+				config: newWebhookConfig(),
+			}
+
+			// Act
+			_, err := service.Create(context.Background(), "user-123", tt.req)
+
+			// Assert
+			assert.True(t, errors.Is(err, wrappers.ValidationErr))
+		})
+	}
+}
+
+// TestCreateWebhook_URLNotAllowed checks that Create returns the error of the client and stores nothing when the target is not allowed
+func TestCreateWebhook_URLNotAllowed(t *testing.T) {
+	// Arrange
+	req := models.CreateWebhookReq{URL: "http://169.254.169.254/latest/meta-data", Events: []string{entities.WebhookEventTaskCreated}}
+
+	webhookClientMock := mocks.NewWebhookClient(t)
+	webhookClientMock.On(testutils.FunctionName(t, ports.WebhookClient.CheckURL), mock.Anything, req.URL).Return(wrappers.NewValidationErr(errors.New("not allowed"))).Once()
+
+	service := &webhookService{
+		config:     newWebhookConfig(),
+		repository: mocks.NewWebhookRepository(t),
+		client:     webhookClientMock,
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), "user-123", req)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestDeleteWebhook_Ok checks that Delete removes the webhook together with its deliveries
+func TestDeleteWebhook_Ok(t *testing.T) {
+	// Arrange
+	webhook := entities.Webhook{ID: "webhook-1", UserID: "user-123"}
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.Delete), mock.Anything, webhook.ID).Return(nil).Once()
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.DeleteByWebhookID), mock.Anything, webhook.ID).Return(nil).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), webhook.UserID, webhook.ID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteWebhook_InvalidUser checks that Delete returns an error when the webhook does not belong to the received userID
+func TestDeleteWebhook_InvalidUser(t *testing.T) {
+	// Arrange
+	webhook := entities.Webhook{ID: "webhook-1", UserID: "user-456"}
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+	service := &webhookService{
+		config:     newWebhookConfig(),
+		repository: webhookRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "user-123", webhook.ID)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.UnauthenticatedErr))
+}
+
+// TestGetDeadLetters_Ok checks that GetDeadLetters returns the dead deliveries of the webhook
+func TestGetDeadLetters_Ok(t *testing.T) {
+	// Arrange
+	webhook := entities.Webhook{ID: "webhook-1", UserID: "user-123"}
+	delivery := entities.WebhookDelivery{ID: "delivery-1", WebhookID: webhook.ID, Event: entities.WebhookEventTaskCreated, Payload: []byte(`{"id":"history-id"}`), Status: entities.WebhookDeliveryStatusDead, Attempts: 3, LastStatusCode: http.StatusBadGateway}
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Get), mock.Anything, map[string]interface{}{"webhook_id": webhook.ID, "status": entities.WebhookDeliveryStatusDead}, mock.Anything, mock.Anything).Return([]interface{}{&delivery}, nil).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetDeadLetters(context.Background(), webhook.UserID, webhook.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, delivery.ID, resp[0].ID)
+	assert.Equal(t, `{"id":"history-id"}`, string(resp[0].Payload))
+	assert.Equal(t, 3, resp[0].Attempts)
+	assert.Equal(t, http.StatusBadGateway, resp[0].LastStatusCode)
+}
+
+// TestRedeliver_Ok checks that Redeliver makes a dead delivery pending again with a new round of attempts
+func TestRedeliver_Ok(t *testing.T) {
+	// Arrange
+	webhook := entities.Webhook{ID: "webhook-1", UserID: "user-123"}
+	delivery := entities.WebhookDelivery{ID: "delivery-1", WebhookID: webhook.ID, Status: entities.WebhookDeliveryStatusDead, Attempts: 3}
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.GetByID), mock.Anything, delivery.ID).Return(&delivery, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), mock.Anything, delivery.ID, mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+		return d.ID == "" && d.Status == entities.WebhookDeliveryStatusPending && d.Attempts == 0 && !d.NextAttemptAt.After(time.Now())
+	})).Return(nil).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.Redeliver(context.Background(), webhook.UserID, webhook.ID, delivery.ID)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRedeliver_InvalidDelivery checks that Redeliver returns an error when the delivery belongs to another webhook or is not dead
+func TestRedeliver_InvalidDelivery(t *testing.T) {
+	tests := []struct {
+		name        string
+		delivery    entities.WebhookDelivery
+		expectedErr error
+	}{
+		{
+			name:        "another webhook",
+			delivery:    entities.WebhookDelivery{ID: "delivery-1", WebhookID: "webhook-2", Status: entities.WebhookDeliveryStatusDead},
+			expectedErr: wrappers.NonExistentErr,
+		},
+		{
+			name:        "pending delivery",
+			delivery:    entities.WebhookDelivery{ID: "delivery-1", WebhookID: "webhook-1", Status: entities.WebhookDeliveryStatusPending},
+			expectedErr: wrappers.ValidationErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			webhook := entities.Webhook{ID: "webhook-1", UserID: "user-123"}
+
+			webhookRepositoryMock := mocks.NewWebhookRepository(t)
+			webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+			deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+			deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.GetByID), mock.Anything, tt.delivery.ID).Return(&tt.delivery, nil).Once()
+
+			service := &webhookService{
+				config:             newWebhookConfig(),
+				repository:         webhookRepositoryMock,
+				deliveryRepository: deliveryRepositoryMock,
+			}
+
+			// Act
+			err := service.Redeliver(context.Background(), webhook.UserID, webhook.ID, tt.delivery.ID)
+
+			// Assert
+			assert.True(t, errors.Is(err, tt.expectedErr))
+		})
+	}
+}
+
+// TestPublish_Ok checks that Publish queues a delivery of the event for each webhook of the owner subscribed to it
+func TestPublish_Ok(t *testing.T) {
+	// Arrange
+	event := models.TaskEvent{ID: "history-id", Type: entities.WebhookEventTaskCreated, TaskID: "task-1", UserID: "user-123"}
+	webhooks := []interface{}{
+		&entities.Webhook{ID: "webhook-1", UserID: event.UserID},
+		&entities.Webhook{ID: "webhook-2", UserID: event.UserID},
+	}
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.Get), mock.Anything, map[string]interface{}{"user_id": event.UserID, "events": event.Type}, mock.Anything, mock.Anything).Return(webhooks, nil).Once()
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	for _, id := range []string{"webhook-1", "webhook-2"} {
+		deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Enqueue), mock.Anything, mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+			return d.EventID == event.ID && d.WebhookID == id && d.Event == event.Type && d.Status == entities.WebhookDeliveryStatusPending &&
+				strings.Contains(string(d.Payload), `"id":"history-id"`)
+		})).Return(nil).Once()
+	}
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.Publish(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPublish_NoWebhooks checks that Publish does nothing when the owner has no webhook subscribed to the event
+func TestPublish_NoWebhooks(t *testing.T) {
+	// Arrange
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.Get), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: mocks.NewWebhookDeliveryRepository(t),
+	}
+
+	// Act
+	err := service.Publish(context.Background(), models.TaskEvent{Type: entities.WebhookEventTaskDeleted, UserID: "user-123"})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeliverDue_Delivered checks that DeliverDue sends the signed payload of the due deliveries and removes them once delivered
+func TestDeliverDue_Delivered(t *testing.T) {
+	// Arrange
+	webhook := entities.Webhook{ID: "webhook-1", URL: "https://example.com/hook", Secret: "0123456789abcdef"}
+	delivery := entities.WebhookDelivery{ID: "delivery-1", WebhookID: webhook.ID, Event: entities.WebhookEventTaskCreated, Payload: []byte(`{"id":"history-id"}`)}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(&delivery, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Delete), mock.Anything, delivery.ID).Return(nil).Once()
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+	clientMock := mocks.NewWebhookClient(t)
+	clientMock.On(testutils.FunctionName(t, ports.WebhookClient.Send), mock.Anything, webhook.URL, mock.MatchedBy(func(header map[string]string) bool {
+		timestamp, _, _ := strings.Cut(strings.TrimPrefix(header[webhookSignatureHeader], "t="), ",")
+		unix, _ := strconv.ParseInt(timestamp, 10, 64)
+		return header[webhookEventHeader] == delivery.Event && header[webhookDeliveryHeader] == delivery.ID &&
+			header[webhookSignatureHeader] == signWebhookPayload(webhook.Secret, time.Unix(unix, 0), delivery.Payload)
+	}), delivery.Payload).Return(http.StatusNoContent, nil).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+		client:             clientMock,
+	}
+
+	// Act
+	attempted, err := service.DeliverDue(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempted)
+}
+
+// TestDeliverDue_Failed checks that DeliverDue schedules the next attempt of a failed delivery with backoff, and keeps it as a dead letter
+// once it runs out of attempts
+func TestDeliverDue_Failed(t *testing.T) {
+	tests := []struct {
+		name           string
+		attempts       int
+		sendStatus     int
+		sendErr        error
+		expectedStatus string
+		expectedDelay  time.Duration
+	}{
+		{name: "first attempt", attempts: 0, sendStatus: http.StatusInternalServerError, expectedStatus: entities.WebhookDeliveryStatusPending, expectedDelay: time.Minute},
+		{name: "second attempt", attempts: 1, sendErr: errors.New("send-error"), expectedStatus: entities.WebhookDeliveryStatusPending, expectedDelay: 2 * time.Minute},
+		{name: "last attempt", attempts: 2, sendStatus: http.StatusGone, expectedStatus: entities.WebhookDeliveryStatusDead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			webhook := entities.Webhook{ID: "webhook-1", URL: "https://example.com/hook"}
+			delivery := entities.WebhookDelivery{ID: "delivery-1", WebhookID: webhook.ID, Status: entities.WebhookDeliveryStatusPending, Attempts: tt.attempts}
+			start := time.Now()
+
+			deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+			deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(&delivery, nil).Once()
+			deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+			deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), mock.Anything, "delivery-1", mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+				scheduled := tt.expectedDelay == 0 || (!d.NextAttemptAt.Before(start.Add(tt.expectedDelay)) && d.NextAttemptAt.Before(time.Now().Add(tt.expectedDelay)))
+				return d.Attempts == tt.attempts+1 && d.Status == tt.expectedStatus && d.LastStatusCode == tt.sendStatus && scheduled
+			})).Return(nil).Once()
+
+			webhookRepositoryMock := mocks.NewWebhookRepository(t)
+			webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, webhook.ID).Return(&webhook, nil).Once()
+
+			clientMock := mocks.NewWebhookClient(t)
+			clientMock.On(testutils.FunctionName(t, ports.WebhookClient.Send), mock.Anything, webhook.URL, mock.Anything, mock.Anything).Return(tt.sendStatus, tt.sendErr).Once()
+
+			service := &webhookService{
+				config:             newWebhookConfig(),
+				repository:         webhookRepositoryMock,
+				deliveryRepository: deliveryRepositoryMock,
+				client:             clientMock,
+			}
+
+			// Act
+			attempted, err := service.DeliverDue(context.Background())
+
+			// Assert
+			assert.Nil(t, err)
+			assert.Equal(t, 1, attempted)
+		})
+	}
+}
+
+// TestDeliverDue_WebhookDeleted checks that DeliverDue drops the deliveries of the webhooks deleted meanwhile
+func TestDeliverDue_WebhookDeleted(t *testing.T) {
+	// Arrange
+	delivery := entities.WebhookDelivery{ID: "delivery-1", WebhookID: "webhook-1"}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(&delivery, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.ClaimDue), mock.Anything, mock.Anything, mock.Anything).Return(nil, wrappers.NonExistentErr).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Delete), mock.Anything, delivery.ID).Return(nil).Once()
+
+	webhookRepositoryMock := mocks.NewWebhookRepository(t)
+	webhookRepositoryMock.On(testutils.FunctionName(t, ports.WebhookRepository.GetByID), mock.Anything, delivery.WebhookID).Return(nil, wrappers.NonExistentErr).Once()
+
+	service := &webhookService{
+		config:             newWebhookConfig(),
+		repository:         webhookRepositoryMock,
+		deliveryRepository: deliveryRepositoryMock,
+		client:             mocks.NewWebhookClient(t),
+	}
+
+	// Act
+	attempted, err := service.DeliverDue(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempted)
+}
+
+// TestSignWebhookPayload_Ok checks that signWebhookPayload signs the time and the payload with HMAC-SHA256
+func TestSignWebhookPayload_Ok(t *testing.T) {
+	// Arrange
+	at := time.Unix(1700000000, 0)
+
+	// Act
+	signature := signWebhookPayload("secret", at, []byte(`{"id":"history-id"}`))
+
+	// Assert
+	assert.Equal(t, "t=1700000000,v1=1eff290654c2f7e7648ad14fbef78185c4278c15fca5baed9397a91b4fb982f3", signature)
+}
+
+// TestWebhookBackoff_Ok checks that webhookBackoff doubles the base delay after each attempt up to the maximum delay
+func TestWebhookBackoff_Ok(t *testing.T) {
+	// Arrange
+	cfg := newWebhookConfig().Webhooks
+	expectedDelays := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 32 * time.Minute, time.Hour, time.Hour}
+
+	for i, expectedDelay := range expectedDelays {
+		// Act
+		delay := webhookBackoff(cfg, i+1)
+
+		// Assert
+		assert.Equal(t, expectedDelay, delay)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+)
+
+// TaskWriter persists the changes to existing tasks, recording each of them in the task history and writing its event to the outbox
+// in the same transaction. It is shared by all the services changing tasks
+type TaskWriter struct {
+	repository        ports.TaskRepository
+	historyRepository ports.TaskHistoryRepository
+	outboxRepository  ports.OutboxRepository
+	transactions      ports.TransactionManager
+}
+
+// NewTaskWriter creates a new task writer
+func NewTaskWriter(repo ports.TaskRepository, historyRepo ports.TaskHistoryRepository, outboxRepo ports.OutboxRepository, transactions ports.TransactionManager) *TaskWriter {
+	return &TaskWriter{
+		repository:        repo,
+		historyRepository: historyRepo,
+		outboxRepository:  outboxRepo,
+		transactions:      transactions,
+	}
+}
+
+// saveChanges persists all the fields of the received entity and records the changes made by the received actor since its previous version,
+// in a single transaction. The entity only gets its new version once committed, as the transaction may be run again
+func (w *TaskWriter) saveChanges(ctx context.Context, actorID string, before entities.Task, after *entities.Task) error {
+	var saved entities.Task
+	err := w.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		saved = *after
+		return w.saveTask(ctx, actorID, entities.TaskHistoryActionUpdated, before, &saved)
+	})
+	if err != nil {
+		return err
+	}
+
+	*after = saved
+	return nil
+}
+
+// saveTasks applies the received change to each of the received tasks and persists them, recording the changes made by the received actor
+// with the received action, in a single transaction. The received tasks are left untouched, as the transaction may be run again
+func (w *TaskWriter) saveTasks(ctx context.Context, actorID, action string, tasks []entities.Task, change func(task *entities.Task)) error {
+	return w.transactions.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, task := range tasks {
+			before := task
+			change(&task)
+			err := w.saveTask(ctx, actorID, action, before, &task)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// saveTask persists all the fields of the received entity and records the changes made by the received actor since its previous version
+// with the received action, together with their outbox event. Every write to an existing task goes through it, so that no change is stored
+// without its history and its event, and it is meant to be run in the transaction of the change
+func (w *TaskWriter) saveTask(ctx context.Context, actorID, action string, before entities.Task, after *entities.Task) error {
+	err := w.save(ctx, after)
+	if err != nil {
+		return err
+	}
+	return w.recordHistory(ctx, actorID, after.UserID, action, after.ID, diffTasks(before, *after))
+}
+
+// touch saves the received task with no other change than its update time, so that its version is incremented when a change
+// related to the task is stored elsewhere, and returns the new version
+func (w *TaskWriter) touch(ctx context.Context, actorID string, task entities.Task) (int, error) {
+	before := task
+	task.UpdatedAt = time.Now().UTC()
+	err := w.saveTask(ctx, actorID, entities.TaskHistoryActionUpdated, before, &task)
+	return task.Version, err
+}
+
+// save persists all the fields of the received entity, as long as nobody else has saved it since it was read, and increments its version
+func (w *TaskWriter) save(ctx context.Context, entity *entities.Task) error {
+	saved := *entity
+	saved.ID = ""
+
+	err := w.repository.UpdateIfVersion(ctx, entity.ID, saved, entity.Version)
+	if err != nil {
+		return err
+	}
+	entity.Version++
+	return nil
+}
+
+// recordHistory stores a history entry with the changes made to a task by the received actor, and writes them to the outbox
+// as an event of the owner of the task with the ID of the entry. Updates without changes are not recorded.
+// It is meant to be run in the same transaction as the change, so that the change is not stored without its event
+func (w *TaskWriter) recordHistory(ctx context.Context, actorID, ownerID, action, taskID string, changes []entities.FieldChange) error {
+	if action == entities.TaskHistoryActionUpdated && len(changes) == 0 {
+		return nil
+	}
+	if changes == nil {
+		changes = []entities.FieldChange{}
+	}
+
+	now := time.Now().UTC()
+	id, err := w.historyRepository.Create(ctx, entities.TaskHistory{
+		TaskID:    taskID,
+		ActorID:   actorID,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	eventChanges := make([]models.FieldChange, len(changes))
+	for i, change := range changes {
+		eventChanges[i] = models.FieldChange(change)
+	}
+	event, err := newOutboxEvent(models.TaskEvent{
+		ID:        id,
+		Type:      taskHistoryEvents[action],
+		TaskID:    taskID,
+		UserID:    ownerID,
+		ActorID:   actorID,
+		Changes:   eventChanges,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.outboxRepository.Create(ctx, event)
+	return err
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestSaveTasks_Ok checks that saveTasks saves every changed task with its history entry and event, leaving the received tasks untouched
+func TestSaveTasks_Ok(t *testing.T) {
+	// Arrange
+	tasks := []entities.Task{
+		{ID: "task-1", UserID: "user-123", Title: "title", Version: 1},
+		{ID: "task-2", UserID: "user-123", Title: "title", Version: 2},
+	}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	for _, task := range tasks {
+		taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+			return e.Title == "new title"
+		}), task.Version).Return(nil).Once()
+	}
+
+	historyRepositoryMock := mocks.NewTaskHistoryRepository(t)
+	historyRepositoryMock.On(testutils.FunctionName(t, ports.TaskHistoryRepository.Create), mock.Anything, mock.MatchedBy(func(e entities.TaskHistory) bool {
+		return e.ActorID == "user-456" && e.Action == entities.TaskHistoryActionUpdated && len(e.Changes) == 1 && e.Changes[0].Field == "title"
+	})).Return("history-id", nil).Twice()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Create), mock.Anything, mock.AnythingOfType("entities.OutboxEvent")).Return("event-id", nil).Twice()
+
+	writer := NewTaskWriter(taskRepositoryMock, historyRepositoryMock, outboxRepositoryMock, newTransactionManagerMock(t))
+
+	// Act
+	err := writer.saveTasks(context.Background(), "user-456", entities.TaskHistoryActionUpdated, tasks, func(task *entities.Task) {
+		task.Title = "new title"
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "title", tasks[0].Title)
+	assert.Equal(t, 1, tasks[0].Version)
+}
+
+// TestSaveChanges_OutboxError checks that saveChanges returns an error and keeps the version of the entity when the event cannot be written
+func TestSaveChanges_OutboxError(t *testing.T) {
+	// Arrange
+	before := entities.Task{ID: "task-id", UserID: "user-123", Title: "title", Version: 3}
+	after := before
+	after.Title = "new title"
+	expectedError := "outbox-error"
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, before.ID, mock.AnythingOfType("entities.Task"), before.Version).Return(nil).Once()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Create), mock.Anything, mock.AnythingOfType("entities.OutboxEvent")).Return("", errors.New(expectedError)).Once()
+
+	writer := NewTaskWriter(taskRepositoryMock, newTaskHistoryRepositoryMock(t), outboxRepositoryMock, newTransactionManagerMock(t))
+
+	// Act
+	err := writer.saveChanges(context.Background(), before.UserID, before, &after)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+	assert.Equal(t, before.Version, after.Version)
+}
+
+// TestTouch_Ok checks that touch saves the task with a new version and records no history
+func TestTouch_Ok(t *testing.T) {
+	// Arrange
+	task := entities.Task{ID: "task-id", UserID: "user-123", Version: 3}
+
+	taskRepositoryMock := mocks.NewTaskRepository(t)
+	taskRepositoryMock.On(testutils.FunctionName(t, ports.TaskRepository.UpdateIfVersion), mock.Anything, task.ID, mock.MatchedBy(func(e entities.Task) bool {
+		return !e.UpdatedAt.IsZero()
+	}), task.Version).Return(nil).Once()
+
+	writer := NewTaskWriter(taskRepositoryMock, mocks.NewTaskHistoryRepository(t), mocks.NewOutboxRepository(t), newTransactionManagerMock(t))
+
+	// Act
+	version, err := writer.touch(context.Background(), task.UserID, task)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, task.Version+1, version)
+}
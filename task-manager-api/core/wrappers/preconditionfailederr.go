@@ -0,0 +1,31 @@
+package wrappers
+
+// PreconditionFailedErr is an error of type preconditionFailedError with the underlying error message, for when a precondition of the request is not met by the current state of the resource
+var PreconditionFailedErr error = preconditionFailedError{msg: "precondition failed"}
+
+// preconditionFailedError is an implementation of error interface
+type preconditionFailedError struct {
+	msg string
+}
+
+// NewPreconditionFailedErr wraps the given error in a preconditionFailedError
+func NewPreconditionFailedErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return preconditionFailedError{
+		msg: err.Error(),
+	}
+}
+
+// Error returns the error message
+func (e preconditionFailedError) Error() string {
+	return e.msg
+}
+
+// Is returns true if the target error is a preconditionFailedError
+func (e preconditionFailedError) Is(tgt error) bool {
+	_, ok := tgt.(preconditionFailedError)
+	return ok
+}
@@ -0,0 +1,71 @@
+package wrappers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPreconditionFailedErr_Ok checks that NewPreconditionFailedErr returns the expected error type when receives an error
+func TestNewPreconditionFailedErr_Ok(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("test error")
+
+	// Act
+	gotErr := NewPreconditionFailedErr(err)
+
+	// Assert
+	assert.NotEmpty(t, gotErr)
+	assert.IsType(t, PreconditionFailedErr, gotErr)
+}
+
+// TestNewPreconditionFailedErr_NilErr checks that NewPreconditionFailedErr returns nil when receives a nil error
+func TestNewPreconditionFailedErr_NilErr(t *testing.T) {
+	// Arrange
+	var err error
+
+	// Act
+	gotErr := NewPreconditionFailedErr(err)
+
+	// Assert
+	assert.Nil(t, gotErr)
+}
+
+// TestPreconditionFailedErrError_Ok checks that Error returns the expected error message of the receiver
+func TestPreconditionFailedErrError_Ok(t *testing.T) {
+	// Arrange
+	expectedMsg := "test error"
+	err := NewPreconditionFailedErr(errors.New(expectedMsg))
+
+	// Act
+	gotMsg := err.Error()
+
+	// Assert
+	assert.Equal(t, expectedMsg, gotMsg)
+}
+
+// TestPreconditionFailedErrIs_True checks that Is returns true when the target is a preconditionFailedError
+func TestPreconditionFailedErrIs_True(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("wrapped: %w", NewPreconditionFailedErr(errors.New("test error")))
+
+	// Act
+	got := errors.Is(err, PreconditionFailedErr)
+
+	// Assert
+	assert.True(t, got)
+}
+
+// TestPreconditionFailedErrIs_False checks that Is returns false when the target is another error
+func TestPreconditionFailedErrIs_False(t *testing.T) {
+	// Arrange
+	err := NewPreconditionFailedErr(errors.New("test error"))
+
+	// Act
+	got := errors.Is(err, errors.New("test error"))
+
+	// Assert
+	assert.False(t, got)
+}
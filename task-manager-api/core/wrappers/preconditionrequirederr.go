@@ -0,0 +1,31 @@
+package wrappers
+
+// PreconditionRequiredErr is an error of type preconditionRequiredError with the underlying error message, for when the request is missing a precondition the resource requires to be modified
+var PreconditionRequiredErr error = preconditionRequiredError{msg: "precondition required"}
+
+// preconditionRequiredError is an implementation of error interface
+type preconditionRequiredError struct {
+	msg string
+}
+
+// NewPreconditionRequiredErr wraps the given error in a preconditionRequiredError
+func NewPreconditionRequiredErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return preconditionRequiredError{
+		msg: err.Error(),
+	}
+}
+
+// Error returns the error message
+func (e preconditionRequiredError) Error() string {
+	return e.msg
+}
+
+// Is returns true if the target error is a preconditionRequiredError
+func (e preconditionRequiredError) Is(tgt error) bool {
+	_, ok := tgt.(preconditionRequiredError)
+	return ok
+}
@@ -0,0 +1,71 @@
+package wrappers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPreconditionRequiredErr_Ok checks that NewPreconditionRequiredErr returns the expected error type when receives an error
+func TestNewPreconditionRequiredErr_Ok(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("test error")
+
+	// Act
+	gotErr := NewPreconditionRequiredErr(err)
+
+	// Assert
+	assert.NotEmpty(t, gotErr)
+	assert.IsType(t, PreconditionRequiredErr, gotErr)
+}
+
+// TestNewPreconditionRequiredErr_NilErr checks that NewPreconditionRequiredErr returns nil when receives a nil error
+func TestNewPreconditionRequiredErr_NilErr(t *testing.T) {
+	// Arrange
+	var err error
+
+	// Act
+	gotErr := NewPreconditionRequiredErr(err)
+
+	// Assert
+	assert.Nil(t, gotErr)
+}
+
+// TestPreconditionRequiredErrError_Ok checks that Error returns the expected error message of the receiver
+func TestPreconditionRequiredErrError_Ok(t *testing.T) {
+	// Arrange
+	expectedMsg := "test error"
+	err := NewPreconditionRequiredErr(errors.New(expectedMsg))
+
+	// Act
+	gotMsg := err.Error()
+
+	// Assert
+	assert.Equal(t, expectedMsg, gotMsg)
+}
+
+// TestPreconditionRequiredErrIs_True checks that Is returns true when the target is a preconditionRequiredError
+func TestPreconditionRequiredErrIs_True(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("wrapped: %w", NewPreconditionRequiredErr(errors.New("test error")))
+
+	// Act
+	got := errors.Is(err, PreconditionRequiredErr)
+
+	// Assert
+	assert.True(t, got)
+}
+
+// TestPreconditionRequiredErrIs_False checks that Is returns false when the target is another error
+func TestPreconditionRequiredErrIs_False(t *testing.T) {
+	// Arrange
+	err := NewPreconditionRequiredErr(errors.New("test error"))
+
+	// Act
+	got := errors.Is(err, errors.New("test error"))
+
+	// Assert
+	assert.False(t, got)
+}
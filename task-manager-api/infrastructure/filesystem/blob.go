@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// localBlobStore adapter of a blob store for the local filesystem, where every blob is a file named after its key inside the root directory.
+type localBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore creates a blob store for the local filesystem, creating its root directory when it does not exist
+func NewLocalBlobStore(root string) (ports.BlobStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &localBlobStore{
+		root: root,
+	}, nil
+}
+
+// Put writes the received content to a temporary file that is only renamed to its key once fully written, and returns the number of bytes stored
+func (s *localBlobStore) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return n, os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file of a blob
+func (s *localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, wrappers.NewNonExistentErr(fmt.Errorf("blob %s not found", key))
+	}
+	return file, err
+}
+
+// Delete removes the file of a blob
+func (s *localBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return wrappers.NewNonExistentErr(fmt.Errorf("blob %s not found", key))
+	}
+	return err
+}
+
+// path resolves the file of a blob, rejecting keys that would escape the root directory
+func (s *localBlobStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", wrappers.NewValidationErr(fmt.Errorf("blob key %q not valid", key))
+	}
+	return filepath.Join(s.root, key), nil
+}
@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewLocalBlobStore_Ok checks that NewLocalBlobStore creates the root directory when it does not exist
+func TestNewLocalBlobStore_Ok(t *testing.T) {
+	// Arrange
+	root := filepath.Join(t.TempDir(), "blobs")
+
+	// Act
+	store, err := NewLocalBlobStore(root)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, store)
+	assert.DirExists(t, root)
+}
+
+// TestPutGetDelete_Ok checks that a blob can be stored, read back and deleted
+func TestPutGetDelete_Ok(t *testing.T) {
+	// Arrange
+	store, _ := NewLocalBlobStore(t.TempDir())
+	content := "blob content"
+
+	// Act
+	n, putErr := store.Put(context.Background(), "blob-key", strings.NewReader(content))
+	reader, getErr := store.Get(context.Background(), "blob-key")
+	stored, _ := io.ReadAll(reader)
+	reader.Close()
+	deleteErr := store.Delete(context.Background(), "blob-key")
+
+	// Assert
+	assert.Nil(t, putErr)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Nil(t, getErr)
+	assert.Equal(t, content, string(stored))
+	assert.Nil(t, deleteErr)
+}
+
+// TestPut_LeavesNoTemporaryFiles checks that Put does not leave any partial file behind when the content cannot be read
+func TestPut_LeavesNoTemporaryFiles(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	store, _ := NewLocalBlobStore(root)
+	content := io.MultiReader(strings.NewReader("partial"), errReader{})
+
+	// Act
+	_, err := store.Put(context.Background(), "blob-key", content)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	entries, _ := os.ReadDir(root)
+	assert.Empty(t, entries)
+}
+
+// TestGet_NotFound checks that Get returns a non existent error when the blob does not exist
+func TestGet_NotFound(t *testing.T) {
+	// Arrange
+	store, _ := NewLocalBlobStore(t.TempDir())
+
+	// Act
+	_, err := store.Get(context.Background(), "missing-key")
+
+	// Assert
+	assert.IsType(t, wrappers.NonExistentErr, err)
+}
+
+// TestDelete_InvalidKey checks that Delete returns a validation error when the key would escape the root directory
+func TestDelete_InvalidKey(t *testing.T) {
+	// Arrange
+	store, _ := NewLocalBlobStore(t.TempDir())
+
+	// Act
+	err := store.Delete(context.Background(), "../outside")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+)
+
+// logPublisher adapter of an event publisher writing the events to a logger, meant for the environments without consumers of the events
+type logPublisher struct {
+	logger *log.Logger
+}
+
+// NewLogPublisher creates an event publisher writing the events to the received logger
+func NewLogPublisher(logger *log.Logger) ports.EventPublisher {
+	return &logPublisher{
+		logger: logger,
+	}
+}
+
+// Publish writes the received event to the logger as JSON
+func (p *logPublisher) Publish(ctx context.Context, event models.TaskEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Printf("event %s published: %s", event.Type, payload)
+	return nil
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPublish_Ok checks that Publish writes the type and the payload of the event to the logger
+func TestPublish_Ok(t *testing.T) {
+	// Arrange
+	var buffer bytes.Buffer
+	publisher := NewLogPublisher(log.New(&buffer, "", 0))
+
+	// Act
+	err := publisher.Publish(context.Background(), models.TaskEvent{ID: "history-id", Type: "task.created", TaskID: "task-id"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, buffer.String(), "event task.created published: ")
+	assert.Contains(t, buffer.String(), `"id":"history-id"`)
+	assert.Contains(t, buffer.String(), `"task_id":"task-id"`)
+}
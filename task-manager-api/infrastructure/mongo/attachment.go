@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// attachmentRepository adapter of an attachment repository for mongo.
+type attachmentRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewAttachmentRepository creates an attachment repository for mongo
+func NewAttachmentRepository(ctx context.Context, db *mongo.Database) (ports.AttachmentRepository, error) {
+	r := &attachmentRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameAttachment),
+			Target:     entities.Attachment{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}},
+			},
+		},
+	)
+	return r, err
+}
+
+// FindByTaskIDs gets the attachments of the received tasks, the oldest first
+func (r *attachmentRepository) FindByTaskIDs(ctx context.Context, taskIDs []string) ([]interface{}, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cur, err := r.Collection.Find(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []entities.Attachment
+	if err := cur.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	if len(attachments) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(attachments))
+	for i := range attachments {
+		result[i] = &attachments[i]
+	}
+	return result, nil
+}
+
+// DeleteByTaskIDs deletes the attachments of the received tasks
+func (r *attachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+	return err
+}
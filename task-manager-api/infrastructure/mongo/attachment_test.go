@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewAttachmentRepository_Ok checks that NewAttachmentRepository creates a new attachmentRepository struct
+func TestNewAttachmentRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewAttachmentRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestFindAttachmentsByTaskIDs_Ok checks that FindByTaskIDs returns the expected attachments when the query succeeds
+func TestFindAttachmentsByTaskIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAttachmentRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{
+					{Key: "_id", Value: id},
+					{Key: "task_id", Value: "task-123"},
+					{Key: "file_name", Value: "notes.txt"},
+					{Key: "size", Value: int64(42)},
+					{Key: "blob_key", Value: "blob-key"},
+				},
+			),
+		)
+
+		// Act
+		result, err := repo.FindByTaskIDs(context.Background(), []string{"task-123"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, id.Hex(), result[0].(*entities.Attachment).ID)
+		assert.Equal(t, "blob-key", result[0].(*entities.Attachment).BlobKey)
+		assert.Equal(t, int64(42), result[0].(*entities.Attachment).Size)
+	})
+}
+
+// TestFindAttachmentsByTaskIDs_NoResults checks that FindByTaskIDs returns a non existent error when the tasks have no attachments
+func TestFindAttachmentsByTaskIDs_NoResults(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAttachmentRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.FindByTaskIDs(context.Background(), []string{"task-123"})
+
+		// Assert
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestDeleteAttachmentsByTaskIDs_Ok checks that DeleteByTaskIDs does not return an error when the deletion succeeds
+func TestDeleteAttachmentsByTaskIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestAttachmentRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		// Act
+		err := repo.DeleteByTaskIDs(context.Background(), []string{"task-1", "task-2"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+func newTestAttachmentRepository(mt *mtest.T) *attachmentRepository {
+	return &attachmentRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.Attachment{},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const blobBucketName = "blobs"
+
+// gridFSBlobStore adapter of a blob store for mongo GridFS, where every blob is a GridFS file whose ID is the blob key.
+type gridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFSBlobStore creates a blob store for mongo GridFS
+func NewGridFSBlobStore(db *mongo.Database) (ports.BlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(blobBucketName))
+	if err != nil {
+		return nil, err
+	}
+
+	return &gridFSBlobStore{
+		bucket: bucket,
+	}, nil
+}
+
+// Put uploads the received content as a new GridFS file and returns the number of bytes stored
+func (s *gridFSBlobStore) Put(ctx context.Context, key string, content io.Reader) (int64, error) {
+	stream, err := s.bucket.OpenUploadStreamWithID(key, key)
+	if err != nil {
+		return 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetWriteDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := io.Copy(stream, content)
+	if err != nil {
+		_ = stream.Abort()
+		return 0, err
+	}
+
+	return n, stream.Close()
+}
+
+// Get opens a download stream of a GridFS file
+func (s *gridFSBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	stream, err := s.bucket.OpenDownloadStream(key)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("blob %s not found", key))
+		}
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			_ = stream.Close()
+			return nil, err
+		}
+	}
+
+	return stream, nil
+}
+
+// Delete removes a GridFS file and its chunks
+func (s *gridFSBlobStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.DeleteContext(ctx, key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("blob %s not found", key))
+	}
+	return err
+}
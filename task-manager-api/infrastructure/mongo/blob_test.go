@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewGridFSBlobStore_Ok checks that NewGridFSBlobStore creates a new gridFSBlobStore struct
+func TestNewGridFSBlobStore_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		store, err := NewGridFSBlobStore(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, store)
+		assert.Nil(t, err)
+	})
+}
+
+// TestGetBlob_NotFound checks that Get returns a non existent error when the GridFS file does not exist
+func TestGetBlob_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		store, _ := NewGridFSBlobStore(mt.DB)
+		ns := mt.DB.Name() + "." + blobBucketName + ".files"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := store.Get(context.Background(), "missing-key")
+
+		// Assert
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taskCollaboratorRepository adapter of a task collaborator repository for mongo.
+type taskCollaboratorRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewTaskCollaboratorRepository creates a task collaborator repository for mongo
+func NewTaskCollaboratorRepository(ctx context.Context, db *mongo.Database) (ports.TaskCollaboratorRepository, error) {
+	r := &taskCollaboratorRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameTaskCollaborator),
+			Target:     entities.TaskCollaborator{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "user_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}},
+			},
+		},
+	)
+	return r, err
+}
+
+// DeleteByTaskIDs deletes the collaborators of the received tasks
+func (r *taskCollaboratorRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+	return err
+}
@@ -0,0 +1,57 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewTaskCollaboratorRepository_Ok checks that NewTaskCollaboratorRepository creates a new taskCollaboratorRepository struct
+func TestNewTaskCollaboratorRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewTaskCollaboratorRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestDeleteCollaboratorsByTaskIDs_Ok checks that DeleteByTaskIDs does not return an error when the deletion succeeds
+func TestDeleteCollaboratorsByTaskIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskCollaboratorRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		// Act
+		err := repo.DeleteByTaskIDs(context.Background(), []string{"task-1", "task-2"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+func newTestTaskCollaboratorRepository(mt *mtest.T) *taskCollaboratorRepository {
+	return &taskCollaboratorRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.TaskCollaborator{},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// commentRepository adapter of a comment repository for mongo.
+type commentRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewCommentRepository creates a comment repository for mongo
+func NewCommentRepository(ctx context.Context, db *mongo.Database) (ports.CommentRepository, error) {
+	r := &commentRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameComment),
+			Target:     entities.Comment{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+			},
+		},
+	)
+	return r, err
+}
+
+// Find gets a page of the comments of a task, the oldest first, using a keyset on the creation time and the _id
+func (r *commentRepository) Find(ctx context.Context, filter models.CommentFilter) ([]interface{}, error) {
+	query := bson.M{"task_id": filter.TaskID}
+	if filter.After != nil {
+		id, err := primitive.ObjectIDFromHex(filter.After.ID)
+		if err != nil {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("cursor not valid: %w", err))
+		}
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$gt": filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{"$gt": id}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(filter.Limit))
+
+	cur, err := r.Collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []entities.Comment
+	if err := cur.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	if len(comments) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(comments))
+	for i := range comments {
+		result[i] = &comments[i]
+	}
+	return result, nil
+}
+
+// DeleteByTaskIDs deletes the comments of the received tasks
+func (r *commentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+	return err
+}
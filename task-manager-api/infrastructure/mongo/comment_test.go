@@ -0,0 +1,135 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewCommentRepository_Ok checks that NewCommentRepository creates a new commentRepository struct
+func TestNewCommentRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewCommentRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestFindComments_Ok checks that Find returns the expected comments when the query succeeds
+func TestFindComments_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestCommentRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{
+					{Key: "_id", Value: id},
+					{Key: "task_id", Value: "task-123"},
+					{Key: "body", Value: "looks good"},
+				},
+			),
+		)
+		filter := models.CommentFilter{
+			TaskID: "task-123",
+			Limit:  10,
+			After:  &models.CommentCursor{ID: primitive.NewObjectID().Hex(), CreatedAt: time.Now().UTC()},
+		}
+
+		// Act
+		result, err := repo.Find(context.Background(), filter)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, id.Hex(), result[0].(*entities.Comment).ID)
+		assert.Equal(t, "looks good", result[0].(*entities.Comment).Body)
+	})
+}
+
+// TestFindComments_NoResults checks that Find returns a non existent error when the task has no comments
+func TestFindComments_NoResults(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestCommentRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Find(context.Background(), models.CommentFilter{TaskID: "task-123", Limit: 10})
+
+		// Assert
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestFindComments_InvalidCursor checks that Find returns a validation error when the cursor does not hold a valid ID
+func TestFindComments_InvalidCursor(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestCommentRepository(mt)
+		filter := models.CommentFilter{
+			TaskID: "task-123",
+			Limit:  10,
+			After:  &models.CommentCursor{ID: "invalid-id"},
+		}
+
+		// Act
+		_, err := repo.Find(context.Background(), filter)
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err)
+	})
+}
+
+// TestDeleteCommentsByTaskIDs_Ok checks that DeleteByTaskIDs does not return an error when the deletion succeeds
+func TestDeleteCommentsByTaskIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestCommentRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 3}})
+
+		// Act
+		err := repo.DeleteByTaskIDs(context.Background(), []string{"task-1", "task-2"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+func newTestCommentRepository(mt *mtest.T) *commentRepository {
+	return &commentRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.Comment{},
+		},
+	}
+}
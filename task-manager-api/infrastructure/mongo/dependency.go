@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taskDependencyRepository adapter of a task dependency repository for mongo.
+type taskDependencyRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewTaskDependencyRepository creates a task dependency repository for mongo
+func NewTaskDependencyRepository(ctx context.Context, db *mongo.Database) (ports.TaskDependencyRepository, error) {
+	r := &taskDependencyRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameTaskDependency),
+			Target:     entities.TaskDependency{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}},
+			},
+			{
+				Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "blocker_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{{Key: "blocker_id", Value: 1}},
+			},
+		},
+	)
+	return r, err
+}
+
+// DeleteByTaskIDs deletes the dependencies where any of the received tasks is either the blocked task or the blocker
+func (r *taskDependencyRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"task_id": bson.M{"$in": taskIDs}},
+		bson.M{"blocker_id": bson.M{"$in": taskIDs}},
+	}})
+	return err
+}
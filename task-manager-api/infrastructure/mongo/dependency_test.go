@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewTaskDependencyRepository_Ok checks that NewTaskDependencyRepository creates a new taskDependencyRepository struct
+func TestNewTaskDependencyRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewTaskDependencyRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestDeleteByTaskIDs_Ok checks that DeleteByTaskIDs does not return an error when the deletion succeeds
+func TestDeleteByTaskIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskDependencyRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		// Act
+		err := repo.DeleteByTaskIDs(context.Background(), []string{"task-1", "task-2"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestDeleteByTaskIDs_DeleteError checks that DeleteByTaskIDs returns an error when the deletion fails
+func TestDeleteByTaskIDs_DeleteError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskDependencyRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "delete-error"}))
+
+		// Act
+		err := repo.DeleteByTaskIDs(context.Background(), []string{"task-1"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+func newTestTaskDependencyRepository(mt *mtest.T) *taskDependencyRepository {
+	return &taskDependencyRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.TaskDependency{},
+		},
+	}
+}
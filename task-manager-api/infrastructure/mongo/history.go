@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taskHistoryRepository adapter of a task history repository for mongo.
+type taskHistoryRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewTaskHistoryRepository creates a task history repository for mongo
+func NewTaskHistoryRepository(ctx context.Context, db *mongo.Database) (ports.TaskHistoryRepository, error) {
+	r := &taskHistoryRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameTaskHistory),
+			Target:     entities.TaskHistory{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}},
+			},
+		},
+	)
+	return r, err
+}
+
+// FindByTaskID gets the history of a task, the oldest changes first
+func (r *taskHistoryRepository) FindByTaskID(ctx context.Context, taskID string) ([]interface{}, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cur, err := r.Collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entities.TaskHistory
+	if err := cur.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(entries))
+	for i := range entries {
+		result[i] = &entries[i]
+	}
+	return result, nil
+}
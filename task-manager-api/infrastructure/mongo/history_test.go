@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewTaskHistoryRepository_Ok checks that NewTaskHistoryRepository creates a new taskHistoryRepository struct
+func TestNewTaskHistoryRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewTaskHistoryRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestFindByTaskID_Ok checks that FindByTaskID returns the expected history entries when the query succeeds
+func TestFindByTaskID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskHistoryRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{
+					{Key: "_id", Value: id},
+					{Key: "task_id", Value: "task-123"},
+					{Key: "action", Value: entities.TaskHistoryActionUpdated},
+					{Key: "changes", Value: bson.A{bson.D{{Key: "field", Value: "title"}, {Key: "from", Value: "a"}, {Key: "to", Value: "b"}}}},
+				},
+			),
+		)
+		expectedChanges := []entities.FieldChange{{Field: "title", From: "a", To: "b"}}
+
+		// Act
+		result, err := repo.FindByTaskID(context.Background(), "task-123")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, id.Hex(), result[0].(*entities.TaskHistory).ID)
+		assert.Equal(t, expectedChanges, result[0].(*entities.TaskHistory).Changes)
+	})
+}
+
+// TestFindByTaskID_NoResults checks that FindByTaskID returns a non existent error when the task has no history
+func TestFindByTaskID_NoResults(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskHistoryRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.FindByTaskID(context.Background(), "task-123")
+
+		// Assert
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+func newTestTaskHistoryRepository(mt *mtest.T) *taskHistoryRepository {
+	return &taskHistoryRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.TaskHistory{},
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reserveAttempts is the number of times Reserve tries to take over an expired key that has not been removed yet
+const reserveAttempts = 3
+
+// idempotencyKeyRepository adapter of an idempotency key repository for mongo.
+type idempotencyKeyRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewIdempotencyKeyRepository creates an idempotency key repository for mongo, where the keys are removed once they expire
+func NewIdempotencyKeyRepository(ctx context.Context, db *mongo.Database) (ports.IdempotencyKeyRepository, error) {
+	r := &idempotencyKeyRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameIdempotencyKey),
+			Target:     entities.IdempotencyKey{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		},
+	)
+	return r, err
+}
+
+// Reserve stores the received key, returning its ID, unless the same key of the same user is already stored, in which case the stored one is returned.
+// The expired keys are taken over, as the TTL index only removes them periodically
+func (r *idempotencyKeyRepository) Reserve(ctx context.Context, key entities.IdempotencyKey) (string, *entities.IdempotencyKey, error) {
+	for i := 0; i < reserveAttempts; i++ {
+		id, err := r.Create(ctx, key)
+		if !mongo.IsDuplicateKeyError(err) {
+			return id, nil, err
+		}
+
+		var stored entities.IdempotencyKey
+		err = r.Collection.FindOne(ctx, bson.M{"user_id": key.UserID, "key": key.Key}).Decode(&stored)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if stored.ExpiresAt.After(time.Now()) {
+			return "", &stored, nil
+		}
+
+		storedID, err := primitive.ObjectIDFromHex(stored.ID)
+		if err != nil {
+			return "", nil, err
+		}
+		_, err = r.Collection.DeleteOne(ctx, bson.M{"_id": storedID, "expires_at": stored.ExpiresAt})
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return "", nil, fmt.Errorf("idempotency key %s could not be reserved after %d attempts", key.Key, reserveAttempts)
+}
@@ -0,0 +1,139 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewIdempotencyKeyRepository_Ok checks that NewIdempotencyKeyRepository creates a new idempotencyKeyRepository struct
+func TestNewIdempotencyKeyRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewIdempotencyKeyRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestReserve_Ok checks that Reserve stores a new key and returns its ID
+func TestReserve_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestIdempotencyKeyRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		id, stored, err := repo.Reserve(context.Background(), entities.IdempotencyKey{UserID: "user-123", Key: "key-1"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+		assert.Nil(t, stored)
+	})
+}
+
+// TestReserve_Stored checks that Reserve returns the stored key when the same key of the same user has not expired yet
+func TestReserve_Stored(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestIdempotencyKeyRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: id},
+				{Key: "request_hash", Value: "hash"},
+				{Key: "status_code", Value: 201},
+				{Key: "expires_at", Value: time.Now().Add(time.Hour)},
+			}),
+		)
+
+		// Act
+		newID, stored, err := repo.Reserve(context.Background(), entities.IdempotencyKey{UserID: "user-123", Key: "key-1"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Empty(t, newID)
+		assert.Equal(t, id.Hex(), stored.ID)
+		assert.Equal(t, "hash", stored.RequestHash)
+		assert.Equal(t, 201, stored.StatusCode)
+	})
+}
+
+// TestReserve_Expired checks that Reserve takes over a stored key that has expired but has not been removed yet
+func TestReserve_Expired(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestIdempotencyKeyRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "expires_at", Value: time.Now().Add(-time.Hour)},
+			}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}},
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		id, stored, err := repo.Reserve(context.Background(), entities.IdempotencyKey{UserID: "user-123", Key: "key-1"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+		assert.Nil(t, stored)
+	})
+}
+
+// TestReserve_Error checks that Reserve returns an error when the key cannot be stored
+func TestReserve_Error(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestIdempotencyKeyRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "insert-error"}))
+
+		// Act
+		id, stored, err := repo.Reserve(context.Background(), entities.IdempotencyKey{UserID: "user-123", Key: "key-1"})
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Empty(t, id)
+		assert.Nil(t, stored)
+	})
+}
+
+func newTestIdempotencyKeyRepository(mt *mtest.T) *idempotencyKeyRepository {
+	return &idempotencyKeyRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.IdempotencyKey{},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// labelRepository adapter of a label repository for mongo.
+type labelRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewLabelRepository creates a label repository for mongo
+func NewLabelRepository(ctx context.Context, db *mongo.Database) (ports.LabelRepository, error) {
+	r := &labelRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameLabel),
+			Target:     entities.Label{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	return r, err
+}
@@ -0,0 +1,58 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxRepository adapter of an outbox repository for mongo.
+type outboxRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewOutboxRepository creates an outbox repository for mongo
+func NewOutboxRepository(ctx context.Context, db *mongo.Database) (ports.OutboxRepository, error) {
+	r := &outboxRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameOutboxEvent),
+			Target:     entities.OutboxEvent{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "available_at", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+	)
+	return r, err
+}
+
+// ClaimNext gets the oldest available event, making it unavailable until the received leaseUntil so that no other process claims it meanwhile.
+// It returns a non existent error when no event is available
+func (r *outboxRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (interface{}, error) {
+	var event entities.OutboxEvent
+	err := r.Collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"available_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"available_at": leaseUntil}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, wrappers.NewNonExistentErr(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
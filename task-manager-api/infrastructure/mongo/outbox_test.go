@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewOutboxRepository_Ok checks that NewOutboxRepository creates a new outboxRepository struct
+func TestNewOutboxRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewOutboxRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestClaimNext_Ok checks that ClaimNext returns the claimed event
+func TestClaimNext_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOutboxRepository(mt)
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "type", Value: entities.WebhookEventTaskCreated},
+				{Key: "payload", Value: []byte(`{"id":"history-id"}`)},
+			}},
+		})
+		now := time.Now().UTC()
+
+		// Act
+		result, err := repo.ClaimNext(context.Background(), now, now.Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, id.Hex(), result.(*entities.OutboxEvent).ID)
+		assert.Equal(t, entities.WebhookEventTaskCreated, result.(*entities.OutboxEvent).Type)
+		assert.Equal(t, `{"id":"history-id"}`, string(result.(*entities.OutboxEvent).Payload))
+	})
+}
+
+// TestClaimNext_NoneAvailable checks that ClaimNext returns a non existent error when no event is available
+func TestClaimNext_NoneAvailable(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestOutboxRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		now := time.Now().UTC()
+
+		// Act
+		result, err := repo.ClaimNext(context.Background(), now, now.Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+func newTestOutboxRepository(mt *mtest.T) *outboxRepository {
+	return &outboxRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.OutboxEvent{},
+		},
+	}
+}
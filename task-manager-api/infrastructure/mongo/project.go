@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// projectRepository adapter of a project repository for mongo.
+type projectRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewProjectRepository creates a project repository for mongo
+func NewProjectRepository(ctx context.Context, db *mongo.Database) (ports.ProjectRepository, error) {
+	r := &projectRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameProject),
+			Target:     entities.Project{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	return r, err
+}
@@ -2,14 +2,25 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
 	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// versionIncrement is the update incrementing the version of the tasks, which every write to a task does
+var versionIncrement = bson.M{"version": 1}
+
 // taskRepository adapter of a task repository for mongo.
 type taskRepository struct {
 	infrastructure.MongoRepository
@@ -25,11 +36,776 @@ func NewTaskRepository(ctx context.Context, db *mongo.Database) (ports.TaskRepos
 		},
 	}
 
-	_, err := r.Collection.Indexes().CreateOne(
+	_, err := r.Collection.Indexes().CreateMany(
 		ctx,
-		mongo.IndexModel{
-			Keys: bson.D{{Key: "user_id", Value: 1}},
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "due_at", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "labels", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "position", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "rank", Value: 1}},
+			},
+			{
+				Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{"project_id": bson.M{"$gt": ""}}),
+			},
+			{
+				Keys:    bson.D{{Key: "assignee_id", Value: 1}, {Key: "due_at", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{"assignee_id": bson.M{"$gt": ""}}),
+			},
+			{
+				Keys:    bson.D{{Key: "due_at", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{"recurrence": bson.M{"$gt": ""}}),
+			},
+			{
+				Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$type": "date"}}),
+			},
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+				Options: options.Index().SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "description", Value: 1}}),
+			},
 		},
 	)
 	return r, err
 }
+
+// Find gets a page of the tasks matching the received filter, using a keyset on the sort field and the _id
+func (r *taskRepository) Find(ctx context.Context, filter models.TaskFilter) ([]interface{}, error) {
+	conditions := filterConditions(filter)
+	if filter.After != nil {
+		after, err := keysetCondition(filter)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, after)
+	}
+
+	direction := 1
+	if filter.SortDesc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: filter.SortBy, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(filter.Limit))
+
+	cur, err := r.Collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []entities.Task
+	if err := cur.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(tasks))
+	for i := range tasks {
+		result[i] = &tasks[i]
+	}
+	return result, nil
+}
+
+// FindByIDs gets the tasks with the received IDs
+func (r *taskRepository) FindByIDs(ctx context.Context, ids []string) ([]interface{}, error) {
+	objectIDs := make(bson.A, len(ids))
+	for i, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("TaskID %s not valid: %w", id, err))
+		}
+		objectIDs[i] = objectID
+	}
+
+	cur, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []entities.Task
+	if err := cur.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(tasks))
+	for i := range tasks {
+		result[i] = &tasks[i]
+	}
+	return result, nil
+}
+
+// Search gets the tasks matching the received filter and text, sorted by relevance
+func (r *taskRepository) Search(ctx context.Context, filter models.TaskFilter, text string) ([]interface{}, error) {
+	query := bson.M{
+		"$and":  filterConditions(filter),
+		"$text": bson.M{"$search": text},
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(filter.Limit))
+
+	cur, err := r.Collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []entities.TaskSearchResult
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(results))
+	for i := range results {
+		result[i] = &results[i]
+	}
+	return result, nil
+}
+
+// filterConditions builds the conditions matching the tasks of the received filter
+func filterConditions(filter models.TaskFilter) bson.A {
+	conditions := bson.A{bson.M{"user_id": filter.UserID}}
+	if filter.AssigneeID != "" {
+		conditions = bson.A{bson.M{"assignee_id": filter.AssigneeID}}
+	}
+	if len(filter.IDs) > 0 {
+		// IDs that are not valid cannot match any task, so they are left out
+		objectIDs := bson.A{}
+		for _, id := range filter.IDs {
+			if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+				objectIDs = append(objectIDs, objectID)
+			}
+		}
+		conditions = bson.A{bson.M{"_id": bson.M{"$in": objectIDs}}}
+	}
+	if filter.ProjectID != "" {
+		conditions = append(conditions, bson.M{"project_id": filter.ProjectID})
+	}
+	if filter.Deleted {
+		conditions = append(conditions, bson.M{"deleted_at": bson.M{"$ne": nil}})
+	} else {
+		conditions = append(conditions, bson.M{"deleted_at": nil})
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, bson.M{"status": bson.M{"$in": filter.Statuses}})
+	}
+
+	dueAt := bson.M{}
+	if filter.DueBefore != nil {
+		dueAt["$lt"] = *filter.DueBefore
+	}
+	if filter.DueAfter != nil {
+		dueAt["$gt"] = *filter.DueAfter
+	}
+	if len(dueAt) > 0 {
+		conditions = append(conditions, bson.M{"due_at": dueAt})
+	}
+
+	if len(filter.Labels) > 0 {
+		op := "$in"
+		if filter.AllLabels {
+			op = "$all"
+		}
+		conditions = append(conditions, bson.M{"labels": bson.M{op: filter.Labels}})
+	}
+	return conditions
+}
+
+// keysetCondition builds the condition matching the tasks placed after the cursor in the requested sort.
+// Null values are sorted before any other value by mongo, so they need specific conditions.
+func keysetCondition(filter models.TaskFilter) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(filter.After.ID)
+	if err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("cursor not valid: %w", err))
+	}
+
+	var value interface{}
+	switch filter.SortBy {
+	case "title":
+		value = filter.After.Title
+	case "rank":
+		value = filter.After.Rank
+	case "due_at":
+		if filter.After.DueAt != nil {
+			value = *filter.After.DueAt
+		}
+	case "deleted_at":
+		if filter.After.DeletedAt != nil {
+			value = *filter.After.DeletedAt
+		}
+	default:
+		value = filter.After.CreatedAt
+	}
+
+	op := "$gt"
+	if filter.SortDesc {
+		op = "$lt"
+	}
+
+	sameValue := bson.M{filter.SortBy: value, "_id": bson.M{op: id}}
+	if value == nil {
+		if filter.SortDesc {
+			return sameValue, nil
+		}
+		return bson.M{"$or": bson.A{sameValue, bson.M{filter.SortBy: bson.M{"$ne": nil}}}}, nil
+	}
+
+	or := bson.A{bson.M{filter.SortBy: bson.M{op: value}}, sameValue}
+	if filter.SortDesc {
+		or = append(or, bson.M{filter.SortBy: nil})
+	}
+	return bson.M{"$or": or}, nil
+}
+
+// CountByStatus counts the tasks of the received userID grouped by status
+func (r *taskRepository) CountByStatus(ctx context.Context, userID string) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "user_id", Value: userID}, {Key: "deleted_at", Value: nil}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$status"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+	}
+
+	cur, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(groups))
+	for _, g := range groups {
+		result[g.Status] = g.Count
+	}
+	return result, nil
+}
+
+// CountSubtasks counts the direct subtasks of each of the received parent tasks, and how many of them are done
+func (r *taskRepository) CountSubtasks(ctx context.Context, parentIDs []string) (map[string]models.SubtaskCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "parent_id", Value: bson.D{{Key: "$in", Value: parentIDs}}}, {Key: "deleted_at", Value: nil}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$parent_id"},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "done", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$eq", Value: bson.A{"$status", entities.TaskStatusDone}}}, 1, 0}}}}}},
+		}}},
+	}
+
+	cur, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		ParentID string `bson:"_id"`
+		Total    int    `bson:"total"`
+		Done     int    `bson:"done"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.SubtaskCount, len(groups))
+	for _, g := range groups {
+		result[g.ParentID] = models.SubtaskCount{Total: g.Total, Done: g.Done}
+	}
+	return result, nil
+}
+
+// CountByProject counts the tasks of each of the received projects, and how many of them are open and done
+func (r *taskRepository) CountByProject(ctx context.Context, projectIDs []string) (map[string]models.ProjectTaskCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "project_id", Value: bson.D{{Key: "$in", Value: projectIDs}}}, {Key: "deleted_at", Value: nil}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$project_id"},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "open", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$in", Value: bson.A{"$status", bson.A{entities.TaskStatusTodo, entities.TaskStatusInProgress, entities.TaskStatusBlocked}}}}, 1, 0}}}}}},
+			{Key: "done", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$eq", Value: bson.A{"$status", entities.TaskStatusDone}}}, 1, 0}}}}}},
+		}}},
+	}
+
+	cur, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		ProjectID string `bson:"_id"`
+		Total     int    `bson:"total"`
+		Open      int    `bson:"open"`
+		Done      int    `bson:"done"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.ProjectTaskCounts, len(groups))
+	for _, g := range groups {
+		result[g.ProjectID] = models.ProjectTaskCounts{Total: g.Total, Open: g.Open, Done: g.Done}
+	}
+	return result, nil
+}
+
+// FirstRank gets the lowest rank in a board column of the received userID, or an empty rank when the column is empty
+func (r *taskRepository) FirstRank(ctx context.Context, userID, status string) (string, error) {
+	return r.edgeRank(ctx, userID, status, 1)
+}
+
+// LastRank gets the highest rank in a board column of the received userID, or an empty rank when the column is empty
+func (r *taskRepository) LastRank(ctx context.Context, userID, status string) (string, error) {
+	return r.edgeRank(ctx, userID, status, -1)
+}
+
+// edgeRank gets the rank at the edge of a board column given by the received sort direction. The tasks in the trash
+// are taken into account, so that they do not clash with the ranks of the column when they are restored
+func (r *taskRepository) edgeRank(ctx context.Context, userID, status string, direction int) (string, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "rank", Value: direction}}).
+		SetProjection(bson.M{"rank": 1})
+
+	var task struct {
+		Rank string `bson:"rank"`
+	}
+	err := r.Collection.FindOne(ctx, bson.M{"user_id": userID, "status": status, "rank": bson.M{"$gt": ""}}, opts).Decode(&task)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	return task.Rank, err
+}
+
+// CountRank counts the tasks with the received rank in a board column of the received userID, including the ones in the trash
+func (r *taskRepository) CountRank(ctx context.Context, userID, status, rank string) (int, error) {
+	count, err := r.Collection.CountDocuments(ctx, bson.M{"user_id": userID, "status": status, "rank": rank})
+	return int(count), err
+}
+
+// FindRankColumns gets the board columns with unranked tasks or with ranks longer than the received maximum length
+func (r *taskRepository) FindRankColumns(ctx context.Context, maxLength, limit int) ([]models.TaskColumn, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "rank", Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}}},
+			bson.D{{Key: "$expr", Value: bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$strLenBytes", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$rank", ""}}}}}, maxLength}}}}},
+		}}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: bson.D{{Key: "user_id", Value: "$user_id"}, {Key: "status", Value: "$status"}}}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cur, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		Column models.TaskColumn `bson:"_id"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.TaskColumn, len(groups))
+	for i, g := range groups {
+		result[i] = g.Column
+	}
+	return result, nil
+}
+
+// FindColumn gets all the tasks in a board column of the received userID, including the ones in the trash, sorted by rank.
+// The unranked tasks go first, in creation order
+func (r *taskRepository) FindColumn(ctx context.Context, userID, status string) ([]interface{}, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+
+	cur, err := r.Collection.Find(ctx, bson.M{"user_id": userID, "status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []entities.Task
+	if err := cur.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(tasks))
+	for i := range tasks {
+		result[i] = &tasks[i]
+	}
+	return result, nil
+}
+
+// BulkWrite applies the received writes in a single bulk write, returning the result of each write in the order they were received.
+// It is meant to be run in a transaction that is rolled back as soon as any write fails, so that either all the writes are applied or none is,
+// and so that the versions checked cannot change before the writes are applied. A write with an invalid ID or to a task that is no longer
+// at the expected version is not sent to the database, and fails with a validation or precondition failed error
+func (r *taskRepository) BulkWrite(ctx context.Context, writes []models.TaskWrite) ([]models.TaskWriteResult, error) {
+	results := make([]models.TaskWriteResult, len(writes))
+	writeModels := make([][]mongo.WriteModel, len(writes))
+	guards := []taskGuard{}
+	for i, write := range writes {
+		taskModels, taskGuards, id, err := taskWriteModels(write)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].ID = id
+		writeModels[i] = taskModels
+		for _, guard := range taskGuards {
+			guard.index = i
+			guards = append(guards, guard)
+		}
+	}
+
+	err := r.checkGuards(ctx, guards, results)
+	if err != nil {
+		return nil, err
+	}
+
+	bulk := []mongo.WriteModel{}
+	indexes := []int{}
+	updates := 0
+	for i, taskModels := range writeModels {
+		if results[i].Err != nil {
+			continue
+		}
+		bulk = append(bulk, taskModels...)
+		for range taskModels {
+			indexes = append(indexes, i)
+		}
+		if writes[i].Op != models.BatchOpCreate {
+			updates += len(taskModels)
+		}
+	}
+	if len(bulk) == 0 {
+		return results, nil
+	}
+
+	result, err := r.Collection.BulkWrite(ctx, bulk, options.BulkWrite().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			results[indexes[writeErr.Index]].Err = writeErr
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a task changed between the check of the versions and the writes, which can only happen outside of a transaction
+	if result.MatchedCount < int64(updates) {
+		return nil, taskWrappers.NewPreconditionFailedErr(fmt.Errorf("%d of the tasks have been modified while being written", int64(updates)-result.MatchedCount))
+	}
+	return results, nil
+}
+
+// taskGuard struct with the version a write of a bulk write expects a task to be at
+type taskGuard struct {
+	index   int
+	id      primitive.ObjectID
+	version int
+}
+
+// checkGuards fails with a precondition failed error the result of each write expecting a task at a version other than its current one
+func (r *taskRepository) checkGuards(ctx context.Context, guards []taskGuard, results []models.TaskWriteResult) error {
+	if len(guards) == 0 {
+		return nil
+	}
+
+	objectIDs := make(bson.A, len(guards))
+	for i, guard := range guards {
+		objectIDs[i] = guard.id
+	}
+	cur, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}}, options.Find().SetProjection(bson.M{"version": 1}))
+	if err != nil {
+		return err
+	}
+
+	var tasks []struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Version int                `bson:"version"`
+	}
+	if err := cur.All(ctx, &tasks); err != nil {
+		return err
+	}
+	versions := make(map[primitive.ObjectID]int, len(tasks))
+	for _, task := range tasks {
+		versions[task.ID] = task.Version
+	}
+
+	for _, guard := range guards {
+		if results[guard.index].Err != nil {
+			continue
+		}
+		if version, ok := versions[guard.id]; !ok || version != guard.version {
+			results[guard.index].Err = taskWrappers.NewPreconditionFailedErr(fmt.Errorf("TaskID %s has been modified since version %d", guard.id.Hex(), guard.version))
+		}
+	}
+	return nil
+}
+
+// taskWriteModels builds the models of a write of a bulk write, one for each task it writes, along with the versions
+// the tasks are expected to be at and the ID of the task it writes
+func taskWriteModels(write models.TaskWrite) ([]mongo.WriteModel, []taskGuard, string, error) {
+	if write.Op == models.BatchOpCreate {
+		id := primitive.NewObjectID()
+		document, err := taskDocument(write.Task)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		document["_id"] = id
+		return []mongo.WriteModel{mongo.NewInsertOneModel().SetDocument(document)}, nil, id.Hex(), nil
+	}
+	if len(write.IDs) == 0 || len(write.IDs) != len(write.Versions) {
+		return nil, nil, "", wrappers.NewValidationErr(fmt.Errorf("a %s write needs the version of each of its tasks", write.Op))
+	}
+
+	guards := make([]taskGuard, len(write.IDs))
+	for i, id := range write.IDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, nil, "", wrappers.NewValidationErr(fmt.Errorf("TaskID %s not valid: %w", id, err))
+		}
+		guards[i] = taskGuard{id: objectID, version: write.Versions[i]}
+	}
+
+	var update bson.M
+	if write.Op == models.BatchOpDelete {
+		update = bson.M{"$set": bson.M{"deleted_at": write.DeletedAt, "updated_at": time.Now().UTC()}, "$inc": versionIncrement}
+	} else {
+		document, err := taskDocument(write.Task)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		delete(document, "version")
+		update = bson.M{"$set": document, "$inc": versionIncrement}
+		guards = guards[:1]
+	}
+
+	writeModels := make([]mongo.WriteModel, len(guards))
+	for i, guard := range guards {
+		writeModels[i] = mongo.NewUpdateOneModel().SetFilter(versionFilter(guard.id, guard.version)).SetUpdate(update)
+	}
+	return writeModels, guards, write.IDs[0], nil
+}
+
+// taskDocument marshals a task into a document without its _id
+func taskDocument(task interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bson.M
+	if err := bson.Unmarshal(raw, &document); err != nil {
+		return nil, err
+	}
+	delete(document, "_id")
+	return document, nil
+}
+
+// FindPendingOccurrences gets the recurring tasks due before the received time whose next occurrence has not been created yet,
+// leaving out the tasks with the received IDs
+func (r *taskRepository) FindPendingOccurrences(ctx context.Context, dueBefore time.Time, excludedIDs []string, limit int) ([]interface{}, error) {
+	query := bson.M{
+		"recurrence":         bson.M{"$gt": ""},
+		"next_occurrence_id": bson.M{"$exists": false},
+		"status":             bson.M{"$ne": entities.TaskStatusArchived},
+		"due_at":             bson.M{"$lte": dueBefore},
+		"deleted_at":         nil,
+	}
+	if len(excludedIDs) > 0 {
+		// IDs that are not valid cannot match any task, so there is no need to exclude them
+		objectIDs := bson.A{}
+		for _, id := range excludedIDs {
+			if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+				objectIDs = append(objectIDs, objectID)
+			}
+		}
+		query["_id"] = bson.M{"$nin": objectIDs}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "due_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cur, err := r.Collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []entities.Task
+	if err := cur.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	if len(tasks) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	result := make([]interface{}, len(tasks))
+	for i := range tasks {
+		result[i] = &tasks[i]
+	}
+	return result, nil
+}
+
+// UpdateIfVersion replaces all the fields of a task and increments its version, as long as it is still at the received version.
+// A task at another version has been modified since it was read, in which case a precondition failed error is returned
+func (r *taskRepository) UpdateIfVersion(ctx context.Context, id string, entity interface{}, version int) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("TaskID %s not valid: %w", id, err))
+	}
+
+	document, err := taskDocument(entity)
+	if err != nil {
+		return err
+	}
+	delete(document, "version")
+
+	result, err := r.Collection.UpdateOne(ctx, versionFilter(objectID, version), bson.M{"$set": document, "$inc": versionIncrement})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return taskWrappers.NewPreconditionFailedErr(fmt.Errorf("TaskID %s has been modified since version %d", id, version))
+	}
+	return nil
+}
+
+// versionFilter builds the filter matching a task at the received version. The tasks created before versioning have no version, which stands for version 0
+func versionFilter(objectID primitive.ObjectID, version int) bson.M {
+	if version == 0 {
+		return bson.M{"_id": objectID, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": objectID, "version": version}
+}
+
+// PurgeDeleted permanently removes the tasks moved to the trash before the received time, returning their IDs. The tasks are only removed
+// while still in the trash, and a precondition failed error is returned when any of them is restored in the meantime, so that the returned IDs
+// are exactly the ones removed. It is meant to be run in a transaction, which is rolled back on that error
+func (r *taskRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	query := bson.M{"deleted_at": bson.M{"$lte": deletedBefore}}
+
+	cur, err := r.Collection.Find(ctx, query, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cur.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	if len(tasks) < 1 {
+		return nil, nil
+	}
+
+	objectIDs := make(bson.A, len(tasks))
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		objectIDs[i] = task.ID
+		ids[i] = task.ID.Hex()
+	}
+
+	result, err := r.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": objectIDs}, "deleted_at": bson.M{"$lte": deletedBefore}})
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedCount != int64(len(ids)) {
+		return nil, taskWrappers.NewPreconditionFailedErr(fmt.Errorf("%d of the %d tasks to purge were restored meanwhile", int64(len(ids))-result.DeletedCount, len(ids)))
+	}
+	return ids, nil
+}
+
+// Watch opens a change stream of the inserts, updates and replaces of the tasks owned by the received userID, or of all the tasks when empty,
+// resuming right after the received resume token when set. Each change carries the task as stored when the change is read. An invalid or expired resume token
+// returns a validation error
+func (r *taskRepository) Watch(ctx context.Context, userID, resumeToken string, maxAwaitTime time.Duration) (ports.TaskChangeStream, error) {
+	match := bson.M{
+		"operationType": bson.M{"$in": bson.A{entities.TaskChangeOperationInsert, entities.TaskChangeOperationUpdate, entities.TaskChangeOperationReplace}},
+	}
+	if userID != "" {
+		match["fullDocument.user_id"] = userID
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: match}}}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup).SetMaxAwaitTime(maxAwaitTime)
+	if resumeToken != "" {
+		opts.SetResumeAfter(bson.M{"_data": resumeToken})
+	}
+
+	stream, err := r.Collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		var serverErr mongo.ServerError
+		if resumeToken != "" && errors.As(err, &serverErr) {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("resume token %s not valid or expired: %w", resumeToken, err))
+		}
+		return nil, err
+	}
+	return &taskChangeStream{stream}, nil
+}
+
+// taskChangeStream adapter of a task change stream for mongo.
+type taskChangeStream struct {
+	stream *mongo.ChangeStream
+}
+
+// Next waits up to the maximum await time of the stream for the next change, returning nil when none arrives meanwhile
+func (s *taskChangeStream) Next(ctx context.Context) (*entities.TaskChange, error) {
+	if !s.stream.TryNext(ctx) {
+		return nil, s.stream.Err()
+	}
+
+	var event struct {
+		ID struct {
+			Data string `bson:"_data"`
+		} `bson:"_id"`
+		OperationType string        `bson:"operationType"`
+		FullDocument  entities.Task `bson:"fullDocument"`
+	}
+	if err := s.stream.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	return &entities.TaskChange{
+		Token:     event.ID.Data,
+		Operation: event.OperationType,
+		Task:      event.FullDocument,
+	}, nil
+}
+
+// Close closes the change stream
+func (s *taskChangeStream) Close(ctx context.Context) error {
+	return s.stream.Close(ctx)
+}
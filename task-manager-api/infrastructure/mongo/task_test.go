@@ -3,9 +3,17 @@ package mongo
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	taskWrappers "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/wrappers"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
@@ -25,3 +33,837 @@ func TestNewTaskRepository_Ok(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+// TestCountByStatus_Ok checks that CountByStatus returns the expected counts when the aggregation succeeds
+func TestCountByStatus_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: entities.TaskStatusTodo}, {Key: "count", Value: 2}},
+				bson.D{{Key: "_id", Value: entities.TaskStatusDone}, {Key: "count", Value: 1}},
+			),
+		)
+		expectedResult := map[string]int{entities.TaskStatusTodo: 2, entities.TaskStatusDone: 1}
+
+		// Act
+		result, err := repo.CountByStatus(context.Background(), "user-123")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// TestCountByStatus_AggregateError checks that CountByStatus returns an error when the aggregation fails
+func TestCountByStatus_AggregateError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "aggregate-error"}))
+
+		// Act
+		_, err := repo.CountByStatus(context.Background(), "user-123")
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestCountSubtasks_Ok checks that CountSubtasks returns the expected counts when the aggregation succeeds
+func TestCountSubtasks_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "task-1"}, {Key: "total", Value: 4}, {Key: "done", Value: 1}},
+			),
+		)
+		expectedResult := map[string]models.SubtaskCount{"task-1": {Total: 4, Done: 1}}
+
+		// Act
+		result, err := repo.CountSubtasks(context.Background(), []string{"task-1", "task-2"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// TestCountSubtasks_AggregateError checks that CountSubtasks returns an error when the aggregation fails
+func TestCountSubtasks_AggregateError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "aggregate-error"}))
+
+		// Act
+		_, err := repo.CountSubtasks(context.Background(), []string{"task-1"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+func newTestTaskRepository(mt *mtest.T) *taskRepository {
+	return &taskRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.Task{},
+		},
+	}
+}
+
+// TestFind_Ok checks that Find returns the expected tasks when the query succeeds
+func TestFind_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		dueBefore := time.Now().UTC()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "task-1"}, {Key: "user_id", Value: "user-123"}, {Key: "title", Value: "title-1"}},
+				bson.D{{Key: "_id", Value: "task-2"}, {Key: "user_id", Value: "user-123"}, {Key: "title", Value: "title-2"}},
+			),
+		)
+
+		// Act
+		result, err := repo.Find(context.Background(), models.TaskFilter{UserID: "user-123", Statuses: []string{entities.TaskStatusTodo}, DueBefore: &dueBefore, DueAfter: &dueBefore})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, "title-1", result[0].(*entities.Task).Title)
+	})
+}
+
+// TestFind_NoDocuments checks that Find returns a NonExistentErr when no task matches the filter
+func TestFind_NoDocuments(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Find(context.Background(), models.TaskFilter{UserID: "user-123"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestFind_FindError checks that Find returns an error when the query fails
+func TestFind_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "find-error"}))
+
+		// Act
+		_, err := repo.Find(context.Background(), models.TaskFilter{UserID: "user-123"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestFindByIDs_Ok checks that FindByIDs returns the expected tasks when the query succeeds
+func TestFindByIDs_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: "user-123"}, {Key: "title", Value: "title-1"}},
+			),
+		)
+
+		// Act
+		result, err := repo.FindByIDs(context.Background(), []string{id.Hex()})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, id.Hex(), result[0].(*entities.Task).ID)
+	})
+}
+
+// TestFindByIDs_InvalidID checks that FindByIDs returns a validation error when an ID is not valid
+func TestFindByIDs_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+
+		// Act
+		_, err := repo.FindByIDs(context.Background(), []string{"not-an-object-id"})
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.ValidationErr, err)
+	})
+}
+
+// TestSearch_Ok checks that Search returns the expected tasks with their text score when the query succeeds
+func TestSearch_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "task-1"}, {Key: "user_id", Value: "user-123"}, {Key: "title", Value: "report"}, {Key: "score", Value: 1.5}},
+			),
+		)
+
+		// Act
+		result, err := repo.Search(context.Background(), models.TaskFilter{UserID: "user-123", Limit: 10}, "report")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, "report", result[0].(*entities.TaskSearchResult).Title)
+		assert.Equal(t, 1.5, result[0].(*entities.TaskSearchResult).Score)
+	})
+}
+
+// TestSearch_NoDocuments checks that Search returns a NonExistentErr when no task matches the text
+func TestSearch_NoDocuments(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Search(context.Background(), models.TaskFilter{UserID: "user-123"}, "report")
+
+		// Assert
+		assert.NotEmpty(t, err)
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestFilterConditions_Labels checks that filterConditions matches any or all of the labels as requested
+func TestFilterConditions_Labels(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   models.TaskFilter
+		expected bson.M
+	}{
+		{"any label", models.TaskFilter{Labels: []string{"work", "urgent"}}, bson.M{"labels": bson.M{"$in": []string{"work", "urgent"}}}},
+		{"all labels", models.TaskFilter{Labels: []string{"work", "urgent"}, AllLabels: true}, bson.M{"labels": bson.M{"$all": []string{"work", "urgent"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			conditions := filterConditions(tt.filter)
+
+			// Assert
+			assert.Contains(t, conditions, tt.expected)
+		})
+	}
+}
+
+// TestFilterConditions_IDs checks that filterConditions matches the requested IDs instead of the user, leaving out the invalid ones
+func TestFilterConditions_IDs(t *testing.T) {
+	// Arrange
+	id := primitive.NewObjectID()
+	filter := models.TaskFilter{IDs: []string{id.Hex(), "not-an-object-id"}}
+
+	// Act
+	conditions := filterConditions(filter)
+
+	// Assert
+	assert.Contains(t, conditions, bson.M{"_id": bson.M{"$in": bson.A{id}}})
+	assert.NotContains(t, conditions, bson.M{"user_id": ""})
+}
+
+// TestFilterConditions_Project checks that filterConditions matches the tasks of the requested project among the tasks of the user
+func TestFilterConditions_Project(t *testing.T) {
+	// Arrange
+	filter := models.TaskFilter{UserID: "user-123", ProjectID: "project-1"}
+
+	// Act
+	conditions := filterConditions(filter)
+
+	// Assert
+	assert.Contains(t, conditions, bson.M{"user_id": "user-123"})
+	assert.Contains(t, conditions, bson.M{"project_id": "project-1"})
+}
+
+// TestFilterConditions_Assignee checks that filterConditions matches the tasks assigned to the requested user instead of the ones it owns
+func TestFilterConditions_Assignee(t *testing.T) {
+	// Arrange
+	filter := models.TaskFilter{AssigneeID: "user-456"}
+
+	// Act
+	conditions := filterConditions(filter)
+
+	// Assert
+	assert.Contains(t, conditions, bson.M{"assignee_id": "user-456"})
+	assert.NotContains(t, conditions, bson.M{"user_id": ""})
+}
+
+// TestKeysetCondition checks that keysetCondition builds the expected conditions for each sort and cursor
+func TestKeysetCondition(t *testing.T) {
+	id := primitive.NewObjectID()
+	dueAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   models.TaskFilter
+		expected bson.M
+	}{
+		{
+			"ascending title",
+			models.TaskFilter{SortBy: "title", After: &models.TaskCursor{ID: id.Hex(), Title: "title"}},
+			bson.M{"$or": bson.A{bson.M{"title": bson.M{"$gt": "title"}}, bson.M{"title": "title", "_id": bson.M{"$gt": id}}}},
+		},
+		{
+			"ascending rank",
+			models.TaskFilter{SortBy: "rank", After: &models.TaskCursor{ID: id.Hex(), Rank: "V"}},
+			bson.M{"$or": bson.A{bson.M{"rank": bson.M{"$gt": "V"}}, bson.M{"rank": "V", "_id": bson.M{"$gt": id}}}},
+		},
+		{
+			"descending due date",
+			models.TaskFilter{SortBy: "due_at", SortDesc: true, After: &models.TaskCursor{ID: id.Hex(), DueAt: &dueAt}},
+			bson.M{"$or": bson.A{bson.M{"due_at": bson.M{"$lt": dueAt}}, bson.M{"due_at": dueAt, "_id": bson.M{"$lt": id}}, bson.M{"due_at": nil}}},
+		},
+		{
+			"ascending null due date",
+			models.TaskFilter{SortBy: "due_at", After: &models.TaskCursor{ID: id.Hex()}},
+			bson.M{"$or": bson.A{bson.M{"due_at": nil, "_id": bson.M{"$gt": id}}, bson.M{"due_at": bson.M{"$ne": nil}}}},
+		},
+		{
+			"descending null due date",
+			models.TaskFilter{SortBy: "due_at", SortDesc: true, After: &models.TaskCursor{ID: id.Hex()}},
+			bson.M{"due_at": nil, "_id": bson.M{"$lt": id}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := keysetCondition(tt.filter)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, condition)
+		})
+	}
+}
+
+// TestKeysetCondition_InvalidID checks that keysetCondition returns a validation error when the cursor ID is not valid
+func TestKeysetCondition_InvalidID(t *testing.T) {
+	// Act
+	_, err := keysetCondition(models.TaskFilter{SortBy: "created_at", After: &models.TaskCursor{ID: "invalid-id"}})
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+}
+
+// TestCountByProject_Ok checks that CountByProject returns the task counts grouped by project
+func TestCountByProject_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "project-1"}, {Key: "total", Value: 5}, {Key: "open", Value: 3}, {Key: "done", Value: 2}},
+			),
+		)
+		expectedResult := map[string]models.ProjectTaskCounts{"project-1": {Total: 5, Open: 3, Done: 2}}
+
+		// Act
+		result, err := repo.CountByProject(context.Background(), []string{"project-1", "project-2"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// TestCountRank_Ok checks that CountRank returns the number of tasks with the rank in the column
+func TestCountRank_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}))
+
+		// Act
+		count, err := repo.CountRank(context.Background(), "user-123", entities.TaskStatusTodo, "V")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+// TestLastRank_Ok checks that LastRank returns the rank of the last task of the column
+func TestLastRank_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "rank", Value: "W"}}))
+
+		// Act
+		rank, err := repo.LastRank(context.Background(), "user-123", entities.TaskStatusTodo)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "W", rank)
+	})
+}
+
+// TestFirstRank_EmptyColumn checks that FirstRank returns an empty rank when the column has no ranked tasks
+func TestFirstRank_EmptyColumn(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		rank, err := repo.FirstRank(context.Background(), "user-123", entities.TaskStatusTodo)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "", rank)
+	})
+}
+
+// TestFindRankColumns_Ok checks that FindRankColumns returns the columns grouped by user and status
+func TestFindRankColumns_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: bson.D{{Key: "user_id", Value: "user-123"}, {Key: "status", Value: entities.TaskStatusTodo}}}},
+			),
+		)
+		expectedResult := []models.TaskColumn{{UserID: "user-123", Status: entities.TaskStatusTodo}}
+
+		// Act
+		result, err := repo.FindRankColumns(context.Background(), 16, 100)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// TestFindColumn_Ok checks that FindColumn returns the tasks of the column
+func TestFindColumn_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "user_id", Value: "user-123"}, {Key: "rank", Value: "V"}},
+			),
+		)
+
+		// Act
+		result, err := repo.FindColumn(context.Background(), "user-123", entities.TaskStatusTodo)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, "V", result[0].(*entities.Task).Rank)
+	})
+}
+
+// TestFindPendingOccurrences_Ok checks that FindPendingOccurrences returns the expected tasks when the query succeeds
+func TestFindPendingOccurrences_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: id}, {Key: "user_id", Value: "user-123"}, {Key: "recurrence", Value: "FREQ=DAILY"}},
+			),
+		)
+
+		// Act
+		result, err := repo.FindPendingOccurrences(context.Background(), time.Now(), []string{primitive.NewObjectID().Hex(), "invalid-id"}, 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, "FREQ=DAILY", result[0].(*entities.Task).Recurrence)
+	})
+}
+
+// TestFindPendingOccurrences_NoResults checks that FindPendingOccurrences returns a non existent error when no task is pending
+func TestFindPendingOccurrences_NoResults(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		_, err := repo.FindPendingOccurrences(context.Background(), time.Now(), nil, 10)
+
+		// Assert
+		assert.IsType(t, wrappers.NonExistentErr, err)
+	})
+}
+
+// TestUpdateIfVersion_Ok checks that UpdateIfVersion does not return an error when the task is still at the received version
+func TestUpdateIfVersion_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.UpdateIfVersion(context.Background(), primitive.NewObjectID().Hex(), entities.Task{Title: "title", Version: 3}, 3)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateIfVersion_Modified checks that UpdateIfVersion returns a precondition failed error when the task is at another version
+func TestUpdateIfVersion_Modified(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.UpdateIfVersion(context.Background(), primitive.NewObjectID().Hex(), entities.Task{Title: "title"}, 0)
+
+		// Assert
+		assert.IsType(t, taskWrappers.PreconditionFailedErr, err)
+	})
+}
+
+// TestUpdateIfVersion_InvalidID checks that UpdateIfVersion returns a validation error when the task ID is not valid
+func TestUpdateIfVersion_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+
+		// Act
+		err := repo.UpdateIfVersion(context.Background(), "invalid-id", entities.Task{}, 1)
+
+		// Assert
+		assert.IsType(t, wrappers.ValidationErr, err)
+	})
+}
+
+// TestPurgeDeleted_Ok checks that PurgeDeleted removes the expired tasks and returns their IDs
+func TestPurgeDeleted_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}},
+		)
+
+		// Act
+		ids, err := repo.PurgeDeleted(context.Background(), time.Now())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []string{id.Hex()}, ids)
+	})
+}
+
+// TestPurgeDeleted_Restored checks that PurgeDeleted returns a precondition failed error when a task is restored before being removed
+func TestPurgeDeleted_Restored(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, bson.D{{Key: "_id", Value: primitive.NewObjectID()}}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}},
+		)
+
+		// Act
+		ids, err := repo.PurgeDeleted(context.Background(), time.Now())
+
+		// Assert
+		assert.IsType(t, taskWrappers.PreconditionFailedErr, err)
+		assert.Nil(t, ids)
+	})
+}
+
+// TestPurgeDeleted_NoResults checks that PurgeDeleted does not remove anything when no task has expired
+func TestPurgeDeleted_NoResults(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		// Act
+		ids, err := repo.PurgeDeleted(context.Background(), time.Now())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Empty(t, ids)
+	})
+}
+
+// TestBulkWrite_Ok checks that BulkWrite applies the writes in a single bulk write, returning a validation error for the writes with invalid IDs
+func TestBulkWrite_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		updatedID := primitive.NewObjectID()
+		deletedID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: updatedID}, {Key: "version", Value: 3}},
+				bson.D{{Key: "_id", Value: deletedID}},
+			),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}},
+		)
+		writes := []models.TaskWrite{
+			{Op: models.BatchOpCreate, Task: entities.Task{UserID: "user-123", Title: "title"}},
+			{Op: models.BatchOpUpdate, IDs: []string{"invalid-id"}, Versions: []int{0}, Task: entities.Task{Title: "title"}},
+			{Op: models.BatchOpUpdate, IDs: []string{updatedID.Hex()}, Versions: []int{3}, Task: entities.Task{ID: updatedID.Hex(), Title: "title"}},
+			{Op: models.BatchOpDelete, IDs: []string{deletedID.Hex()}, Versions: []int{0}, DeletedAt: time.Now()},
+		}
+
+		// Act
+		results, err := repo.BulkWrite(context.Background(), writes)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Nil(t, results[0].Err)
+		assert.True(t, primitive.IsValidObjectID(results[0].ID))
+		assert.IsType(t, wrappers.ValidationErr, results[1].Err)
+		assert.Nil(t, results[2].Err)
+		assert.Equal(t, updatedID.Hex(), results[2].ID)
+		assert.Nil(t, results[3].Err)
+		assert.Equal(t, deletedID.Hex(), results[3].ID)
+	})
+}
+
+// TestBulkWrite_Modified checks that BulkWrite does not apply the writes to tasks that are no longer at the expected version, failing them with a precondition failed error
+func TestBulkWrite_Modified(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		updatedID := primitive.NewObjectID()
+		subtaskID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: updatedID}, {Key: "version", Value: 1}},
+				bson.D{{Key: "_id", Value: subtaskID}, {Key: "version", Value: 4}},
+			),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+		)
+		writes := []models.TaskWrite{
+			{Op: models.BatchOpUpdate, IDs: []string{updatedID.Hex()}, Versions: []int{1}, Task: entities.Task{Title: "title"}},
+			{Op: models.BatchOpDelete, IDs: []string{primitive.NewObjectID().Hex(), subtaskID.Hex()}, Versions: []int{2, 3}, DeletedAt: time.Now()},
+		}
+
+		// Act
+		results, err := repo.BulkWrite(context.Background(), writes)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Nil(t, results[0].Err)
+		assert.IsType(t, taskWrappers.PreconditionFailedErr, results[1].Err)
+	})
+}
+
+// TestBulkWrite_ModifiedWhileWriting checks that BulkWrite returns a precondition failed error when a task changes between the check of its version and its write
+func TestBulkWrite_ModifiedWhileWriting(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "version", Value: 1}}),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}},
+		)
+		writes := []models.TaskWrite{
+			{Op: models.BatchOpUpdate, IDs: []string{id.Hex()}, Versions: []int{1}, Task: entities.Task{Title: "title"}},
+		}
+
+		// Act
+		results, err := repo.BulkWrite(context.Background(), writes)
+
+		// Assert
+		assert.Nil(t, results)
+		assert.IsType(t, taskWrappers.PreconditionFailedErr, err)
+	})
+}
+
+// TestBulkWrite_WriteErrors checks that BulkWrite returns the error of each failed write in its result
+func TestBulkWrite_WriteErrors(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		firstID := primitive.NewObjectID()
+		secondID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: firstID}}, bson.D{{Key: "_id", Value: secondID}}),
+			bson.D{
+				{Key: "ok", Value: 1},
+				{Key: "n", Value: 1},
+				{Key: "writeErrors", Value: bson.A{bson.D{{Key: "index", Value: 1}, {Key: "code", Value: 11000}, {Key: "errmsg", Value: "duplicate key"}}}},
+			},
+		)
+		writes := []models.TaskWrite{
+			{Op: models.BatchOpDelete, IDs: []string{"invalid-id"}, Versions: []int{0}},
+			{Op: models.BatchOpDelete, IDs: []string{firstID.Hex()}, Versions: []int{0}},
+			{Op: models.BatchOpDelete, IDs: []string{secondID.Hex()}, Versions: []int{0}},
+		}
+
+		// Act
+		results, err := repo.BulkWrite(context.Background(), writes)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.IsType(t, wrappers.ValidationErr, results[0].Err)
+		assert.Nil(t, results[1].Err)
+		assert.NotNil(t, results[2].Err)
+	})
+}
+
+// TestWatch_Ok checks that Watch opens a change stream whose changes carry their resume token, their operation and the task
+func TestWatch_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		ns := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "token-1"}}},
+				{Key: "operationType", Value: entities.TaskChangeOperationInsert},
+				{Key: "fullDocument", Value: bson.D{
+					{Key: "_id", Value: id},
+					{Key: "user_id", Value: "user-123"},
+					{Key: "title", Value: "title"},
+				}},
+			}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		stream, err := repo.Watch(context.Background(), "user-123", "token-0", time.Second)
+		assert.Nil(t, err)
+		change, err := stream.Next(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "token-1", change.Token)
+		assert.Equal(t, entities.TaskChangeOperationInsert, change.Operation)
+		assert.Equal(t, id.Hex(), change.Task.ID)
+		assert.Equal(t, "title", change.Task.Title)
+		assert.Nil(t, stream.Close(context.Background()))
+	})
+}
+
+// TestWatch_InvalidResumeToken checks that Watch returns a validation error when the changes cannot be resumed after the received token
+func TestWatch_InvalidResumeToken(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestTaskRepository(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 260, Name: "InvalidResumeToken", Message: "invalid resume token"}))
+
+		// Act
+		stream, err := repo.Watch(context.Background(), "user-123", "expired-token", time.Second)
+
+		// Assert
+		assert.Nil(t, stream)
+		assert.IsType(t, wrappers.ValidationErr, err)
+	})
+}
@@ -0,0 +1,36 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// transactionManager adapter of a transaction manager for mongo, which requires the database to run as a replica set
+type transactionManager struct {
+	client *mongo.Client
+}
+
+// NewTransactionManager creates a transaction manager for mongo
+func NewTransactionManager(db *mongo.Database) ports.TransactionManager {
+	return &transactionManager{
+		client: db.Client(),
+	}
+}
+
+// WithTransaction runs the received function in a transaction, committed when the function succeeds and aborted otherwise.
+// The repositories take part in the transaction when receiving the context passed to the function, which may be run again
+// on transient errors. A function run from inside another transaction takes part in it instead of starting a new one
+func (m *transactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return m.client.UseSession(ctx, func(sessionCtx mongo.SessionContext) error {
+		_, err := sessionCtx.WithTransaction(sessionCtx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sessionCtx)
+		})
+		return err
+	})
+}
@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestWithTransaction_Ok checks that WithTransaction runs the received function in a session and commits its transaction
+func TestWithTransaction_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		manager := NewTransactionManager(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		// Act
+		inSession := false
+		err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+			inSession = mongo.SessionFromContext(ctx) != nil
+			_, err := mt.Coll.InsertOne(ctx, bson.M{"key": "value"})
+			return err
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.True(t, inSession)
+	})
+}
+
+// TestWithTransaction_Error checks that WithTransaction returns the error of the received function
+func TestWithTransaction_Error(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		manager := NewTransactionManager(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+		expectedErr := errors.New("fn-error")
+
+		// Act
+		err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+			_, err := mt.Coll.InsertOne(ctx, bson.M{"key": "value"})
+			if err != nil {
+				return err
+			}
+			return expectedErr
+		})
+
+		// Assert
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+// TestWithTransaction_Nested checks that WithTransaction runs the received function in the transaction of the received context
+func TestWithTransaction_Nested(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		manager := NewTransactionManager(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		// Act
+		var outer, inner mongo.Session
+		err := manager.WithTransaction(context.Background(), func(ctx context.Context) error {
+			outer = mongo.SessionFromContext(ctx)
+			return manager.WithTransaction(ctx, func(ctx context.Context) error {
+				inner = mongo.SessionFromContext(ctx)
+				_, err := mt.Coll.InsertOne(ctx, bson.M{"key": "value"})
+				return err
+			})
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotNil(t, outer)
+		assert.Equal(t, outer.ID(), inner.ID())
+	})
+}
@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookRepository adapter of a webhook repository for mongo.
+type webhookRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewWebhookRepository creates a webhook repository for mongo
+func NewWebhookRepository(ctx context.Context, db *mongo.Database) (ports.WebhookRepository, error) {
+	r := &webhookRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameWebhook),
+			Target:     entities.Webhook{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "events", Value: 1}},
+		},
+	)
+	return r, err
+}
+
+// webhookDeliveryRepository adapter of a webhook delivery repository for mongo.
+type webhookDeliveryRepository struct {
+	infrastructure.MongoRepository
+}
+
+// NewWebhookDeliveryRepository creates a webhook delivery repository for mongo
+func NewWebhookDeliveryRepository(ctx context.Context, db *mongo.Database) (ports.WebhookDeliveryRepository, error) {
+	r := &webhookDeliveryRepository{
+		infrastructure.MongoRepository{
+			DB:         db,
+			Collection: db.Collection(entities.EntityNameWebhookDelivery),
+			Target:     entities.WebhookDelivery{},
+		},
+	}
+
+	_, err := r.Collection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "status", Value: 1}},
+			},
+			{
+				Keys: bson.D{{Key: "event_id", Value: 1}, {Key: "webhook_id", Value: 1}},
+				Options: options.Index().SetUnique(true).
+					SetPartialFilterExpression(bson.M{"event_id": bson.M{"$gt": ""}}),
+			},
+		},
+	)
+	return r, err
+}
+
+// Enqueue inserts the received delivery unless the webhook already has a delivery of the same event,
+// so that an event published more than once is only delivered once
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery entities.WebhookDelivery) error {
+	_, err := r.Collection.UpdateOne(
+		ctx,
+		bson.M{"event_id": delivery.EventID, "webhook_id": delivery.WebhookID},
+		bson.M{"$setOnInsert": delivery},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// ClaimDue gets the pending delivery that has been due for the longest, postponing its next attempt until the received leaseUntil
+// so that no other process claims it meanwhile. It returns a non existent error when no delivery is due
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (interface{}, error) {
+	var delivery entities.WebhookDelivery
+	err := r.Collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"status": entities.WebhookDeliveryStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}),
+	).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, wrappers.NewNonExistentErr(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// DeleteByWebhookID deletes the deliveries of the received webhook
+func (r *webhookDeliveryRepository) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{"webhook_id": webhookID})
+	return err
+}
@@ -0,0 +1,159 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewWebhookRepository_Ok checks that NewWebhookRepository creates a new webhookRepository struct
+func TestNewWebhookRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewWebhookRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestNewWebhookDeliveryRepository_Ok checks that NewWebhookDeliveryRepository creates a new webhookDeliveryRepository struct
+func TestNewWebhookDeliveryRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		repo, err := NewWebhookDeliveryRepository(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+		assert.Nil(t, err)
+	})
+}
+
+// TestClaimDue_Ok checks that ClaimDue returns the claimed delivery
+func TestClaimDue_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestWebhookDeliveryRepository(mt)
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "webhook_id", Value: "webhook-1"},
+				{Key: "status", Value: entities.WebhookDeliveryStatusPending},
+			}},
+		})
+		now := time.Now().UTC()
+
+		// Act
+		result, err := repo.ClaimDue(context.Background(), now, now.Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, id.Hex(), result.(*entities.WebhookDelivery).ID)
+		assert.Equal(t, "webhook-1", result.(*entities.WebhookDelivery).WebhookID)
+	})
+}
+
+// TestClaimDue_NoneDue checks that ClaimDue returns a non existent error when no delivery is due
+func TestClaimDue_NoneDue(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestWebhookDeliveryRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		now := time.Now().UTC()
+
+		// Act
+		result, err := repo.ClaimDue(context.Background(), now, now.Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestEnqueue_Ok checks that Enqueue does not return an error when the delivery is queued or was already queued
+func TestEnqueue_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestWebhookDeliveryRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Enqueue(context.Background(), entities.WebhookDelivery{EventID: "history-id", WebhookID: "webhook-1"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestEnqueue_Error checks that Enqueue returns an error when the upsert fails
+func TestEnqueue_Error(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestWebhookDeliveryRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		err := repo.Enqueue(context.Background(), entities.WebhookDelivery{EventID: "history-id", WebhookID: "webhook-1"})
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestDeleteByWebhookID_Ok checks that DeleteByWebhookID does not return an error when the deletion succeeds
+func TestDeleteByWebhookID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := newTestWebhookDeliveryRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		// Act
+		err := repo.DeleteByWebhookID(context.Background(), "webhook-1")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+func newTestWebhookDeliveryRepository(mt *mtest.T) *webhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		infrastructure.MongoRepository{
+			DB:         mt.DB,
+			Collection: mt.Coll,
+			Target:     entities.WebhookDelivery{},
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// maxResponseSize is the size of the response bodies read before closing them, which allows reusing the connections
+const maxResponseSize = 64 << 10
+
+// httpClient adapter of a webhook client over HTTP.
+type httpClient struct {
+	client       *http.Client
+	resolver     *net.Resolver
+	blockedHosts []string
+	allowedAddr  func(addr netip.Addr) bool
+}
+
+// NewHTTPClient creates a webhook client over HTTP, giving up on the requests that take longer than the received timeout.
+// It refuses to reach the received internal hosts and any address that is not public
+func NewHTTPClient(timeout time.Duration, blockedHosts []string) ports.WebhookClient {
+	return newHTTPClient(timeout, blockedHosts, isPublicAddr)
+}
+
+// newHTTPClient creates a webhook client over HTTP that only connects to the addresses accepted by the received function.
+// The addresses are checked when dialing, after resolving the host, so that a host resolving to a different address
+// than the one checked when the webhook was registered is refused too
+func newHTTPClient(timeout time.Duration, blockedHosts []string, allowedAddr func(addr netip.Addr) bool) *httpClient {
+	dialer := &net.Dialer{
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !allowedAddr(addr.Unmap()) {
+				return fmt.Errorf("address %s not allowed for webhooks", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address dialed, so the webhooks are always reached directly
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &httpClient{
+		client:       &http.Client{Timeout: timeout, Transport: transport},
+		resolver:     net.DefaultResolver,
+		blockedHosts: blockedHosts,
+		allowedAddr:  allowedAddr,
+	}
+}
+
+// Send posts the received body to the received URL, returning the status code of the response
+func (c *httpClient) Send(ctx context.Context, url string, header map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	return resp.StatusCode, nil
+}
+
+// CheckURL checks that the host of the received URL is not an internal host and only resolves to allowed addresses
+func (c *httpClient) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("webhook url %s not valid", rawURL))
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if slices.ContainsFunc(c.blockedHosts, func(blocked string) bool {
+		return host == blocked || strings.HasSuffix(host, "."+blocked)
+	}) {
+		return wrappers.NewValidationErr(fmt.Errorf("webhook host %s not allowed", host))
+	}
+
+	addrs, err := c.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return wrappers.NewValidationErr(fmt.Errorf("webhook host %s could not be resolved", host))
+	}
+	for _, addr := range addrs {
+		if !c.allowedAddr(addr.Unmap()) {
+			return wrappers.NewValidationErr(fmt.Errorf("webhook host %s resolves to %s, which is not allowed", host, addr))
+		}
+	}
+	return nil
+}
+
+// isPublicAddr reports whether the received address can be reached from the internet,
+// as opposed to loopback, private, link-local (such as the cloud metadata endpoints) and unspecified addresses
+func isPublicAddr(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
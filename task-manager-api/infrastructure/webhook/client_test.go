@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLocalHTTPClient creates a webhook client allowed to reach any address, so that it can reach the local test servers
+func newLocalHTTPClient(timeout time.Duration) *httpClient {
+	return newHTTPClient(timeout, nil, func(addr netip.Addr) bool { return true })
+}
+
+// TestSend_Ok checks that Send posts the body with the received headers and returns the status code of the response
+func TestSend_Ok(t *testing.T) {
+	// Arrange
+	var receivedBody []byte
+	var receivedHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		receivedHeader = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := newLocalHTTPClient(time.Second)
+
+	// Act
+	statusCode, err := client.Send(context.Background(), server.URL, map[string]string{"X-Webhook-Event": "task.created"}, []byte(`{"id":"history-id"}`))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.Equal(t, `{"id":"history-id"}`, string(receivedBody))
+	assert.Equal(t, "task.created", receivedHeader.Get("X-Webhook-Event"))
+}
+
+// TestSend_Timeout checks that Send returns an error when the endpoint takes longer than the timeout
+func TestSend_Timeout(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := newLocalHTTPClient(10 * time.Millisecond)
+
+	// Act
+	statusCode, err := client.Send(context.Background(), server.URL, nil, nil)
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, statusCode)
+}
+
+// TestSend_InvalidURL checks that Send returns an error when the URL is not valid
+func TestSend_InvalidURL(t *testing.T) {
+	// Arrange
+	client := newLocalHTTPClient(time.Second)
+
+	// Act
+	_, err := client.Send(context.Background(), "://invalid", nil, nil)
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestSend_PrivateAddress checks that Send refuses to connect to an address that is not public, whatever the URL was checked against
+func TestSend_PrivateAddress(t *testing.T) {
+	// Arrange
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(time.Second, nil)
+
+	// Act
+	statusCode, err := client.Send(context.Background(), server.URL, nil, nil)
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, statusCode)
+	assert.False(t, called)
+}
+
+// TestCheckURL_Ok checks that CheckURL accepts a URL whose host is a public address
+func TestCheckURL_Ok(t *testing.T) {
+	// Arrange
+	client := NewHTTPClient(time.Second, []string{"localhost"})
+
+	// Act
+	err := client.CheckURL(context.Background(), "https://93.184.215.14/hook")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestCheckURL_NotAllowed checks that CheckURL returns a validation error when the host is internal or is not a public address
+func TestCheckURL_NotAllowed(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "blocked host", url: "http://localhost:8080/hook"},
+		{name: "blocked subdomain", url: "http://db.mongo./hook"},
+		{name: "loopback", url: "http://127.0.0.1/hook"},
+		{name: "private", url: "http://10.0.0.5/hook"},
+		{name: "link-local", url: "http://169.254.169.254/latest/meta-data"},
+		{name: "unspecified", url: "http://0.0.0.0/hook"},
+		{name: "ipv6 loopback", url: "http://[::1]/hook"},
+		{name: "ipv4-mapped private", url: "http://[::ffff:192.168.1.1]/hook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			client := NewHTTPClient(time.Second, []string{"localhost", "mongo"})
+
+			// Act
+			err := client.CheckURL(context.Background(), tt.url)
+
+			// Assert
+			assert.True(t, errors.Is(err, wrappers.ValidationErr))
+		})
+	}
+}
@@ -4,7 +4,10 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
+	models "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	ports "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,6 +16,154 @@ type TaskRepository struct {
 	mock.Mock
 }
 
+// BulkWrite provides a mock function with given fields: ctx, writes
+func (_m *TaskRepository) BulkWrite(ctx context.Context, writes []models.TaskWrite) ([]models.TaskWriteResult, error) {
+	ret := _m.Called(ctx, writes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkWrite")
+	}
+
+	var r0 []models.TaskWriteResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.TaskWrite) ([]models.TaskWriteResult, error)); ok {
+		return rf(ctx, writes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.TaskWrite) []models.TaskWriteResult); ok {
+		r0 = rf(ctx, writes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TaskWriteResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.TaskWrite) error); ok {
+		r1 = rf(ctx, writes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByProject provides a mock function with given fields: ctx, projectIDs
+func (_m *TaskRepository) CountByProject(ctx context.Context, projectIDs []string) (map[string]models.ProjectTaskCounts, error) {
+	ret := _m.Called(ctx, projectIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByProject")
+	}
+
+	var r0 map[string]models.ProjectTaskCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]models.ProjectTaskCounts, error)); ok {
+		return rf(ctx, projectIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]models.ProjectTaskCounts); ok {
+		r0 = rf(ctx, projectIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]models.ProjectTaskCounts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, projectIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountByStatus provides a mock function with given fields: ctx, userID
+func (_m *TaskRepository) CountByStatus(ctx context.Context, userID string) (map[string]int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByStatus")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[string]int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountRank provides a mock function with given fields: ctx, userID, status, rank
+func (_m *TaskRepository) CountRank(ctx context.Context, userID string, status string, rank string) (int, error) {
+	ret := _m.Called(ctx, userID, status, rank)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountRank")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (int, error)); ok {
+		return rf(ctx, userID, status, rank)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int); ok {
+		r0 = rf(ctx, userID, status, rank)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, status, rank)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountSubtasks provides a mock function with given fields: ctx, parentIDs
+func (_m *TaskRepository) CountSubtasks(ctx context.Context, parentIDs []string) (map[string]models.SubtaskCount, error) {
+	ret := _m.Called(ctx, parentIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountSubtasks")
+	}
+
+	var r0 map[string]models.SubtaskCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]models.SubtaskCount, error)); ok {
+		return rf(ctx, parentIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]models.SubtaskCount); ok {
+		r0 = rf(ctx, parentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]models.SubtaskCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, parentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, entity
 func (_m *TaskRepository) Create(ctx context.Context, entity interface{}) (string, error) {
 	ret := _m.Called(ctx, entity)
@@ -59,6 +210,184 @@ func (_m *TaskRepository) Delete(ctx context.Context, ID string) error {
 	return r0
 }
 
+// Find provides a mock function with given fields: ctx, filter
+func (_m *TaskRepository) Find(ctx context.Context, filter models.TaskFilter) ([]interface{}, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.TaskFilter) ([]interface{}, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.TaskFilter) []interface{}); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.TaskFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIDs provides a mock function with given fields: ctx, ids
+func (_m *TaskRepository) FindByIDs(ctx context.Context, ids []string) ([]interface{}, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIDs")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]interface{}, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []interface{}); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindColumn provides a mock function with given fields: ctx, userID, status
+func (_m *TaskRepository) FindColumn(ctx context.Context, userID string, status string) ([]interface{}, error) {
+	ret := _m.Called(ctx, userID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindColumn")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]interface{}, error)); ok {
+		return rf(ctx, userID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []interface{}); ok {
+		r0 = rf(ctx, userID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPendingOccurrences provides a mock function with given fields: ctx, dueBefore, excludedIDs, limit
+func (_m *TaskRepository) FindPendingOccurrences(ctx context.Context, dueBefore time.Time, excludedIDs []string, limit int) ([]interface{}, error) {
+	ret := _m.Called(ctx, dueBefore, excludedIDs, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPendingOccurrences")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []string, int) ([]interface{}, error)); ok {
+		return rf(ctx, dueBefore, excludedIDs, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []string, int) []interface{}); ok {
+		r0 = rf(ctx, dueBefore, excludedIDs, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, []string, int) error); ok {
+		r1 = rf(ctx, dueBefore, excludedIDs, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRankColumns provides a mock function with given fields: ctx, maxLength, limit
+func (_m *TaskRepository) FindRankColumns(ctx context.Context, maxLength int, limit int) ([]models.TaskColumn, error) {
+	ret := _m.Called(ctx, maxLength, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRankColumns")
+	}
+
+	var r0 []models.TaskColumn
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.TaskColumn, error)); ok {
+		return rf(ctx, maxLength, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.TaskColumn); ok {
+		r0 = rf(ctx, maxLength, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TaskColumn)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, maxLength, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FirstRank provides a mock function with given fields: ctx, userID, status
+func (_m *TaskRepository) FirstRank(ctx context.Context, userID string, status string) (string, error) {
+	ret := _m.Called(ctx, userID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FirstRank")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, userID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, userID, status)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: ctx, filter, skip, take
 func (_m *TaskRepository) Get(ctx context.Context, filter map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
 	ret := _m.Called(ctx, filter, skip, take)
@@ -119,6 +448,94 @@ func (_m *TaskRepository) GetByID(ctx context.Context, ID string) (interface{},
 	return r0, r1
 }
 
+// LastRank provides a mock function with given fields: ctx, userID, status
+func (_m *TaskRepository) LastRank(ctx context.Context, userID string, status string) (string, error) {
+	ret := _m.Called(ctx, userID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastRank")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, userID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, userID, status)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, deletedBefore
+func (_m *TaskRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]string, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []string); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, filter, text
+func (_m *TaskRepository) Search(ctx context.Context, filter models.TaskFilter, text string) ([]interface{}, error) {
+	ret := _m.Called(ctx, filter, text)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.TaskFilter, string) ([]interface{}, error)); ok {
+		return rf(ctx, filter, text)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.TaskFilter, string) []interface{}); ok {
+		r0 = rf(ctx, filter, text)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.TaskFilter, string) error); ok {
+		r1 = rf(ctx, filter, text)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, ID, entity
 func (_m *TaskRepository) Update(ctx context.Context, ID string, entity interface{}) error {
 	ret := _m.Called(ctx, ID, entity)
@@ -137,6 +554,54 @@ func (_m *TaskRepository) Update(ctx context.Context, ID string, entity interfac
 	return r0
 }
 
+// UpdateIfVersion provides a mock function with given fields: ctx, id, entity, version
+func (_m *TaskRepository) UpdateIfVersion(ctx context.Context, id string, entity interface{}, version int) error {
+	ret := _m.Called(ctx, id, entity, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateIfVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, int) error); ok {
+		r0 = rf(ctx, id, entity, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Watch provides a mock function with given fields: ctx, userID, resumeToken, maxAwaitTime
+func (_m *TaskRepository) Watch(ctx context.Context, userID string, resumeToken string, maxAwaitTime time.Duration) (ports.TaskChangeStream, error) {
+	ret := _m.Called(ctx, userID, resumeToken, maxAwaitTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 ports.TaskChangeStream
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (ports.TaskChangeStream, error)); ok {
+		return rf(ctx, userID, resumeToken, maxAwaitTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) ports.TaskChangeStream); ok {
+		r0 = rf(ctx, userID, resumeToken, maxAwaitTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ports.TaskChangeStream)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, userID, resumeToken, maxAwaitTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTaskRepository creates a new instance of TaskRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTaskRepository(t interface {
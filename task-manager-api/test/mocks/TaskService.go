@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	models "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/models"
+	ports "github.com/sergicanet9/go-microservices-demo/task-manager-api/core/ports"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +15,108 @@ type TaskService struct {
 	mock.Mock
 }
 
+// AddBlocker provides a mock function with given fields: ctx, userID, taskID, version, blocker
+func (_m *TaskService) AddBlocker(ctx context.Context, userID string, taskID string, version int, blocker models.AddBlockerReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, blocker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddBlocker")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.AddBlockerReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, blocker)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.AddBlockerReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, blocker)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.AddBlockerReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, blocker)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCollaborator provides a mock function with given fields: ctx, userID, taskID, collaborator, token
+func (_m *TaskService) AddCollaborator(ctx context.Context, userID string, taskID string, collaborator models.AddCollaboratorReq, token string) error {
+	ret := _m.Called(ctx, userID, taskID, collaborator, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.AddCollaboratorReq, string) error); ok {
+		r0 = rf(ctx, userID, taskID, collaborator, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Assign provides a mock function with given fields: ctx, userID, taskID, version, assignment, token
+func (_m *TaskService) Assign(ctx context.Context, userID string, taskID string, version int, assignment models.AssignTaskReq, token string) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, assignment, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Assign")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.AssignTaskReq, string) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, assignment, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.AssignTaskReq, string) int); ok {
+		r0 = rf(ctx, userID, taskID, version, assignment, token)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.AssignTaskReq, string) error); ok {
+		r1 = rf(ctx, userID, taskID, version, assignment, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Batch provides a mock function with given fields: ctx, userID, batch, token
+func (_m *TaskService) Batch(ctx context.Context, userID string, batch models.BatchTasksReq, token string) (models.BatchTasksResp, error) {
+	ret := _m.Called(ctx, userID, batch, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 models.BatchTasksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.BatchTasksReq, string) (models.BatchTasksResp, error)); ok {
+		return rf(ctx, userID, batch, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.BatchTasksReq, string) models.BatchTasksResp); ok {
+		r0 = rf(ctx, userID, batch, token)
+	} else {
+		r0 = ret.Get(0).(models.BatchTasksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.BatchTasksReq, string) error); ok {
+		r1 = rf(ctx, userID, batch, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, userID, task, token
 func (_m *TaskService) Create(ctx context.Context, userID string, task models.CreateTaskReq, token string) (models.CreateTaskResp, error) {
 	ret := _m.Called(ctx, userID, task, token)
@@ -42,17 +145,45 @@ func (_m *TaskService) Create(ctx context.Context, userID string, task models.Cr
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, userID, taskID
-func (_m *TaskService) Delete(ctx context.Context, userID string, taskID string) error {
-	ret := _m.Called(ctx, userID, taskID)
+// CreateSubtask provides a mock function with given fields: ctx, userID, parentID, task, token
+func (_m *TaskService) CreateSubtask(ctx context.Context, userID string, parentID string, task models.CreateTaskReq, token string) (models.CreateTaskResp, error) {
+	ret := _m.Called(ctx, userID, parentID, task, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubtask")
+	}
+
+	var r0 models.CreateTaskResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.CreateTaskReq, string) (models.CreateTaskResp, error)); ok {
+		return rf(ctx, userID, parentID, task, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.CreateTaskReq, string) models.CreateTaskResp); ok {
+		r0 = rf(ctx, userID, parentID, task, token)
+	} else {
+		r0 = ret.Get(0).(models.CreateTaskResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.CreateTaskReq, string) error); ok {
+		r1 = rf(ctx, userID, parentID, task, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, taskID, version, cascade
+func (_m *TaskService) Delete(ctx context.Context, userID string, taskID string, version int, cascade bool) error {
+	ret := _m.Called(ctx, userID, taskID, version, cascade)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, userID, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, bool) error); ok {
+		r0 = rf(ctx, userID, taskID, version, cascade)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,29 +191,613 @@ func (_m *TaskService) Delete(ctx context.Context, userID string, taskID string)
 	return r0
 }
 
-// GetByUserID provides a mock function with given fields: ctx, userID
-func (_m *TaskService) GetByUserID(ctx context.Context, userID string) ([]models.GetTaskResp, error) {
-	ret := _m.Called(ctx, userID)
+// Get provides a mock function with given fields: ctx, userID, taskID
+func (_m *TaskService) Get(ctx context.Context, userID string, taskID string) (models.GetTaskResp, error) {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 models.GetTaskResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.GetTaskResp, error)); ok {
+		return rf(ctx, userID, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.GetTaskResp); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		r0 = ret.Get(0).(models.GetTaskResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssigned provides a mock function with given fields: ctx, userID, req
+func (_m *TaskService) GetAssigned(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssigned")
+	}
+
+	var r0 models.GetTasksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) (models.GetTasksResp, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) models.GetTasksResp); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(models.GetTasksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.GetTasksReq) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID, req
+func (_m *TaskService) GetByUserID(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error) {
+	ret := _m.Called(ctx, userID, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByUserID")
 	}
 
-	var r0 []models.GetTaskResp
+	var r0 models.GetTasksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) (models.GetTasksResp, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) models.GetTasksResp); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(models.GetTasksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.GetTasksReq) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCollaborators provides a mock function with given fields: ctx, userID, taskID
+func (_m *TaskService) GetCollaborators(ctx context.Context, userID string, taskID string) ([]models.GetCollaboratorResp, error) {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollaborators")
+	}
+
+	var r0 []models.GetCollaboratorResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.GetTaskResp, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.GetCollaboratorResp, error)); ok {
+		return rf(ctx, userID, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.GetCollaboratorResp); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GetCollaboratorResp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGraph provides a mock function with given fields: ctx, userID, taskID
+func (_m *TaskService) GetGraph(ctx context.Context, userID string, taskID string) (models.GetTaskGraphResp, error) {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGraph")
+	}
+
+	var r0 models.GetTaskGraphResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.GetTaskGraphResp, error)); ok {
+		return rf(ctx, userID, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.GetTaskGraphResp); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		r0 = ret.Get(0).(models.GetTaskGraphResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: ctx, userID, taskID
+func (_m *TaskService) GetHistory(ctx context.Context, userID string, taskID string) ([]models.GetTaskHistoryResp, error) {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []models.GetTaskHistoryResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.GetTaskHistoryResp, error)); ok {
+		return rf(ctx, userID, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.GetTaskHistoryResp); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.GetTaskHistoryResp)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStats provides a mock function with given fields: ctx, userID
+func (_m *TaskService) GetStats(ctx context.Context, userID string) (models.GetTaskStatsResp, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 models.GetTaskStatsResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.GetTaskStatsResp, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []models.GetTaskResp); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.GetTaskStatsResp); ok {
 		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.GetTaskStatsResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: ctx, userID, taskID
+func (_m *TaskService) GetSubtasks(ctx context.Context, userID string, taskID string) ([]models.GetTaskResp, error) {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubtasks")
+	}
+
+	var r0 []models.GetTaskResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.GetTaskResp, error)); ok {
+		return rf(ctx, userID, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.GetTaskResp); ok {
+		r0 = rf(ctx, userID, taskID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.GetTaskResp)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, userID, req
+func (_m *TaskService) GetTrash(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrash")
+	}
+
+	var r0 models.GetTasksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) (models.GetTasksResp, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) models.GetTasksResp); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(models.GetTasksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.GetTasksReq) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MaterializeOccurrences provides a mock function with given fields: ctx
+func (_m *TaskService) MaterializeOccurrences(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaterializeOccurrences")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: ctx, userID, taskID, version, move
+func (_m *TaskService) Move(ctx context.Context, userID string, taskID string, version int, move models.MoveTaskReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, move)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.MoveTaskReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, move)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.MoveTaskReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, move)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.MoveTaskReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, move)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: ctx, userID, taskID, version, patch
+func (_m *TaskService) Patch(ctx context.Context, userID string, taskID string, version int, patch models.PatchTaskReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.PatchTaskReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.PatchTaskReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, patch)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.PatchTaskReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeTrash provides a mock function with given fields: ctx
+func (_m *TaskService) PurgeTrash(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrash")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebalanceRanks provides a mock function with given fields: ctx
+func (_m *TaskService) RebalanceRanks(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebalanceRanks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveBlocker provides a mock function with given fields: ctx, userID, taskID, version, blockerID
+func (_m *TaskService) RemoveBlocker(ctx context.Context, userID string, taskID string, version int, blockerID string) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveBlocker")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) int); ok {
+		r0 = rf(ctx, userID, taskID, version, blockerID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, string) error); ok {
+		r1 = rf(ctx, userID, taskID, version, blockerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveCollaborator provides a mock function with given fields: ctx, userID, taskID, collaboratorID
+func (_m *TaskService) RemoveCollaborator(ctx context.Context, userID string, taskID string, collaboratorID string) error {
+	ret := _m.Called(ctx, userID, taskID, collaboratorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveCollaborator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, taskID, collaboratorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderSubtasks provides a mock function with given fields: ctx, userID, taskID, version, reorder
+func (_m *TaskService) ReorderSubtasks(ctx context.Context, userID string, taskID string, version int, reorder models.ReorderSubtasksReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, reorder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderSubtasks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.ReorderSubtasksReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, reorder)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.ReorderSubtasksReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, reorder)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.ReorderSubtasksReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, reorder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, userID, taskID, version
+func (_m *TaskService) Restore(ctx context.Context, userID string, taskID string, version int) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (int, error)); ok {
+		return rf(ctx, userID, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) int); ok {
+		r0 = rf(ctx, userID, taskID, version)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userID, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, userID, req
+func (_m *TaskService) Search(ctx context.Context, userID string, req models.GetTasksReq) (models.GetTasksResp, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 models.GetTasksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) (models.GetTasksResp, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.GetTasksReq) models.GetTasksResp); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(models.GetTasksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.GetTasksReq) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stream provides a mock function with given fields: ctx, userID, lastEventID
+func (_m *TaskService) Stream(ctx context.Context, userID string, lastEventID string) (ports.TaskEventStream, error) {
+	ret := _m.Called(ctx, userID, lastEventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 ports.TaskEventStream
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (ports.TaskEventStream, error)); ok {
+		return rf(ctx, userID, lastEventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ports.TaskEventStream); ok {
+		r0 = rf(ctx, userID, lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ports.TaskEventStream)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, lastEventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transition provides a mock function with given fields: ctx, userID, taskID, version, transition
+func (_m *TaskService) Transition(ctx context.Context, userID string, taskID string, version int, transition models.TransitionTaskReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, transition)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.TransitionTaskReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, transition)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.TransitionTaskReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, transition)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.TransitionTaskReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, transition)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userID, taskID, version, task
+func (_m *TaskService) Update(ctx context.Context, userID string, taskID string, version int, task models.UpdateTaskReq) (int, error) {
+	ret := _m.Called(ctx, userID, taskID, version, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.UpdateTaskReq) (int, error)); ok {
+		return rf(ctx, userID, taskID, version, task)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, models.UpdateTaskReq) int); ok {
+		r0 = rf(ctx, userID, taskID, version, task)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, models.UpdateTaskReq) error); ok {
+		r1 = rf(ctx, userID, taskID, version, task)
 	} else {
 		r1 = ret.Error(1)
 	}
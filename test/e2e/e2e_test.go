@@ -167,16 +167,19 @@ func testGetTasks(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var respBody []map[string]interface{}
+	var respBody struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
 	json.NewDecoder(resp.Body).Decode(&respBody)
-	assert.NotEmpty(t, respBody)
-	assert.Equal(t, taskID, respBody[0]["id"])
+	assert.NotEmpty(t, respBody.Tasks)
+	assert.Equal(t, taskID, respBody.Tasks[0]["id"])
 }
 
 func testDeleteTask(t *testing.T) {
 	// Arrange
 	req, _ := http.NewRequest("DELETE", fmt.Sprintf("%s/tasks/%s", taskManagerAPIAddr, taskID), nil)
 	req.Header.Set("Authorization", "Bearer "+userToken)
+	req.Header.Set("If-Match", `"1"`)
 
 	// Act
 	resp, err := client.Do(req)